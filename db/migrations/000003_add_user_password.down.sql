@@ -0,0 +1,2 @@
+-- Drop hashed_password column from users table
+ALTER TABLE "users" DROP COLUMN IF EXISTS "hashed_password";
@@ -0,0 +1 @@
+ALTER TABLE "users" ADD COLUMN "hashed_password" varchar NOT NULL DEFAULT '';
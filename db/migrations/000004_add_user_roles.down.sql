@@ -0,0 +1,5 @@
+-- Convert role column back to free-form text
+ALTER TABLE "users" ALTER COLUMN "role" TYPE varchar(50) USING "role"::text;
+
+-- Drop user_role type
+DROP TYPE IF EXISTS "user_role";
@@ -0,0 +1,14 @@
+CREATE TYPE "user_role" AS ENUM (
+  'admin',
+  'manager',
+  'member',
+  'viewer'
+);
+
+-- Map free-form roles onto the permission model, unknown values become members
+ALTER TABLE "users" ALTER COLUMN "role" TYPE user_role USING (
+  CASE
+    WHEN lower("role") IN ('admin', 'manager', 'member', 'viewer') THEN lower("role")
+    ELSE 'member'
+  END
+)::user_role;
@@ -0,0 +1,5 @@
+-- Drop project_members table
+DROP TABLE IF EXISTS "project_members";
+
+-- Drop project_role type
+DROP TYPE IF EXISTS "project_role";
@@ -0,0 +1,27 @@
+CREATE TYPE "project_role" AS ENUM (
+  'manager',
+  'member',
+  'viewer'
+);
+
+CREATE TABLE "project_members" (
+  "project_id" BIGINT NOT NULL,
+  "user_id" BIGINT NOT NULL,
+  "role" project_role NOT NULL DEFAULT 'member',
+  "added_at" timestamp NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("project_id", "user_id")
+);
+
+CREATE INDEX ON "project_members" ("user_id");
+
+ALTER TABLE "project_members" ADD FOREIGN KEY ("project_id") REFERENCES "projects" ("id") ON DELETE CASCADE;
+ALTER TABLE "project_members" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- Existing managers and assignees keep access to their projects
+INSERT INTO "project_members" ("project_id", "user_id", "role")
+SELECT "id", "manager_id", 'manager' FROM "projects"
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "project_members" ("project_id", "user_id", "role")
+SELECT DISTINCT "project_id", "assignee_id", 'member' FROM "tasks"
+ON CONFLICT DO NOTHING;
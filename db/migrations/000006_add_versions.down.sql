@@ -0,0 +1,4 @@
+-- Drop version columns
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "projects" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "version";
@@ -0,0 +1,4 @@
+-- Every update increments the version, which clients send back in If-Match
+ALTER TABLE "users" ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE "projects" ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE "tasks" ADD COLUMN "version" BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,29 @@
+CREATE TYPE "task_status" AS ENUM (
+  'new',
+  'in_progress',
+  'completed'
+);
+
+-- Map custom statuses back onto the enum by their category
+ALTER TABLE "tasks" DROP CONSTRAINT IF EXISTS "tasks_project_id_status_fkey";
+ALTER TABLE "tasks" ADD COLUMN "status_enum" task_status;
+
+UPDATE "tasks" SET "status_enum" = (
+  CASE ws."category"
+    WHEN 'new' THEN 'new'
+    WHEN 'active' THEN 'in_progress'
+    ELSE 'completed'
+  END
+)::task_status
+FROM "workflow_statuses" ws
+WHERE ws."project_id" = "tasks"."project_id" AND ws."name" = "tasks"."status";
+
+ALTER TABLE "tasks" DROP COLUMN "status";
+ALTER TABLE "tasks" RENAME COLUMN "status_enum" TO "status";
+ALTER TABLE "tasks" ALTER COLUMN "status" SET NOT NULL;
+
+-- Drop workflow_statuses table
+DROP TABLE IF EXISTS "workflow_statuses";
+
+-- Drop status_category type
+DROP TYPE IF EXISTS "status_category";
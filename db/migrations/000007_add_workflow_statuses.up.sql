@@ -0,0 +1,28 @@
+CREATE TYPE "status_category" AS ENUM (
+  'new',
+  'active',
+  'done'
+);
+
+CREATE TABLE "workflow_statuses" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "project_id" BIGINT NOT NULL,
+  "name" varchar(50) NOT NULL,
+  "category" status_category NOT NULL,
+  "position" INT NOT NULL DEFAULT 0,
+  UNIQUE ("project_id", "name")
+);
+
+ALTER TABLE "workflow_statuses" ADD FOREIGN KEY ("project_id") REFERENCES "projects" ("id") ON DELETE CASCADE;
+
+-- Every existing project gets the statuses of the former task_status enum
+INSERT INTO "workflow_statuses" ("project_id", "name", "category", "position")
+SELECT "projects"."id", s."name", s."category"::status_category, s."position"
+FROM "projects"
+CROSS JOIN (VALUES ('new', 'new', 1), ('in_progress', 'active', 2), ('completed', 'done', 3)) AS s ("name", "category", "position");
+
+-- The status of a task must be one of the statuses of its project
+ALTER TABLE "tasks" ALTER COLUMN "status" TYPE varchar(50) USING "status"::text;
+ALTER TABLE "tasks" ADD FOREIGN KEY ("project_id", "status") REFERENCES "workflow_statuses" ("project_id", "name") ON UPDATE CASCADE;
+
+DROP TYPE IF EXISTS "task_status";
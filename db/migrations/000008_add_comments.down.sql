@@ -0,0 +1,2 @@
+-- Drop comments table
+DROP TABLE IF EXISTS "comments";
@@ -0,0 +1,18 @@
+CREATE TABLE "comments" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "task_id" BIGINT NOT NULL,
+  "author_id" BIGINT NOT NULL,
+  "parent_id" BIGINT,
+  "body" text NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  "updated_at" timestamp,
+  UNIQUE ("task_id", "id")
+);
+
+CREATE INDEX ON "comments" ("task_id", "created_at");
+
+ALTER TABLE "comments" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+ALTER TABLE "comments" ADD FOREIGN KEY ("author_id") REFERENCES "users" ("id") ON DELETE CASCADE;
+
+-- A reply belongs to the task of the comment it answers and goes away with it
+ALTER TABLE "comments" ADD FOREIGN KEY ("task_id", "parent_id") REFERENCES "comments" ("task_id", "id") ON DELETE CASCADE;
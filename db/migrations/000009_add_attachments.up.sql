@@ -0,0 +1,17 @@
+CREATE TABLE "attachments" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "task_id" BIGINT NOT NULL,
+  "uploader_id" BIGINT NOT NULL,
+  "filename" varchar(255) NOT NULL,
+  "content_type" varchar(255) NOT NULL,
+  "size" BIGINT NOT NULL,
+  "checksum" char(64) NOT NULL,
+  "storage_key" varchar(255) UNIQUE NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "attachments" ("task_id");
+CREATE INDEX ON "attachments" ("uploader_id");
+
+ALTER TABLE "attachments" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+ALTER TABLE "attachments" ADD FOREIGN KEY ("uploader_id") REFERENCES "users" ("id");
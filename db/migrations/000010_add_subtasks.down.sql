@@ -0,0 +1,3 @@
+-- Drop the parent reference with its constraints
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "parent_task_id";
+ALTER TABLE "tasks" DROP CONSTRAINT IF EXISTS "tasks_project_id_id_key";
@@ -0,0 +1,9 @@
+ALTER TABLE "tasks" ADD COLUMN "parent_task_id" BIGINT;
+
+CREATE INDEX ON "tasks" ("parent_task_id");
+
+-- A subtask belongs to the project of its parent. A task with subtasks can
+-- neither be deleted nor moved to another project.
+ALTER TABLE "tasks" ADD UNIQUE ("project_id", "id");
+ALTER TABLE "tasks" ADD FOREIGN KEY ("project_id", "parent_task_id") REFERENCES "tasks" ("project_id", "id");
+ALTER TABLE "tasks" ADD CHECK ("parent_task_id" <> "id");
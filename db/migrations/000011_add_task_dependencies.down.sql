@@ -0,0 +1,12 @@
+-- Drop the triggers maintaining the blocked flag
+DROP TRIGGER IF EXISTS "workflow_statuses_refresh_blocked" ON "workflow_statuses";
+DROP TRIGGER IF EXISTS "tasks_refresh_blocked" ON "tasks";
+DROP FUNCTION IF EXISTS "workflow_status_changed";
+DROP FUNCTION IF EXISTS "blocker_changed";
+
+-- Drop task_dependencies table with its trigger
+DROP TABLE IF EXISTS "task_dependencies";
+DROP FUNCTION IF EXISTS "dependency_changed";
+DROP FUNCTION IF EXISTS "refresh_blocked";
+
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "blocked";
@@ -0,0 +1,77 @@
+CREATE TABLE "task_dependencies" (
+  "blocker_id" BIGINT NOT NULL,
+  "blocked_id" BIGINT NOT NULL,
+  "created_at" timestamp NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("blocker_id", "blocked_id"),
+  CHECK ("blocker_id" <> "blocked_id")
+);
+
+CREATE INDEX ON "task_dependencies" ("blocked_id");
+
+ALTER TABLE "task_dependencies" ADD FOREIGN KEY ("blocker_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+ALTER TABLE "task_dependencies" ADD FOREIGN KEY ("blocked_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+-- A task is blocked while any of its blockers is not done. The flag is kept
+-- up to date by the triggers below so that every query returning tasks has it.
+ALTER TABLE "tasks" ADD COLUMN "blocked" boolean NOT NULL DEFAULT false;
+
+CREATE FUNCTION "refresh_blocked"(task_ids BIGINT[]) RETURNS void AS $$
+  UPDATE tasks t
+  SET blocked = EXISTS (
+    SELECT 1
+    FROM task_dependencies d
+    JOIN tasks b ON b.id = d.blocker_id
+    JOIN workflow_statuses ws ON ws.project_id = b.project_id AND ws.name = b.status
+    WHERE d.blocked_id = t.id AND ws.category <> 'done'
+  )
+  WHERE t.id = ANY(task_ids);
+$$ LANGUAGE sql;
+
+-- Adding or removing a dependency changes the blocked task
+CREATE FUNCTION "dependency_changed"() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    PERFORM refresh_blocked(ARRAY[OLD.blocked_id]);
+  ELSE
+    PERFORM refresh_blocked(ARRAY[NEW.blocked_id]);
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "task_dependencies_refresh_blocked"
+AFTER INSERT OR DELETE ON "task_dependencies"
+FOR EACH ROW EXECUTE FUNCTION dependency_changed();
+
+-- A blocker moving to or out of a done status changes the tasks it blocks
+CREATE FUNCTION "blocker_changed"() RETURNS trigger AS $$
+BEGIN
+  PERFORM refresh_blocked(ARRAY(SELECT blocked_id FROM task_dependencies WHERE blocker_id = NEW.id));
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "tasks_refresh_blocked"
+AFTER UPDATE OF status, project_id ON "tasks"
+FOR EACH ROW
+WHEN (OLD.status IS DISTINCT FROM NEW.status OR OLD.project_id IS DISTINCT FROM NEW.project_id)
+EXECUTE FUNCTION blocker_changed();
+
+-- So does a status of the workflow changing category
+CREATE FUNCTION "workflow_status_changed"() RETURNS trigger AS $$
+BEGIN
+  PERFORM refresh_blocked(ARRAY(
+    SELECT d.blocked_id
+    FROM task_dependencies d
+    JOIN tasks b ON b.id = d.blocker_id
+    WHERE b.project_id = NEW.project_id AND b.status = NEW.name
+  ));
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "workflow_statuses_refresh_blocked"
+AFTER UPDATE OF category ON "workflow_statuses"
+FOR EACH ROW
+WHEN (OLD.category IS DISTINCT FROM NEW.category)
+EXECUTE FUNCTION workflow_status_changed();
@@ -0,0 +1,3 @@
+-- Drop task_labels and labels tables
+DROP TABLE IF EXISTS "task_labels";
+DROP TABLE IF EXISTS "labels";
@@ -0,0 +1,19 @@
+CREATE TABLE "labels" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "project_id" BIGINT NOT NULL,
+  "name" varchar(50) NOT NULL,
+  "color" char(7) NOT NULL DEFAULT '#808080',
+  UNIQUE ("project_id", "name")
+);
+
+CREATE TABLE "task_labels" (
+  "task_id" BIGINT NOT NULL,
+  "label_id" BIGINT NOT NULL,
+  PRIMARY KEY ("task_id", "label_id")
+);
+
+CREATE INDEX ON "task_labels" ("label_id");
+
+ALTER TABLE "labels" ADD FOREIGN KEY ("project_id") REFERENCES "projects" ("id") ON DELETE CASCADE;
+ALTER TABLE "task_labels" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+ALTER TABLE "task_labels" ADD FOREIGN KEY ("label_id") REFERENCES "labels" ("id") ON DELETE CASCADE;
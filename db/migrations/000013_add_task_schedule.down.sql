@@ -0,0 +1,3 @@
+-- Drop the due date and estimate of tasks
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "estimate";
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "due_date";
@@ -0,0 +1,6 @@
+-- A task may have a due date and an estimate of its effort, in hours or
+-- story points as the team plans. Tasks without them keep NULL.
+ALTER TABLE "tasks" ADD COLUMN "due_date" date;
+ALTER TABLE "tasks" ADD COLUMN "estimate" double precision CHECK ("estimate" > 0);
+
+CREATE INDEX ON "tasks" ("due_date");
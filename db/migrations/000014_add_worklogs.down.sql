@@ -0,0 +1,2 @@
+-- Drop worklogs table
+DROP TABLE IF EXISTS "worklogs";
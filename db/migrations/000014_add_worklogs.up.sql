@@ -0,0 +1,20 @@
+-- Time logged against a task. The duration is in seconds and stays NULL
+-- while the entry is a running timer.
+CREATE TABLE "worklogs" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "task_id" BIGINT NOT NULL,
+  "user_id" BIGINT NOT NULL,
+  "started_at" timestamp NOT NULL,
+  "duration" BIGINT CHECK ("duration" >= 0),
+  "note" text NOT NULL DEFAULT '',
+  UNIQUE ("task_id", "id")
+);
+
+CREATE INDEX ON "worklogs" ("task_id", "started_at");
+CREATE INDEX ON "worklogs" ("user_id", "started_at");
+
+-- A user has at most one running timer
+CREATE UNIQUE INDEX "worklogs_running_timer_key" ON "worklogs" ("user_id") WHERE "duration" IS NULL;
+
+ALTER TABLE "worklogs" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+ALTER TABLE "worklogs" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE;
@@ -0,0 +1,8 @@
+-- Drop the sprint of tasks
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "sprint_id";
+
+-- Drop sprints table
+DROP TABLE IF EXISTS "sprints";
+
+-- Drop sprint_state type
+DROP TYPE IF EXISTS "sprint_state";
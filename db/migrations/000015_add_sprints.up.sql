@@ -0,0 +1,35 @@
+CREATE TYPE "sprint_state" AS ENUM (
+  'planned',
+  'active',
+  'closed'
+);
+
+-- The capacity is the work the team plans to get done, in the unit of the
+-- task estimates
+CREATE TABLE "sprints" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "project_id" BIGINT NOT NULL,
+  "name" varchar(100) NOT NULL,
+  "goal" text NOT NULL DEFAULT '',
+  "start_date" date NOT NULL,
+  "end_date" date NOT NULL,
+  "capacity" double precision CHECK ("capacity" > 0),
+  "state" sprint_state NOT NULL DEFAULT 'planned',
+  "closed_at" timestamp,
+  UNIQUE ("project_id", "id"),
+  CHECK ("end_date" >= "start_date")
+);
+
+-- A project runs at most one sprint at a time
+CREATE UNIQUE INDEX "sprints_active_key" ON "sprints" ("project_id") WHERE "state" = 'active';
+
+ALTER TABLE "sprints" ADD FOREIGN KEY ("project_id") REFERENCES "projects" ("id") ON DELETE CASCADE;
+
+-- A task belongs to at most one sprint of its project, tasks without a
+-- sprint are in the backlog. Deleting a sprint returns its tasks there,
+-- clearing only sprint_id needs PostgreSQL 15.
+ALTER TABLE "tasks" ADD COLUMN "sprint_id" BIGINT;
+
+CREATE INDEX ON "tasks" ("sprint_id");
+
+ALTER TABLE "tasks" ADD FOREIGN KEY ("project_id", "sprint_id") REFERENCES "sprints" ("project_id", "id") ON DELETE SET NULL ("sprint_id");
@@ -0,0 +1,5 @@
+-- Drop the milestone of tasks
+ALTER TABLE "tasks" DROP COLUMN IF EXISTS "milestone_id";
+
+-- Drop milestones table
+DROP TABLE IF EXISTS "milestones";
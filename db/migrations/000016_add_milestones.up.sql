@@ -0,0 +1,20 @@
+CREATE TABLE "milestones" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "project_id" BIGINT NOT NULL,
+  "title" varchar(100) NOT NULL,
+  "description" text NOT NULL DEFAULT '',
+  "due_date" date NOT NULL,
+  UNIQUE ("project_id", "id")
+);
+
+CREATE INDEX ON "milestones" ("project_id", "due_date");
+
+ALTER TABLE "milestones" ADD FOREIGN KEY ("project_id") REFERENCES "projects" ("id") ON DELETE CASCADE;
+
+-- A task counts towards at most one milestone of its project. Deleting a
+-- milestone unlinks its tasks, clearing only milestone_id needs PostgreSQL 15.
+ALTER TABLE "tasks" ADD COLUMN "milestone_id" BIGINT;
+
+CREATE INDEX ON "tasks" ("milestone_id");
+
+ALTER TABLE "tasks" ADD FOREIGN KEY ("project_id", "milestone_id") REFERENCES "milestones" ("project_id", "id") ON DELETE SET NULL ("milestone_id");
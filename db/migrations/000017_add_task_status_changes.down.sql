@@ -0,0 +1,7 @@
+-- Drop the triggers recording task statuses
+DROP TRIGGER IF EXISTS "tasks_record_status_changed" ON "tasks";
+DROP TRIGGER IF EXISTS "tasks_record_status_created" ON "tasks";
+DROP FUNCTION IF EXISTS "record_task_status";
+
+-- Drop task_status_changes table
+DROP TABLE IF EXISTS "task_status_changes";
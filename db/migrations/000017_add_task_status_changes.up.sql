@@ -0,0 +1,41 @@
+-- Every status a task goes through, with the category the status had at the
+-- time, so that reports can tell what was done on any day
+CREATE TABLE "task_status_changes" (
+  "id" BIGSERIAL PRIMARY KEY,
+  "task_id" BIGINT NOT NULL,
+  "status" varchar(50) NOT NULL,
+  "category" status_category NOT NULL,
+  "changed_at" timestamp NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "task_status_changes" ("task_id", "changed_at");
+
+ALTER TABLE "task_status_changes" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+
+-- Existing tasks start out with their current status, done ones from the day
+-- they were completed
+INSERT INTO "task_status_changes" ("task_id", "status", "category", "changed_at")
+SELECT t.id, t.status, ws.category,
+  CASE WHEN ws.category = 'done' THEN COALESCE(t.completion_date, t.creation_date) ELSE t.creation_date END
+FROM "tasks" t
+JOIN "workflow_statuses" ws ON ws.project_id = t.project_id AND ws.name = t.status;
+
+CREATE FUNCTION "record_task_status"() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO task_status_changes (task_id, status, category)
+  SELECT NEW.id, NEW.status, ws.category
+  FROM workflow_statuses ws
+  WHERE ws.project_id = NEW.project_id AND ws.name = NEW.status;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "tasks_record_status_created"
+AFTER INSERT ON "tasks"
+FOR EACH ROW EXECUTE FUNCTION record_task_status();
+
+CREATE TRIGGER "tasks_record_status_changed"
+AFTER UPDATE OF status, project_id ON "tasks"
+FOR EACH ROW
+WHEN (OLD.status IS DISTINCT FROM NEW.status OR OLD.project_id IS DISTINCT FROM NEW.project_id)
+EXECUTE FUNCTION record_task_status();
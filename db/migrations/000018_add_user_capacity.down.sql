@@ -0,0 +1,2 @@
+-- Drop the weekly capacity of users
+ALTER TABLE "users" DROP COLUMN IF EXISTS "weekly_capacity";
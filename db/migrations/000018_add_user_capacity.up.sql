@@ -0,0 +1,3 @@
+-- The work a user can take on per week, in the unit of the task estimates.
+-- Users without one are never reported as over-allocated.
+ALTER TABLE "users" ADD COLUMN "weekly_capacity" double precision CHECK ("weekly_capacity" > 0);
@@ -0,0 +1,7 @@
+-- Drop the project of task labels
+ALTER TABLE "task_labels" DROP COLUMN IF EXISTS "project_id";
+
+ALTER TABLE "task_labels" ADD FOREIGN KEY ("task_id") REFERENCES "tasks" ("id") ON DELETE CASCADE;
+ALTER TABLE "task_labels" ADD FOREIGN KEY ("label_id") REFERENCES "labels" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "labels" DROP CONSTRAINT IF EXISTS "labels_project_id_id_key";
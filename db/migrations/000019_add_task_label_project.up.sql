@@ -0,0 +1,22 @@
+-- A label can only be attached to tasks of its own project. Moving a task
+-- carries the new project over to its labels, which fails unless the labels
+-- of the old project were detached first.
+ALTER TABLE "labels" ADD UNIQUE ("project_id", "id");
+
+ALTER TABLE "task_labels" ADD COLUMN "project_id" BIGINT;
+
+UPDATE "task_labels" tl
+SET "project_id" = t."project_id"
+FROM "tasks" t
+WHERE t."id" = tl."task_id";
+
+DELETE FROM "task_labels" tl
+USING "labels" l
+WHERE l."id" = tl."label_id" AND l."project_id" <> tl."project_id";
+
+ALTER TABLE "task_labels" ALTER COLUMN "project_id" SET NOT NULL;
+
+ALTER TABLE "task_labels" DROP CONSTRAINT "task_labels_task_id_fkey";
+ALTER TABLE "task_labels" DROP CONSTRAINT "task_labels_label_id_fkey";
+ALTER TABLE "task_labels" ADD FOREIGN KEY ("project_id", "task_id") REFERENCES "tasks" ("project_id", "id") ON DELETE CASCADE ON UPDATE CASCADE;
+ALTER TABLE "task_labels" ADD FOREIGN KEY ("project_id", "label_id") REFERENCES "labels" ("project_id", "id") ON DELETE CASCADE;
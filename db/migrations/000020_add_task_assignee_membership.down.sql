@@ -0,0 +1,2 @@
+-- Drop the membership of task assignees
+ALTER TABLE "tasks" DROP CONSTRAINT IF EXISTS "tasks_project_id_assignee_id_fkey";
@@ -0,0 +1,7 @@
+-- A task is assigned to a member of its project. A member with assigned
+-- tasks cannot be removed from the project until the tasks are reassigned.
+INSERT INTO "project_members" ("project_id", "user_id", "role")
+SELECT DISTINCT "project_id", "assignee_id", 'member' FROM "tasks"
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE "tasks" ADD FOREIGN KEY ("project_id", "assignee_id") REFERENCES "project_members" ("project_id", "user_id");
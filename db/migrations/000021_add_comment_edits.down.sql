@@ -0,0 +1,2 @@
+-- Drop comment_edits table
+DROP TABLE IF EXISTS "comment_edits";
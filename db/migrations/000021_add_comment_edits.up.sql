@@ -0,0 +1,14 @@
+-- The times a comment was edited, updated_at only keeps the latest one
+CREATE TABLE "comment_edits" (
+  "comment_id" BIGINT NOT NULL,
+  "edited_at" timestamp NOT NULL
+);
+
+CREATE INDEX ON "comment_edits" ("comment_id", "edited_at");
+
+ALTER TABLE "comment_edits" ADD FOREIGN KEY ("comment_id") REFERENCES "comments" ("id") ON DELETE CASCADE;
+
+-- Comments edited before keep their latest edit
+INSERT INTO "comment_edits" ("comment_id", "edited_at")
+SELECT "id", "updated_at" FROM "comments"
+WHERE "updated_at" IS NOT NULL;
@@ -0,0 +1,20 @@
+-- name: ListTaskAttachments :many
+SELECT * FROM attachments
+WHERE task_id = $1
+ORDER BY created_at, id;
+
+-- name: GetAttachment :one
+SELECT * FROM attachments
+WHERE task_id = $1 AND id = $2 LIMIT 1;
+
+-- name: CreateAttachment :one
+INSERT INTO attachments (
+    task_id, uploader_id, filename, content_type, size, checksum, storage_key
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: DeleteAttachment :exec
+DELETE FROM attachments
+WHERE task_id = $1 AND id = $2;
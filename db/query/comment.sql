@@ -0,0 +1,53 @@
+-- name: ListTaskComments :many
+SELECT c.id, c.task_id, c.author_id, c.parent_id, c.body, c.created_at, c.updated_at, u.full_name AS author_name
+FROM comments c
+JOIN users u ON u.id = c.author_id
+WHERE c.task_id = $1
+ORDER BY c.created_at, c.id;
+
+-- name: GetComment :one
+SELECT * FROM comments
+WHERE task_id = $1 AND id = $2 LIMIT 1;
+
+-- name: CountTaskComments :one
+SELECT count(*) FROM comments
+WHERE task_id = $1;
+
+-- name: CreateComment :one
+INSERT INTO comments (
+    task_id, author_id, parent_id, body
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: UpdateComment :one
+UPDATE comments
+SET
+    body = $3,
+    updated_at = now()
+WHERE task_id = $1 AND id = $2
+RETURNING *;
+
+-- name: AddCommentEdit :exec
+INSERT INTO comment_edits (
+    comment_id, edited_at
+) VALUES (
+    $1, $2
+);
+
+-- name: ListCommentEdits :many
+SELECT edited_at FROM comment_edits
+WHERE comment_id = $1
+ORDER BY edited_at;
+
+-- name: ListTaskCommentEdits :many
+SELECT e.comment_id, e.edited_at
+FROM comment_edits e
+JOIN comments c ON c.id = e.comment_id
+WHERE c.task_id = $1
+ORDER BY e.comment_id, e.edited_at;
+
+-- name: DeleteComment :exec
+DELETE FROM comments
+WHERE task_id = $1 AND id = $2;
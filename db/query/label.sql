@@ -0,0 +1,52 @@
+-- name: ListLabels :many
+SELECT * FROM labels
+WHERE project_id = $1
+ORDER BY name;
+
+-- name: GetLabel :one
+SELECT * FROM labels
+WHERE project_id = $1 AND id = $2 LIMIT 1;
+
+-- name: CreateLabel :one
+INSERT INTO labels (
+    project_id, name, color
+) VALUES (
+    $1, $2, $3
+)
+RETURNING *;
+
+-- name: UpdateLabel :one
+UPDATE labels
+SET
+    name = $3,
+    color = $4
+WHERE project_id = $1 AND id = $2
+RETURNING *;
+
+-- name: DeleteLabel :exec
+DELETE FROM labels
+WHERE project_id = $1 AND id = $2;
+
+-- name: ListTaskLabels :many
+SELECT labels.*
+FROM task_labels tl
+JOIN labels ON labels.id = tl.label_id
+WHERE tl.task_id = $1
+ORDER BY labels.name;
+
+-- name: AttachLabel :exec
+INSERT INTO task_labels (
+    task_id, label_id, project_id
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: DetachLabel :execrows
+DELETE FROM task_labels
+WHERE task_id = $1 AND label_id = $2;
+
+-- name: DetachForeignLabels :exec
+-- Removes the labels of other projects from a task that moves
+DELETE FROM task_labels
+WHERE task_id = $1 AND project_id <> $2;
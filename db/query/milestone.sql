@@ -0,0 +1,59 @@
+-- name: ListMilestones :many
+-- Lists the milestones of a project with the share of their tasks in a done
+-- status, a milestone without tasks is at 0 percent
+SELECT sqlc.embed(milestones),
+    count(t.id) AS tasks,
+    count(t.id) FILTER (WHERE ws.category = 'done') AS completed,
+    COALESCE(floor(100 * count(t.id) FILTER (WHERE ws.category = 'done') / NULLIF(count(t.id), 0)), 0)::int AS percent
+FROM milestones
+LEFT JOIN tasks t ON t.milestone_id = milestones.id
+LEFT JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE milestones.project_id = $1
+GROUP BY milestones.id
+ORDER BY milestones.due_date, milestones.id;
+
+-- name: GetMilestoneSummary :one
+SELECT sqlc.embed(milestones),
+    count(t.id) AS tasks,
+    count(t.id) FILTER (WHERE ws.category = 'done') AS completed,
+    COALESCE(floor(100 * count(t.id) FILTER (WHERE ws.category = 'done') / NULLIF(count(t.id), 0)), 0)::int AS percent
+FROM milestones
+LEFT JOIN tasks t ON t.milestone_id = milestones.id
+LEFT JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE milestones.project_id = $1 AND milestones.id = $2
+GROUP BY milestones.id;
+
+-- name: CountMilestoneTasksByStatus :many
+-- Counts the tasks of the milestones of a project per status, in workflow
+-- order. Statuses without tasks are left out.
+SELECT t.milestone_id::bigint AS milestone_id, t.status, ws.category, count(*) AS tasks
+FROM tasks t
+JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE t.project_id = $1 AND t.milestone_id IS NOT NULL
+GROUP BY t.milestone_id, t.status, ws.category, ws.position
+ORDER BY t.milestone_id, ws.position, t.status;
+
+-- name: GetMilestone :one
+SELECT * FROM milestones
+WHERE project_id = $1 AND id = $2 LIMIT 1;
+
+-- name: CreateMilestone :one
+INSERT INTO milestones (
+    project_id, title, description, due_date
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: UpdateMilestone :one
+UPDATE milestones
+SET
+    title = $3,
+    description = $4,
+    due_date = $5
+WHERE project_id = $1 AND id = $2
+RETURNING *;
+
+-- name: DeleteMilestone :exec
+DELETE FROM milestones
+WHERE project_id = $1 AND id = $2;
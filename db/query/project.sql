@@ -2,10 +2,6 @@
 SELECT * FROM projects
 WHERE id = $1 LIMIT 1;
 
--- name: ListProjects :many
-SELECT * FROM projects
-ORDER BY start_date ASC;
-
 -- name: CreateProject :one
 INSERT INTO projects (
     name, description, start_date, end_date, manager_id
@@ -21,25 +17,23 @@ SET
     description = $3,
     start_date = $4,
     end_date = $5,
-    manager_id = $6
-WHERE id = $1
+    manager_id = $6,
+    version = version + 1
+WHERE id = $1 AND version = $7
 RETURNING *;
 
 -- name: DeleteProject :exec
 DELETE FROM projects
 WHERE id = $1;
 
--- name: GetProjectTasks :many
-SELECT * FROM tasks
-WHERE project_id = $1
-ORDER BY creation_date ASC;
-
--- name: SearchProjectsByTitle :many
-SELECT * FROM projects
-WHERE name ILIKE '%' || $1 || '%'
-ORDER BY start_date ASC;
-
--- name: SearchProjectsByManager :many
-SELECT * FROM projects
-WHERE manager_id = $1
-ORDER BY start_date ASC;
+-- name: PatchProject :one
+UPDATE projects
+SET
+    name = COALESCE(sqlc.narg(name), name),
+    description = COALESCE(sqlc.narg(description), description),
+    start_date = COALESCE(sqlc.narg(start_date), start_date),
+    end_date = COALESCE(sqlc.narg(end_date), end_date),
+    manager_id = COALESCE(sqlc.narg(manager_id), manager_id),
+    version = version + 1
+WHERE id = sqlc.arg(id) AND version = sqlc.arg(version)
+RETURNING *;
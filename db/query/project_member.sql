@@ -0,0 +1,30 @@
+-- name: AddProjectMember :one
+INSERT INTO project_members (
+    project_id, user_id, role
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (project_id, user_id) DO UPDATE
+SET role = EXCLUDED.role
+RETURNING *;
+
+-- name: GetProjectMember :one
+SELECT * FROM project_members
+WHERE project_id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListProjectMembers :many
+SELECT pm.project_id, pm.user_id, pm.role, pm.added_at, u.full_name, u.email
+FROM project_members pm
+JOIN users u ON u.id = pm.user_id
+WHERE pm.project_id = $1
+ORDER BY u.full_name ASC;
+
+-- name: RemoveProjectMember :execrows
+DELETE FROM project_members
+WHERE project_id = $1 AND user_id = $2;
+
+-- name: DemoteProjectManager :exec
+-- Makes a former project manager a regular member
+UPDATE project_members
+SET role = 'member'
+WHERE project_id = $1 AND user_id = $2 AND role = 'manager';
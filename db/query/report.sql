@@ -0,0 +1,107 @@
+-- name: GetBurndown :many
+-- Counts, for every day of the range, the tasks of the project or sprint that
+-- existed at the end of the day and those of them in a done status then. The
+-- status history decides, tasks from before it fall back to completion_date.
+WITH days AS (
+    SELECT generate_series(sqlc.arg(from_date)::date, sqlc.arg(to_date)::date, interval '1 day')::date AS day
+), scope AS (
+    SELECT t.id, t.creation_date, t.completion_date, t.estimate
+    FROM tasks t
+    WHERE t.project_id = sqlc.arg(project_id)
+        AND (sqlc.narg(sprint_id)::bigint IS NULL OR t.sprint_id = sqlc.narg(sprint_id)::bigint)
+)
+SELECT
+    days.day,
+    count(s.id) AS tasks,
+    count(s.id) FILTER (WHERE s.done) AS completed,
+    COALESCE(sum(s.estimate), 0)::double precision AS estimate,
+    COALESCE(sum(s.estimate) FILTER (WHERE s.done), 0)::double precision AS completed_estimate
+FROM days
+LEFT JOIN LATERAL (
+    SELECT scope.id, scope.estimate,
+        COALESCE(
+            (SELECT h.category = 'done'
+             FROM task_status_changes h
+             WHERE h.task_id = scope.id AND h.changed_at < days.day + 1
+             ORDER BY h.changed_at DESC, h.id DESC
+             LIMIT 1),
+            scope.completion_date < days.day + 1,
+            false
+        ) AS done
+    FROM scope
+    WHERE scope.creation_date < days.day + 1
+) s ON true
+GROUP BY days.day
+ORDER BY days.day;
+
+-- name: GetProjectTaskTotals :one
+-- Counts the tasks of a project, those in a done status and the open ones
+-- past their due date
+SELECT
+    count(*) AS tasks,
+    count(*) FILTER (WHERE ws.category = 'done') AS completed,
+    count(*) FILTER (WHERE ws.category <> 'done' AND t.due_date < CURRENT_DATE) AS overdue
+FROM tasks t
+JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE t.project_id = $1;
+
+-- name: CountProjectTasksByStatus :many
+-- Counts the tasks of a project in every status of its workflow, in workflow
+-- order
+SELECT ws.name AS status, ws.category, count(t.id) AS tasks
+FROM workflow_statuses ws
+LEFT JOIN tasks t ON t.project_id = ws.project_id AND t.status = ws.name
+WHERE ws.project_id = $1
+GROUP BY ws.id
+ORDER BY ws.position, ws.name;
+
+-- name: CountProjectTasksByPriority :many
+SELECT priority, count(*) AS tasks
+FROM tasks
+WHERE project_id = $1
+GROUP BY priority;
+
+-- name: ListTopAssignees :many
+-- Lists the assignees of a project with the most open tasks, ties go to the
+-- larger sum of estimates
+SELECT t.assignee_id, u.full_name, count(*) AS open_tasks,
+    COALESCE(sum(t.estimate), 0)::double precision AS open_estimate
+FROM tasks t
+JOIN users u ON u.id = t.assignee_id
+JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE t.project_id = sqlc.arg(project_id) AND ws.category <> 'done'
+GROUP BY t.assignee_id, u.full_name
+ORDER BY open_tasks DESC, open_estimate DESC, t.assignee_id
+LIMIT sqlc.arg(top);
+
+-- name: ListWorkload :many
+-- Sums the open tasks assigned to every user, or to the one user_id names,
+-- across the projects the viewer is a member of. Viewers other than admins
+-- only see themselves and the members of their projects. The busiest users
+-- come first.
+SELECT u.id AS user_id, u.full_name, u.weekly_capacity,
+    count(o.id) AS open_tasks,
+    count(o.id) FILTER (WHERE o.priority = 'high') AS high,
+    count(o.id) FILTER (WHERE o.priority = 'medium') AS medium,
+    count(o.id) FILTER (WHERE o.priority = 'low') AS low,
+    count(o.id) FILTER (WHERE o.estimate IS NULL) AS unestimated,
+    COALESCE(sum(o.estimate), 0)::double precision AS open_estimate,
+    count(DISTINCT o.project_id) AS projects
+FROM users u
+LEFT JOIN (
+    SELECT t.id, t.assignee_id, t.project_id, t.priority, t.estimate
+    FROM tasks t
+    JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+    WHERE ws.category <> 'done'
+        AND (sqlc.arg(viewer_is_admin)::boolean
+            OR t.project_id IN (SELECT pm.project_id FROM project_members pm WHERE pm.user_id = sqlc.arg(viewer_id)))
+) o ON o.assignee_id = u.id
+WHERE (sqlc.narg(user_id)::bigint IS NULL OR u.id = sqlc.narg(user_id)::bigint)
+    AND (sqlc.arg(viewer_is_admin)::boolean
+        OR u.id = sqlc.arg(viewer_id)
+        OR u.id IN (
+            SELECT pm.user_id FROM project_members pm
+            WHERE pm.project_id IN (SELECT own.project_id FROM project_members own WHERE own.user_id = sqlc.arg(viewer_id))
+        ))
+GROUP BY u.id
+ORDER BY open_estimate DESC, open_tasks DESC, u.id;
@@ -0,0 +1,84 @@
+-- name: ListSprints :many
+SELECT sqlc.embed(sprints),
+    count(t.id) AS tasks,
+    count(t.id) FILTER (WHERE ws.category = 'done') AS completed,
+    COALESCE(sum(t.estimate), 0)::double precision AS committed
+FROM sprints
+LEFT JOIN tasks t ON t.sprint_id = sprints.id
+LEFT JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE sprints.project_id = $1
+GROUP BY sprints.id
+ORDER BY sprints.start_date, sprints.id;
+
+-- name: GetSprint :one
+SELECT * FROM sprints
+WHERE project_id = $1 AND id = $2 LIMIT 1;
+
+-- name: GetSprintSummary :one
+SELECT sqlc.embed(sprints),
+    count(t.id) AS tasks,
+    count(t.id) FILTER (WHERE ws.category = 'done') AS completed,
+    COALESCE(sum(t.estimate), 0)::double precision AS committed
+FROM sprints
+LEFT JOIN tasks t ON t.sprint_id = sprints.id
+LEFT JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE sprints.project_id = $1 AND sprints.id = $2
+GROUP BY sprints.id;
+
+-- name: CreateSprint :one
+INSERT INTO sprints (
+    project_id, name, goal, start_date, end_date, capacity
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: UpdateSprint :one
+UPDATE sprints
+SET
+    name = $3,
+    goal = $4,
+    start_date = $5,
+    end_date = $6,
+    capacity = $7
+WHERE project_id = $1 AND id = $2
+RETURNING *;
+
+-- name: DeleteSprint :exec
+DELETE FROM sprints
+WHERE project_id = $1 AND id = $2;
+
+-- name: SetSprintState :one
+-- Moves the sprint to the state when it is in the expected one, closing it
+-- records the time
+UPDATE sprints
+SET
+    state = sqlc.arg(state),
+    closed_at = CASE WHEN sqlc.arg(state) = 'closed'::sprint_state THEN now() END
+WHERE project_id = sqlc.arg(project_id) AND id = sqlc.arg(id) AND state = sqlc.arg(expected)
+RETURNING *;
+
+-- name: ListSprintTasks :many
+SELECT * FROM tasks
+WHERE sprint_id = sqlc.arg(sprint_id)::bigint
+ORDER BY creation_date, id;
+
+-- name: SetTaskSprint :one
+UPDATE tasks
+SET
+    sprint_id = sqlc.narg(sprint_id),
+    version = version + 1
+WHERE project_id = sqlc.arg(project_id) AND id = sqlc.arg(id)
+RETURNING *;
+
+-- name: MoveUnfinishedTasks :execrows
+-- Moves the tasks of a sprint that are not done to another sprint of the
+-- project, or to the backlog when to_sprint_id is null
+UPDATE tasks
+SET
+    sprint_id = sqlc.narg(to_sprint_id),
+    version = version + 1
+WHERE tasks.sprint_id = sqlc.arg(from_sprint_id)::bigint
+    AND (tasks.project_id, tasks.status) IN (
+        SELECT ws.project_id, ws.name FROM workflow_statuses ws WHERE ws.category <> 'done'
+    );
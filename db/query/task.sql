@@ -2,15 +2,11 @@
 SELECT * FROM tasks
 WHERE id = $1 LIMIT 1;
 
--- name: ListTasks :many
-SELECT * FROM tasks
-ORDER BY creation_date ASC;
-
 -- name: CreateTask :one
 INSERT INTO tasks (
-    title, description, priority, status, assignee_id, project_id, completion_date
+    title, description, priority, status, assignee_id, project_id, completion_date, parent_task_id, due_date, estimate, milestone_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
 RETURNING *;
 
@@ -23,35 +19,117 @@ SET
     status = $5,
     assignee_id = $6,
     project_id = $7,
-    completion_date = $8
-WHERE id = $1
+    completion_date = $8,
+    parent_task_id = $10,
+    due_date = $11,
+    estimate = $12,
+    sprint_id = CASE WHEN project_id = $7 THEN sprint_id END,
+    milestone_id = $13,
+    version = version + 1
+WHERE id = $1 AND version = $9
 RETURNING *;
 
 -- name: DeleteTask :exec
 DELETE FROM tasks
 WHERE id = $1;
 
--- name: SearchTasksByTitle :many
-SELECT * FROM tasks
-WHERE title ILIKE '%' || $1 || '%'
-ORDER BY creation_date ASC;
+-- name: PatchTask :one
+UPDATE tasks
+SET
+    title = COALESCE(sqlc.narg(title), title),
+    description = COALESCE(sqlc.narg(description), description),
+    priority = COALESCE(sqlc.narg(priority), priority),
+    status = COALESCE(sqlc.narg(status), status),
+    assignee_id = COALESCE(sqlc.narg(assignee_id), assignee_id),
+    project_id = COALESCE(sqlc.narg(project_id), project_id),
+    parent_task_id = CASE WHEN sqlc.arg(set_parent_task_id)::boolean
+        THEN sqlc.narg(parent_task_id)::bigint
+        ELSE parent_task_id
+    END,
+    completion_date = CASE WHEN sqlc.arg(set_completion_date)::boolean
+        THEN sqlc.narg(completion_date)::timestamp
+        ELSE completion_date
+    END,
+    due_date = CASE WHEN sqlc.arg(set_due_date)::boolean
+        THEN sqlc.narg(due_date)::date
+        ELSE due_date
+    END,
+    estimate = CASE WHEN sqlc.arg(set_estimate)::boolean
+        THEN sqlc.narg(estimate)::double precision
+        ELSE estimate
+    END,
+    sprint_id = CASE WHEN project_id = COALESCE(sqlc.narg(project_id), project_id) THEN sprint_id END,
+    milestone_id = CASE
+        WHEN sqlc.arg(set_milestone_id)::boolean THEN sqlc.narg(milestone_id)::bigint
+        WHEN project_id = COALESCE(sqlc.narg(project_id), project_id) THEN milestone_id
+    END,
+    version = version + 1
+WHERE id = sqlc.arg(id) AND version = sqlc.arg(version)
+RETURNING *;
 
--- name: SearchTasksByStatus :many
+-- name: ListSubtasks :many
 SELECT * FROM tasks
-WHERE status = $1
-ORDER BY creation_date ASC;
+WHERE parent_task_id = sqlc.arg(id)::bigint
+ORDER BY creation_date, id;
 
--- name: SearchTasksByPriority :many
-SELECT * FROM tasks
-WHERE priority = $1
-ORDER BY creation_date ASC;
+-- name: ListTaskSubtree :many
+WITH RECURSIVE subtree AS (
+    SELECT t.id, 1 AS depth, ARRAY[t.id] AS path
+    FROM tasks t
+    WHERE t.parent_task_id = sqlc.arg(id)::bigint
+    UNION ALL
+    SELECT t.id, s.depth + 1, s.path || t.id
+    FROM tasks t
+    JOIN subtree s ON t.parent_task_id = s.id
+    WHERE NOT t.id = ANY(s.path)
+)
+SELECT sqlc.embed(tasks), subtree.depth::int AS depth, ws.category
+FROM subtree
+JOIN tasks ON tasks.id = subtree.id
+JOIN workflow_statuses ws ON ws.project_id = tasks.project_id AND ws.name = tasks.status
+ORDER BY subtree.depth, tasks.creation_date, tasks.id;
 
--- name: SearchTasksByAssignee :many
-SELECT * FROM tasks
-WHERE assignee_id = $1
-ORDER BY creation_date ASC;
+-- name: GetTaskRollup :one
+WITH RECURSIVE subtree AS (
+    SELECT t.id, ARRAY[t.id] AS path
+    FROM tasks t
+    WHERE t.parent_task_id = sqlc.arg(id)::bigint
+    UNION ALL
+    SELECT t.id, s.path || t.id
+    FROM tasks t
+    JOIN subtree s ON t.parent_task_id = s.id
+    WHERE NOT t.id = ANY(s.path)
+)
+SELECT
+    count(*) AS total,
+    count(*) FILTER (WHERE ws.category = 'done') AS done
+FROM subtree
+JOIN tasks ON tasks.id = subtree.id
+JOIN workflow_statuses ws ON ws.project_id = tasks.project_id AND ws.name = tasks.status;
 
--- name: SearchTasksByProject :many
-SELECT * FROM tasks
-WHERE project_id = $1
-ORDER BY creation_date ASC;
\ No newline at end of file
+-- name: IsTaskAncestor :one
+-- Reports whether ancestor_id is the task itself or one of its ancestors
+WITH RECURSIVE ancestors AS (
+    SELECT t.id, t.parent_task_id
+    FROM tasks t
+    WHERE t.id = sqlc.arg(task_id)::bigint
+    UNION
+    SELECT t.id, t.parent_task_id
+    FROM tasks t
+    JOIN ancestors a ON t.id = a.parent_task_id
+)
+SELECT EXISTS (
+    SELECT 1 FROM ancestors WHERE id = sqlc.arg(ancestor_id)::bigint
+);
+
+-- name: CountDueOutsideDates :one
+-- Counts the tasks and milestones of a project due before the start date or
+-- after the end date
+SELECT
+    COUNT(*) FILTER (WHERE due.due_date < sqlc.arg(start_date)::date) AS before_start,
+    COUNT(*) FILTER (WHERE due.due_date > sqlc.arg(end_date)::date) AS after_end
+FROM (
+    SELECT t.due_date FROM tasks t WHERE t.project_id = sqlc.arg(project_id) AND t.due_date IS NOT NULL
+    UNION ALL
+    SELECT m.due_date FROM milestones m WHERE m.project_id = sqlc.arg(project_id)
+) due;
@@ -0,0 +1,45 @@
+-- name: AddTaskDependency :exec
+INSERT INTO task_dependencies (
+    blocker_id, blocked_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveTaskDependency :execrows
+DELETE FROM task_dependencies
+WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: ListTaskBlockers :many
+SELECT tasks.*
+FROM task_dependencies d
+JOIN tasks ON tasks.id = d.blocker_id
+WHERE d.blocked_id = $1
+ORDER BY d.created_at, tasks.id;
+
+-- name: ListBlockedTasks :many
+SELECT tasks.*
+FROM task_dependencies d
+JOIN tasks ON tasks.id = d.blocked_id
+WHERE d.blocker_id = $1
+ORDER BY d.created_at, tasks.id;
+
+-- name: DependsOn :one
+-- Reports whether the task waits on the blocker, directly or through other tasks
+WITH RECURSIVE upstream AS (
+    SELECT d.blocker_id
+    FROM task_dependencies d
+    WHERE d.blocked_id = sqlc.arg(task_id)::bigint
+    UNION
+    SELECT d.blocker_id
+    FROM task_dependencies d
+    JOIN upstream u ON d.blocked_id = u.blocker_id
+)
+SELECT EXISTS (
+    SELECT 1 FROM upstream WHERE blocker_id = sqlc.arg(blocker_id)::bigint
+);
+
+-- name: LockProjectDependencies :exec
+-- Serializes changes to the dependencies of a project until the transaction
+-- ends, so that concurrent additions cannot close a cycle together
+SELECT pg_advisory_xact_lock(sqlc.arg(project_id)::bigint);
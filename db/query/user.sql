@@ -2,15 +2,15 @@
 SELECT * FROM users
 WHERE id = $1 LIMIT 1;
 
--- name: ListUsers :many
+-- name: GetUserByEmail :one
 SELECT * FROM users
-ORDER BY full_name ASC;
+WHERE email = $1 LIMIT 1;
 
 -- name: CreateUser :one
 INSERT INTO users (
-    full_name, email, role
+    full_name, email, role, hashed_password
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 )
 RETURNING *;
 
@@ -20,25 +20,25 @@ SET
     full_name = $2,
     email = $3,
     role = $4,
-    registration_date = $5
-WHERE id = $1
+    registration_date = $5,
+    version = version + 1
+WHERE id = $1 AND version = $6
 RETURNING *;
 
 -- name: DeleteUser :exec
 DELETE FROM users
 WHERE id = $1;
 
--- name: GetUserTasks :many
-SELECT * FROM tasks
-WHERE assignee_id = $1
-ORDER BY creation_date ASC;
-
--- name: SearchUsersByName :many
-SELECT * FROM users
-WHERE full_name ILIKE '%' || $1 || '%'
-ORDER BY full_name ASC;
-
--- name: SearchUsersByEmail :many
-SELECT * FROM users
-WHERE email ILIKE '%' || $1 || '%'
-ORDER BY email ASC;
+-- name: PatchUser :one
+UPDATE users
+SET
+    full_name = COALESCE(sqlc.narg(full_name), full_name),
+    email = COALESCE(sqlc.narg(email), email),
+    role = COALESCE(sqlc.narg(role), role),
+    weekly_capacity = CASE WHEN sqlc.arg(set_weekly_capacity)::boolean
+        THEN sqlc.narg(weekly_capacity)::double precision
+        ELSE weekly_capacity
+    END,
+    version = version + 1
+WHERE id = sqlc.arg(id) AND version = sqlc.arg(version)
+RETURNING *;
@@ -0,0 +1,40 @@
+-- name: ListWorkflowStatuses :many
+SELECT * FROM workflow_statuses
+WHERE project_id = $1
+ORDER BY position, id;
+
+-- name: GetWorkflowStatus :one
+SELECT * FROM workflow_statuses
+WHERE project_id = $1 AND name = $2 LIMIT 1;
+
+-- name: GetWorkflowStatusByID :one
+SELECT * FROM workflow_statuses
+WHERE id = $1 LIMIT 1;
+
+-- name: CreateWorkflowStatus :one
+INSERT INTO workflow_statuses (
+    project_id, name, category, position
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: CreateDefaultWorkflowStatuses :exec
+INSERT INTO workflow_statuses (project_id, name, category, position)
+VALUES
+    ($1, 'new', 'new', 1),
+    ($1, 'in_progress', 'active', 2),
+    ($1, 'completed', 'done', 3);
+
+-- name: UpdateWorkflowStatus :one
+UPDATE workflow_statuses
+SET
+    name = $2,
+    category = $3,
+    position = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWorkflowStatus :exec
+DELETE FROM workflow_statuses
+WHERE id = $1;
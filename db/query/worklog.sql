@@ -0,0 +1,87 @@
+-- name: ListTaskWorklogs :many
+SELECT * FROM worklogs
+WHERE task_id = $1
+ORDER BY started_at, id;
+
+-- name: GetWorklog :one
+SELECT * FROM worklogs
+WHERE task_id = $1 AND id = $2 LIMIT 1;
+
+-- name: CreateWorklog :one
+INSERT INTO worklogs (
+    task_id, user_id, started_at, duration, note
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: DeleteWorklog :exec
+DELETE FROM worklogs
+WHERE task_id = $1 AND id = $2;
+
+-- name: StartTimer :one
+-- Worklogs are stored in UTC like the entries logged by hand
+INSERT INTO worklogs (
+    task_id, user_id, started_at, note
+) VALUES (
+    $1, $2, now() AT TIME ZONE 'UTC', $3
+)
+RETURNING *;
+
+-- name: StopTimer :one
+-- Stops the running timer of the user on the task, recording the elapsed
+-- whole seconds as its duration
+UPDATE worklogs
+SET duration = GREATEST(0, floor(extract(epoch FROM (now() AT TIME ZONE 'UTC') - started_at)))::bigint
+WHERE task_id = $1 AND user_id = $2 AND duration IS NULL
+RETURNING *;
+
+-- name: SumTaskWorklogsByUser :many
+SELECT w.user_id, u.full_name, sum(w.duration)::bigint AS seconds, count(*) AS entries
+FROM worklogs w
+JOIN users u ON u.id = w.user_id
+WHERE w.task_id = sqlc.arg(task_id)
+    AND w.duration IS NOT NULL
+    AND (sqlc.narg(started_from)::timestamp IS NULL OR w.started_at >= sqlc.narg(started_from))
+    AND (sqlc.narg(started_to)::timestamp IS NULL OR w.started_at <= sqlc.narg(started_to))
+GROUP BY w.user_id, u.full_name
+ORDER BY seconds DESC, w.user_id;
+
+-- name: SumProjectWorklogsByTask :many
+SELECT t.id AS task_id, t.title, sum(w.duration)::bigint AS seconds, count(*) AS entries
+FROM worklogs w
+JOIN tasks t ON t.id = w.task_id
+WHERE t.project_id = sqlc.arg(project_id)
+    AND w.duration IS NOT NULL
+    AND (sqlc.narg(started_from)::timestamp IS NULL OR w.started_at >= sqlc.narg(started_from))
+    AND (sqlc.narg(started_to)::timestamp IS NULL OR w.started_at <= sqlc.narg(started_to))
+GROUP BY t.id, t.title
+ORDER BY seconds DESC, t.id;
+
+-- name: SumProjectWorklogsByUser :many
+SELECT w.user_id, u.full_name, sum(w.duration)::bigint AS seconds, count(*) AS entries
+FROM worklogs w
+JOIN tasks t ON t.id = w.task_id
+JOIN users u ON u.id = w.user_id
+WHERE t.project_id = sqlc.arg(project_id)
+    AND w.duration IS NOT NULL
+    AND (sqlc.narg(started_from)::timestamp IS NULL OR w.started_at >= sqlc.narg(started_from))
+    AND (sqlc.narg(started_to)::timestamp IS NULL OR w.started_at <= sqlc.narg(started_to))
+GROUP BY w.user_id, u.full_name
+ORDER BY seconds DESC, w.user_id;
+
+-- name: SumUserWorklogsByProject :many
+-- Only counts the projects the viewer is a member of, unless the viewer is
+-- an admin
+SELECT p.id AS project_id, p.name, sum(w.duration)::bigint AS seconds, count(*) AS entries
+FROM worklogs w
+JOIN tasks t ON t.id = w.task_id
+JOIN projects p ON p.id = t.project_id
+WHERE w.user_id = sqlc.arg(user_id)
+    AND w.duration IS NOT NULL
+    AND (sqlc.narg(started_from)::timestamp IS NULL OR w.started_at >= sqlc.narg(started_from))
+    AND (sqlc.narg(started_to)::timestamp IS NULL OR w.started_at <= sqlc.narg(started_to))
+    AND (sqlc.arg(viewer_is_admin)::boolean
+        OR p.id IN (SELECT pm.project_id FROM project_members pm WHERE pm.user_id = sqlc.arg(viewer_id)))
+GROUP BY p.id, p.name
+ORDER BY seconds DESC, p.id;
@@ -0,0 +1,135 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: attachment.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createAttachment = `-- name: CreateAttachment :one
+INSERT INTO attachments (
+    task_id, uploader_id, filename, content_type, size, checksum, storage_key
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING id, task_id, uploader_id, filename, content_type, size, checksum, storage_key, created_at
+`
+
+type CreateAttachmentParams struct {
+	TaskID      int64  `json:"task_id"`
+	UploaderID  int64  `json:"uploader_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Checksum    string `json:"checksum"`
+	StorageKey  string `json:"storage_key"`
+}
+
+func (q *Queries) CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error) {
+	row := q.db.QueryRowContext(ctx, createAttachment,
+		arg.TaskID,
+		arg.UploaderID,
+		arg.Filename,
+		arg.ContentType,
+		arg.Size,
+		arg.Checksum,
+		arg.StorageKey,
+	)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.UploaderID,
+		&i.Filename,
+		&i.ContentType,
+		&i.Size,
+		&i.Checksum,
+		&i.StorageKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteAttachment = `-- name: DeleteAttachment :exec
+DELETE FROM attachments
+WHERE task_id = $1 AND id = $2
+`
+
+type DeleteAttachmentParams struct {
+	TaskID int64 `json:"task_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) DeleteAttachment(ctx context.Context, arg DeleteAttachmentParams) error {
+	_, err := q.db.ExecContext(ctx, deleteAttachment, arg.TaskID, arg.ID)
+	return err
+}
+
+const getAttachment = `-- name: GetAttachment :one
+SELECT id, task_id, uploader_id, filename, content_type, size, checksum, storage_key, created_at FROM attachments
+WHERE task_id = $1 AND id = $2 LIMIT 1
+`
+
+type GetAttachmentParams struct {
+	TaskID int64 `json:"task_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetAttachment(ctx context.Context, arg GetAttachmentParams) (Attachment, error) {
+	row := q.db.QueryRowContext(ctx, getAttachment, arg.TaskID, arg.ID)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.UploaderID,
+		&i.Filename,
+		&i.ContentType,
+		&i.Size,
+		&i.Checksum,
+		&i.StorageKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listTaskAttachments = `-- name: ListTaskAttachments :many
+SELECT id, task_id, uploader_id, filename, content_type, size, checksum, storage_key, created_at FROM attachments
+WHERE task_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListTaskAttachments(ctx context.Context, taskID int64) ([]Attachment, error) {
+	rows, err := q.db.QueryContext(ctx, listTaskAttachments, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Attachment{}
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskID,
+			&i.UploaderID,
+			&i.Filename,
+			&i.ContentType,
+			&i.Size,
+			&i.Checksum,
+			&i.StorageKey,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var attachmentColumns = []string{"id", "task_id", "uploader_id", "filename", "content_type", "size", "checksum", "storage_key", "created_at"}
+
+func TestCreateAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+	params := CreateAttachmentParams{
+		TaskID:      1,
+		UploaderID:  2,
+		Filename:    "design.pdf",
+		ContentType: "application/pdf",
+		Size:        2048,
+		Checksum:    "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		StorageKey:  "tasks/1/5e2bf3c1",
+	}
+
+	rows := sqlmock.NewRows(attachmentColumns).
+		AddRow(7, 1, 2, "design.pdf", "application/pdf", 2048, params.Checksum, "tasks/1/5e2bf3c1", now)
+
+	mock.ExpectQuery("INSERT INTO attachments").
+		WithArgs(int64(1), int64(2), "design.pdf", "application/pdf", int64(2048), params.Checksum, "tasks/1/5e2bf3c1").
+		WillReturnRows(rows)
+
+	attachment, err := queries.CreateAttachment(context.Background(), params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), attachment.ID)
+	assert.Equal(t, int64(2048), attachment.Size)
+	assert.Equal(t, "tasks/1/5e2bf3c1", attachment.StorageKey)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetAttachment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows(attachmentColumns).
+		AddRow(7, 1, 2, "notes.txt", "text/plain; charset=utf-8", 12, "abc", "tasks/1/77aa", time.Now())
+
+	mock.ExpectQuery("SELECT (.+) FROM attachments WHERE task_id = \\$1 AND id = \\$2 LIMIT 1").
+		WithArgs(int64(1), int64(7)).
+		WillReturnRows(rows)
+
+	attachment, err := queries.GetAttachment(context.Background(), GetAttachmentParams{TaskID: 1, ID: 7})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "notes.txt", attachment.Filename)
+	assert.Equal(t, "text/plain; charset=utf-8", attachment.ContentType)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,259 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: comment.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const addCommentEdit = `-- name: AddCommentEdit :exec
+INSERT INTO comment_edits (
+    comment_id, edited_at
+) VALUES (
+    $1, $2
+)
+`
+
+type AddCommentEditParams struct {
+	CommentID int64     `json:"comment_id"`
+	EditedAt  time.Time `json:"edited_at"`
+}
+
+func (q *Queries) AddCommentEdit(ctx context.Context, arg AddCommentEditParams) error {
+	_, err := q.db.ExecContext(ctx, addCommentEdit, arg.CommentID, arg.EditedAt)
+	return err
+}
+
+const countTaskComments = `-- name: CountTaskComments :one
+SELECT count(*) FROM comments
+WHERE task_id = $1
+`
+
+func (q *Queries) CountTaskComments(ctx context.Context, taskID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTaskComments, taskID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createComment = `-- name: CreateComment :one
+INSERT INTO comments (
+    task_id, author_id, parent_id, body
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, task_id, author_id, parent_id, body, created_at, updated_at
+`
+
+type CreateCommentParams struct {
+	TaskID   int64         `json:"task_id"`
+	AuthorID int64         `json:"author_id"`
+	ParentID sql.NullInt64 `json:"parent_id"`
+	Body     string        `json:"body"`
+}
+
+func (q *Queries) CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error) {
+	row := q.db.QueryRowContext(ctx, createComment,
+		arg.TaskID,
+		arg.AuthorID,
+		arg.ParentID,
+		arg.Body,
+	)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.AuthorID,
+		&i.ParentID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteComment = `-- name: DeleteComment :exec
+DELETE FROM comments
+WHERE task_id = $1 AND id = $2
+`
+
+type DeleteCommentParams struct {
+	TaskID int64 `json:"task_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) DeleteComment(ctx context.Context, arg DeleteCommentParams) error {
+	_, err := q.db.ExecContext(ctx, deleteComment, arg.TaskID, arg.ID)
+	return err
+}
+
+const getComment = `-- name: GetComment :one
+SELECT id, task_id, author_id, parent_id, body, created_at, updated_at FROM comments
+WHERE task_id = $1 AND id = $2 LIMIT 1
+`
+
+type GetCommentParams struct {
+	TaskID int64 `json:"task_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetComment(ctx context.Context, arg GetCommentParams) (Comment, error) {
+	row := q.db.QueryRowContext(ctx, getComment, arg.TaskID, arg.ID)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.AuthorID,
+		&i.ParentID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listCommentEdits = `-- name: ListCommentEdits :many
+SELECT edited_at FROM comment_edits
+WHERE comment_id = $1
+ORDER BY edited_at
+`
+
+func (q *Queries) ListCommentEdits(ctx context.Context, commentID int64) ([]time.Time, error) {
+	rows, err := q.db.QueryContext(ctx, listCommentEdits, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []time.Time{}
+	for rows.Next() {
+		var edited_at time.Time
+		if err := rows.Scan(&edited_at); err != nil {
+			return nil, err
+		}
+		items = append(items, edited_at)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskCommentEdits = `-- name: ListTaskCommentEdits :many
+SELECT e.comment_id, e.edited_at
+FROM comment_edits e
+JOIN comments c ON c.id = e.comment_id
+WHERE c.task_id = $1
+ORDER BY e.comment_id, e.edited_at
+`
+
+func (q *Queries) ListTaskCommentEdits(ctx context.Context, taskID int64) ([]CommentEdit, error) {
+	rows, err := q.db.QueryContext(ctx, listTaskCommentEdits, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CommentEdit{}
+	for rows.Next() {
+		var i CommentEdit
+		if err := rows.Scan(&i.CommentID, &i.EditedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskComments = `-- name: ListTaskComments :many
+SELECT c.id, c.task_id, c.author_id, c.parent_id, c.body, c.created_at, c.updated_at, u.full_name AS author_name
+FROM comments c
+JOIN users u ON u.id = c.author_id
+WHERE c.task_id = $1
+ORDER BY c.created_at, c.id
+`
+
+type ListTaskCommentsRow struct {
+	ID         int64         `json:"id"`
+	TaskID     int64         `json:"task_id"`
+	AuthorID   int64         `json:"author_id"`
+	ParentID   sql.NullInt64 `json:"parent_id"`
+	Body       string        `json:"body"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  sql.NullTime  `json:"updated_at"`
+	AuthorName string        `json:"author_name"`
+}
+
+func (q *Queries) ListTaskComments(ctx context.Context, taskID int64) ([]ListTaskCommentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTaskComments, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTaskCommentsRow{}
+	for rows.Next() {
+		var i ListTaskCommentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskID,
+			&i.AuthorID,
+			&i.ParentID,
+			&i.Body,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AuthorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateComment = `-- name: UpdateComment :one
+UPDATE comments
+SET
+    body = $3,
+    updated_at = now()
+WHERE task_id = $1 AND id = $2
+RETURNING id, task_id, author_id, parent_id, body, created_at, updated_at
+`
+
+type UpdateCommentParams struct {
+	TaskID int64  `json:"task_id"`
+	ID     int64  `json:"id"`
+	Body   string `json:"body"`
+}
+
+func (q *Queries) UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comment, error) {
+	row := q.db.QueryRowContext(ctx, updateComment, arg.TaskID, arg.ID, arg.Body)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.AuthorID,
+		&i.ParentID,
+		&i.Body,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateComment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+	parentID := sql.NullInt64{Int64: 3, Valid: true}
+
+	rows := sqlmock.NewRows([]string{"id", "task_id", "author_id", "parent_id", "body", "created_at", "updated_at"}).
+		AddRow(4, 1, 2, 3, "Agreed, let's ship it", now, nil)
+
+	mock.ExpectQuery("INSERT INTO comments").
+		WithArgs(int64(1), int64(2), parentID, "Agreed, let's ship it").
+		WillReturnRows(rows)
+
+	comment, err := queries.CreateComment(context.Background(), CreateCommentParams{
+		TaskID:   1,
+		AuthorID: 2,
+		ParentID: parentID,
+		Body:     "Agreed, let's ship it",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), comment.ID)
+	assert.Equal(t, parentID, comment.ParentID)
+	assert.False(t, comment.UpdatedAt.Valid)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestListTaskComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "task_id", "author_id", "parent_id", "body", "created_at", "updated_at", "author_name"}).
+		AddRow(3, 1, 5, nil, "Ready for review?", now, now.Add(time.Minute), "Jane Doe").
+		AddRow(4, 1, 2, 3, "Agreed, let's ship it", now.Add(time.Hour), nil, "John Smith")
+
+	mock.ExpectQuery("SELECT (.+) FROM comments c JOIN users u ON u.id = c.author_id WHERE c.task_id = \\$1 ORDER BY c.created_at, c.id").
+		WithArgs(int64(1)).
+		WillReturnRows(rows)
+
+	comments, err := queries.ListTaskComments(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "Jane Doe", comments[0].AuthorName)
+	assert.True(t, comments[0].UpdatedAt.Valid)
+	assert.Equal(t, int64(3), comments[1].ParentID.Int64)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUpdateComment(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "task_id", "author_id", "parent_id", "body", "created_at", "updated_at"}).
+		AddRow(3, 1, 5, nil, "Ready for review now", now, now.Add(time.Minute))
+
+	mock.ExpectQuery("UPDATE comments SET body = \\$3, updated_at = now\\(\\) WHERE task_id = \\$1 AND id = \\$2").
+		WithArgs(int64(1), int64(3), "Ready for review now").
+		WillReturnRows(rows)
+
+	comment, err := queries.UpdateComment(context.Background(), UpdateCommentParams{TaskID: 1, ID: 3, Body: "Ready for review now"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Ready for review now", comment.Body)
+	assert.True(t, comment.UpdatedAt.Valid)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
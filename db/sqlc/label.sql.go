@@ -0,0 +1,231 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: label.sql
+
+package db
+
+import (
+	"context"
+)
+
+const attachLabel = `-- name: AttachLabel :exec
+INSERT INTO task_labels (
+    task_id, label_id, project_id
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT DO NOTHING
+`
+
+type AttachLabelParams struct {
+	TaskID    int64 `json:"task_id"`
+	LabelID   int64 `json:"label_id"`
+	ProjectID int64 `json:"project_id"`
+}
+
+func (q *Queries) AttachLabel(ctx context.Context, arg AttachLabelParams) error {
+	_, err := q.db.ExecContext(ctx, attachLabel, arg.TaskID, arg.LabelID, arg.ProjectID)
+	return err
+}
+
+const createLabel = `-- name: CreateLabel :one
+INSERT INTO labels (
+    project_id, name, color
+) VALUES (
+    $1, $2, $3
+)
+RETURNING id, project_id, name, color
+`
+
+type CreateLabelParams struct {
+	ProjectID int64  `json:"project_id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+}
+
+func (q *Queries) CreateLabel(ctx context.Context, arg CreateLabelParams) (Label, error) {
+	row := q.db.QueryRowContext(ctx, createLabel, arg.ProjectID, arg.Name, arg.Color)
+	var i Label
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Color,
+	)
+	return i, err
+}
+
+const deleteLabel = `-- name: DeleteLabel :exec
+DELETE FROM labels
+WHERE project_id = $1 AND id = $2
+`
+
+type DeleteLabelParams struct {
+	ProjectID int64 `json:"project_id"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) DeleteLabel(ctx context.Context, arg DeleteLabelParams) error {
+	_, err := q.db.ExecContext(ctx, deleteLabel, arg.ProjectID, arg.ID)
+	return err
+}
+
+const detachForeignLabels = `-- name: DetachForeignLabels :exec
+DELETE FROM task_labels
+WHERE task_id = $1 AND project_id <> $2
+`
+
+type DetachForeignLabelsParams struct {
+	TaskID    int64 `json:"task_id"`
+	ProjectID int64 `json:"project_id"`
+}
+
+// Removes the labels of other projects from a task that moves
+func (q *Queries) DetachForeignLabels(ctx context.Context, arg DetachForeignLabelsParams) error {
+	_, err := q.db.ExecContext(ctx, detachForeignLabels, arg.TaskID, arg.ProjectID)
+	return err
+}
+
+const detachLabel = `-- name: DetachLabel :execrows
+DELETE FROM task_labels
+WHERE task_id = $1 AND label_id = $2
+`
+
+type DetachLabelParams struct {
+	TaskID  int64 `json:"task_id"`
+	LabelID int64 `json:"label_id"`
+}
+
+func (q *Queries) DetachLabel(ctx context.Context, arg DetachLabelParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, detachLabel, arg.TaskID, arg.LabelID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getLabel = `-- name: GetLabel :one
+SELECT id, project_id, name, color FROM labels
+WHERE project_id = $1 AND id = $2 LIMIT 1
+`
+
+type GetLabelParams struct {
+	ProjectID int64 `json:"project_id"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) GetLabel(ctx context.Context, arg GetLabelParams) (Label, error) {
+	row := q.db.QueryRowContext(ctx, getLabel, arg.ProjectID, arg.ID)
+	var i Label
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Color,
+	)
+	return i, err
+}
+
+const listLabels = `-- name: ListLabels :many
+SELECT id, project_id, name, color FROM labels
+WHERE project_id = $1
+ORDER BY name
+`
+
+func (q *Queries) ListLabels(ctx context.Context, projectID int64) ([]Label, error) {
+	rows, err := q.db.QueryContext(ctx, listLabels, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Label{}
+	for rows.Next() {
+		var i Label
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Name,
+			&i.Color,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskLabels = `-- name: ListTaskLabels :many
+SELECT labels.id, labels.project_id, labels.name, labels.color
+FROM task_labels tl
+JOIN labels ON labels.id = tl.label_id
+WHERE tl.task_id = $1
+ORDER BY labels.name
+`
+
+func (q *Queries) ListTaskLabels(ctx context.Context, taskID int64) ([]Label, error) {
+	rows, err := q.db.QueryContext(ctx, listTaskLabels, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Label{}
+	for rows.Next() {
+		var i Label
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Name,
+			&i.Color,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateLabel = `-- name: UpdateLabel :one
+UPDATE labels
+SET
+    name = $3,
+    color = $4
+WHERE project_id = $1 AND id = $2
+RETURNING id, project_id, name, color
+`
+
+type UpdateLabelParams struct {
+	ProjectID int64  `json:"project_id"`
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+}
+
+func (q *Queries) UpdateLabel(ctx context.Context, arg UpdateLabelParams) (Label, error) {
+	row := q.db.QueryRowContext(ctx, updateLabel,
+		arg.ProjectID,
+		arg.ID,
+		arg.Name,
+		arg.Color,
+	)
+	var i Label
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Color,
+	)
+	return i, err
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateLabel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"id", "project_id", "name", "color"}).
+		AddRow(1, 1, "bug", "#d73a4a")
+
+	mock.ExpectQuery("INSERT INTO labels (.+) RETURNING (.+)").
+		WithArgs(int64(1), "bug", "#d73a4a").
+		WillReturnRows(rows)
+
+	label, err := queries.CreateLabel(context.Background(), CreateLabelParams{ProjectID: 1, Name: "bug", Color: "#d73a4a"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bug", label.Name)
+	assert.Equal(t, "#d73a4a", label.Color)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestListTaskLabels(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"id", "project_id", "name", "color"}).
+		AddRow(1, 1, "bug", "#d73a4a").
+		AddRow(2, 1, "urgent", "#808080")
+
+	mock.ExpectQuery("SELECT (.+) FROM task_labels tl JOIN labels ON labels.id = tl.label_id WHERE tl.task_id = \\$1").
+		WithArgs(int64(3)).
+		WillReturnRows(rows)
+
+	labels, err := queries.ListTaskLabels(context.Background(), 3)
+
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+	assert.Equal(t, "urgent", labels[1].Name)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDetachLabel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	mock.ExpectExec("DELETE FROM task_labels WHERE task_id = \\$1 AND label_id = \\$2").
+		WithArgs(int64(3), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	detached, err := queries.DetachLabel(context.Background(), DetachLabelParams{TaskID: 3, LabelID: 1})
+
+	assert.NoError(t, err)
+	assert.Zero(t, detached)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListMilestones(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	due := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "project_id", "title", "description", "due_date", "tasks", "completed", "percent"}).
+		AddRow(1, 2, "Beta", "", due, 3, 1, 33).
+		AddRow(2, 2, "Launch", "Public release", due.AddDate(0, 1, 0), 0, 0, 0)
+
+	mock.ExpectQuery("SELECT (.+) FROM milestones LEFT JOIN tasks t ON t.milestone_id = milestones.id (.+) WHERE milestones.project_id = \\$1 GROUP BY milestones.id ORDER BY milestones.due_date, milestones.id").
+		WithArgs(int64(2)).
+		WillReturnRows(rows)
+
+	milestones, err := queries.ListMilestones(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, milestones, 2)
+	assert.Equal(t, "Beta", milestones[0].Milestone.Title)
+	assert.Equal(t, int64(3), milestones[0].Tasks)
+	assert.Equal(t, int32(33), milestones[0].Percent)
+	assert.Equal(t, int32(0), milestones[1].Percent)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCountMilestoneTasksByStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"milestone_id", "status", "category", "tasks"}).
+		AddRow(1, "new", StatusCategoryNew, 2).
+		AddRow(1, "completed", StatusCategoryDone, 1)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks t JOIN workflow_statuses ws (.+) WHERE t.project_id = \\$1 AND t.milestone_id IS NOT NULL GROUP BY (.+) ORDER BY t.milestone_id, ws.position, t.status").
+		WithArgs(int64(2)).
+		WillReturnRows(rows)
+
+	counts, err := queries.CountMilestoneTasksByStatus(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []CountMilestoneTasksByStatusRow{
+		{MilestoneID: 1, Status: "new", Category: StatusCategoryNew, Tasks: 2},
+		{MilestoneID: 1, Status: "completed", Category: StatusCategoryDone, Tasks: 1},
+	}, counts)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
@@ -11,6 +11,189 @@ import (
 	"time"
 )
 
+type ProjectRole string
+
+const (
+	ProjectRoleManager ProjectRole = "manager"
+	ProjectRoleMember  ProjectRole = "member"
+	ProjectRoleViewer  ProjectRole = "viewer"
+)
+
+func (e *ProjectRole) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProjectRole(s)
+	case string:
+		*e = ProjectRole(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProjectRole: %T", src)
+	}
+	return nil
+}
+
+type NullProjectRole struct {
+	ProjectRole ProjectRole `json:"project_role"`
+	Valid       bool        `json:"valid"` // Valid is true if ProjectRole is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProjectRole) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProjectRole, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProjectRole.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProjectRole) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProjectRole), nil
+}
+
+func (e ProjectRole) Valid() bool {
+	switch e {
+	case ProjectRoleManager,
+		ProjectRoleMember,
+		ProjectRoleViewer:
+		return true
+	}
+	return false
+}
+
+func AllProjectRoleValues() []ProjectRole {
+	return []ProjectRole{
+		ProjectRoleManager,
+		ProjectRoleMember,
+		ProjectRoleViewer,
+	}
+}
+
+type SprintState string
+
+const (
+	SprintStatePlanned SprintState = "planned"
+	SprintStateActive  SprintState = "active"
+	SprintStateClosed  SprintState = "closed"
+)
+
+func (e *SprintState) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = SprintState(s)
+	case string:
+		*e = SprintState(s)
+	default:
+		return fmt.Errorf("unsupported scan type for SprintState: %T", src)
+	}
+	return nil
+}
+
+type NullSprintState struct {
+	SprintState SprintState `json:"sprint_state"`
+	Valid       bool        `json:"valid"` // Valid is true if SprintState is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullSprintState) Scan(value interface{}) error {
+	if value == nil {
+		ns.SprintState, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.SprintState.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullSprintState) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.SprintState), nil
+}
+
+func (e SprintState) Valid() bool {
+	switch e {
+	case SprintStatePlanned,
+		SprintStateActive,
+		SprintStateClosed:
+		return true
+	}
+	return false
+}
+
+func AllSprintStateValues() []SprintState {
+	return []SprintState{
+		SprintStatePlanned,
+		SprintStateActive,
+		SprintStateClosed,
+	}
+}
+
+type StatusCategory string
+
+const (
+	StatusCategoryNew    StatusCategory = "new"
+	StatusCategoryActive StatusCategory = "active"
+	StatusCategoryDone   StatusCategory = "done"
+)
+
+func (e *StatusCategory) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = StatusCategory(s)
+	case string:
+		*e = StatusCategory(s)
+	default:
+		return fmt.Errorf("unsupported scan type for StatusCategory: %T", src)
+	}
+	return nil
+}
+
+type NullStatusCategory struct {
+	StatusCategory StatusCategory `json:"status_category"`
+	Valid          bool           `json:"valid"` // Valid is true if StatusCategory is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullStatusCategory) Scan(value interface{}) error {
+	if value == nil {
+		ns.StatusCategory, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.StatusCategory.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullStatusCategory) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.StatusCategory), nil
+}
+
+func (e StatusCategory) Valid() bool {
+	switch e {
+	case StatusCategoryNew,
+		StatusCategoryActive,
+		StatusCategoryDone:
+		return true
+	}
+	return false
+}
+
+func AllStatusCategoryValues() []StatusCategory {
+	return []StatusCategory{
+		StatusCategoryNew,
+		StatusCategoryActive,
+		StatusCategoryDone,
+	}
+}
+
 type TaskPriority string
 
 const (
@@ -54,47 +237,128 @@ func (ns NullTaskPriority) Value() (driver.Value, error) {
 	return string(ns.TaskPriority), nil
 }
 
-type TaskStatus string
+func (e TaskPriority) Valid() bool {
+	switch e {
+	case TaskPriorityLow,
+		TaskPriorityMedium,
+		TaskPriorityHigh:
+		return true
+	}
+	return false
+}
+
+func AllTaskPriorityValues() []TaskPriority {
+	return []TaskPriority{
+		TaskPriorityLow,
+		TaskPriorityMedium,
+		TaskPriorityHigh,
+	}
+}
+
+type UserRole string
 
 const (
-	TaskStatusNew        TaskStatus = "new"
-	TaskStatusInProgress TaskStatus = "in_progress"
-	TaskStatusCompleted  TaskStatus = "completed"
+	UserRoleAdmin   UserRole = "admin"
+	UserRoleManager UserRole = "manager"
+	UserRoleMember  UserRole = "member"
+	UserRoleViewer  UserRole = "viewer"
 )
 
-func (e *TaskStatus) Scan(src interface{}) error {
+func (e *UserRole) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = TaskStatus(s)
+		*e = UserRole(s)
 	case string:
-		*e = TaskStatus(s)
+		*e = UserRole(s)
 	default:
-		return fmt.Errorf("unsupported scan type for TaskStatus: %T", src)
+		return fmt.Errorf("unsupported scan type for UserRole: %T", src)
 	}
 	return nil
 }
 
-type NullTaskStatus struct {
-	TaskStatus TaskStatus `json:"task_status"`
-	Valid      bool       `json:"valid"` // Valid is true if TaskStatus is not NULL
+type NullUserRole struct {
+	UserRole UserRole `json:"user_role"`
+	Valid    bool     `json:"valid"` // Valid is true if UserRole is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullTaskStatus) Scan(value interface{}) error {
+func (ns *NullUserRole) Scan(value interface{}) error {
 	if value == nil {
-		ns.TaskStatus, ns.Valid = "", false
+		ns.UserRole, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.TaskStatus.Scan(value)
+	return ns.UserRole.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullTaskStatus) Value() (driver.Value, error) {
+func (ns NullUserRole) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.TaskStatus), nil
+	return string(ns.UserRole), nil
+}
+
+func (e UserRole) Valid() bool {
+	switch e {
+	case UserRoleAdmin,
+		UserRoleManager,
+		UserRoleMember,
+		UserRoleViewer:
+		return true
+	}
+	return false
+}
+
+func AllUserRoleValues() []UserRole {
+	return []UserRole{
+		UserRoleAdmin,
+		UserRoleManager,
+		UserRoleMember,
+		UserRoleViewer,
+	}
+}
+
+type Attachment struct {
+	ID          int64     `json:"id"`
+	TaskID      int64     `json:"task_id"`
+	UploaderID  int64     `json:"uploader_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	StorageKey  string    `json:"storage_key"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Comment struct {
+	ID        int64         `json:"id"`
+	TaskID    int64         `json:"task_id"`
+	AuthorID  int64         `json:"author_id"`
+	ParentID  sql.NullInt64 `json:"parent_id"`
+	Body      string        `json:"body"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt sql.NullTime  `json:"updated_at"`
+}
+
+type CommentEdit struct {
+	CommentID int64     `json:"comment_id"`
+	EditedAt  time.Time `json:"edited_at"`
+}
+
+type Label struct {
+	ID        int64  `json:"id"`
+	ProjectID int64  `json:"project_id"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+}
+
+type Milestone struct {
+	ID          int64     `json:"id"`
+	ProjectID   int64     `json:"project_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	DueDate     time.Time `json:"due_date"`
 }
 
 type Project struct {
@@ -104,24 +368,91 @@ type Project struct {
 	StartDate   time.Time `json:"start_date"`
 	EndDate     time.Time `json:"end_date"`
 	ManagerID   int64     `json:"manager_id"`
+	Version     int64     `json:"version"`
+}
+
+type ProjectMember struct {
+	ProjectID int64       `json:"project_id"`
+	UserID    int64       `json:"user_id"`
+	Role      ProjectRole `json:"role"`
+	AddedAt   time.Time   `json:"added_at"`
+}
+
+type Sprint struct {
+	ID        int64           `json:"id"`
+	ProjectID int64           `json:"project_id"`
+	Name      string          `json:"name"`
+	Goal      string          `json:"goal"`
+	StartDate time.Time       `json:"start_date"`
+	EndDate   time.Time       `json:"end_date"`
+	Capacity  sql.NullFloat64 `json:"capacity"`
+	State     SprintState     `json:"state"`
+	ClosedAt  sql.NullTime    `json:"closed_at"`
 }
 
 type Task struct {
-	ID             int64        `json:"id"`
-	Title          string       `json:"title"`
-	Description    string       `json:"description"`
-	Priority       TaskPriority `json:"priority"`
-	Status         TaskStatus   `json:"status"`
-	AssigneeID     int64        `json:"assignee_id"`
-	ProjectID      int64        `json:"project_id"`
-	CreationDate   time.Time    `json:"creation_date"`
-	CompletionDate sql.NullTime `json:"completion_date"`
+	ID             int64           `json:"id"`
+	Title          string          `json:"title"`
+	Description    string          `json:"description"`
+	Priority       TaskPriority    `json:"priority"`
+	Status         string          `json:"status"`
+	AssigneeID     int64           `json:"assignee_id"`
+	ProjectID      int64           `json:"project_id"`
+	CreationDate   time.Time       `json:"creation_date"`
+	CompletionDate sql.NullTime    `json:"completion_date"`
+	Version        int64           `json:"version"`
+	ParentTaskID   sql.NullInt64   `json:"parent_task_id"`
+	Blocked        bool            `json:"blocked"`
+	DueDate        sql.NullTime    `json:"due_date"`
+	Estimate       sql.NullFloat64 `json:"estimate"`
+	SprintID       sql.NullInt64   `json:"sprint_id"`
+	MilestoneID    sql.NullInt64   `json:"milestone_id"`
+}
+
+type TaskDependency struct {
+	BlockerID int64     `json:"blocker_id"`
+	BlockedID int64     `json:"blocked_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TaskLabel struct {
+	TaskID    int64 `json:"task_id"`
+	LabelID   int64 `json:"label_id"`
+	ProjectID int64 `json:"project_id"`
+}
+
+type TaskStatusChange struct {
+	ID        int64          `json:"id"`
+	TaskID    int64          `json:"task_id"`
+	Status    string         `json:"status"`
+	Category  StatusCategory `json:"category"`
+	ChangedAt time.Time      `json:"changed_at"`
 }
 
 type User struct {
-	ID               int64     `json:"id"`
-	FullName         string    `json:"full_name"`
-	Email            string    `json:"email"`
-	RegistrationDate time.Time `json:"registration_date"`
-	Role             string    `json:"role"`
+	ID               int64           `json:"id"`
+	FullName         string          `json:"full_name"`
+	Email            string          `json:"email"`
+	RegistrationDate time.Time       `json:"registration_date"`
+	Role             UserRole        `json:"role"`
+	HashedPassword   string          `json:"hashed_password"`
+	Version          int64           `json:"version"`
+	WeeklyCapacity   sql.NullFloat64 `json:"weekly_capacity"`
+}
+
+type WorkflowStatus struct {
+	ID        int64          `json:"id"`
+	ProjectID int64          `json:"project_id"`
+	Name      string         `json:"name"`
+	Category  StatusCategory `json:"category"`
+	Position  int32          `json:"position"`
+}
+
+type Worklog struct {
+	ID        int64         `json:"id"`
+	TaskID    int64         `json:"task_id"`
+	UserID    int64         `json:"user_id"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  sql.NullInt64 `json:"duration"`
+	Note      string        `json:"note"`
 }
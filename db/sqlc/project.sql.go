@@ -17,7 +17,7 @@ INSERT INTO projects (
 ) VALUES (
     $1, $2, $3, $4, $5
 )
-RETURNING id, name, description, start_date, end_date, manager_id
+RETURNING id, name, description, start_date, end_date, manager_id, version
 `
 
 type CreateProjectParams struct {
@@ -44,6 +44,7 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		&i.StartDate,
 		&i.EndDate,
 		&i.ManagerID,
+		&i.Version,
 	)
 	return i, err
 }
@@ -59,7 +60,7 @@ func (q *Queries) DeleteProject(ctx context.Context, id int64) error {
 }
 
 const getProject = `-- name: GetProject :one
-SELECT id, name, description, start_date, end_date, manager_id FROM projects
+SELECT id, name, description, start_date, end_date, manager_id, version FROM projects
 WHERE id = $1 LIMIT 1
 `
 
@@ -73,154 +74,55 @@ func (q *Queries) GetProject(ctx context.Context, id int64) (Project, error) {
 		&i.StartDate,
 		&i.EndDate,
 		&i.ManagerID,
+		&i.Version,
 	)
 	return i, err
 }
 
-const getProjectTasks = `-- name: GetProjectTasks :many
-SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date FROM tasks
-WHERE project_id = $1
-ORDER BY creation_date ASC
-`
-
-func (q *Queries) GetProjectTasks(ctx context.Context, projectID int64) ([]Task, error) {
-	rows, err := q.db.QueryContext(ctx, getProjectTasks, projectID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Task{}
-	for rows.Next() {
-		var i Task
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.Priority,
-			&i.Status,
-			&i.AssigneeID,
-			&i.ProjectID,
-			&i.CreationDate,
-			&i.CompletionDate,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const listProjects = `-- name: ListProjects :many
-SELECT id, name, description, start_date, end_date, manager_id FROM projects
-ORDER BY start_date ASC
-`
-
-func (q *Queries) ListProjects(ctx context.Context) ([]Project, error) {
-	rows, err := q.db.QueryContext(ctx, listProjects)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Project{}
-	for rows.Next() {
-		var i Project
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.StartDate,
-			&i.EndDate,
-			&i.ManagerID,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const searchProjectsByManager = `-- name: SearchProjectsByManager :many
-SELECT id, name, description, start_date, end_date, manager_id FROM projects
-WHERE manager_id = $1
-ORDER BY start_date ASC
+const patchProject = `-- name: PatchProject :one
+UPDATE projects
+SET
+    name = COALESCE($1, name),
+    description = COALESCE($2, description),
+    start_date = COALESCE($3, start_date),
+    end_date = COALESCE($4, end_date),
+    manager_id = COALESCE($5, manager_id),
+    version = version + 1
+WHERE id = $6 AND version = $7
+RETURNING id, name, description, start_date, end_date, manager_id, version
 `
 
-func (q *Queries) SearchProjectsByManager(ctx context.Context, managerID int64) ([]Project, error) {
-	rows, err := q.db.QueryContext(ctx, searchProjectsByManager, managerID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Project{}
-	for rows.Next() {
-		var i Project
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.StartDate,
-			&i.EndDate,
-			&i.ManagerID,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type PatchProjectParams struct {
+	Name        sql.NullString `json:"name"`
+	Description sql.NullString `json:"description"`
+	StartDate   sql.NullTime   `json:"start_date"`
+	EndDate     sql.NullTime   `json:"end_date"`
+	ManagerID   sql.NullInt64  `json:"manager_id"`
+	ID          int64          `json:"id"`
+	Version     int64          `json:"version"`
 }
 
-const searchProjectsByTitle = `-- name: SearchProjectsByTitle :many
-SELECT id, name, description, start_date, end_date, manager_id FROM projects
-WHERE name ILIKE '%' || $1 || '%'
-ORDER BY start_date ASC
-`
-
-func (q *Queries) SearchProjectsByTitle(ctx context.Context, dollar_1 sql.NullString) ([]Project, error) {
-	rows, err := q.db.QueryContext(ctx, searchProjectsByTitle, dollar_1)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Project{}
-	for rows.Next() {
-		var i Project
-		if err := rows.Scan(
-			&i.ID,
-			&i.Name,
-			&i.Description,
-			&i.StartDate,
-			&i.EndDate,
-			&i.ManagerID,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) PatchProject(ctx context.Context, arg PatchProjectParams) (Project, error) {
+	row := q.db.QueryRowContext(ctx, patchProject,
+		arg.Name,
+		arg.Description,
+		arg.StartDate,
+		arg.EndDate,
+		arg.ManagerID,
+		arg.ID,
+		arg.Version,
+	)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.StartDate,
+		&i.EndDate,
+		&i.ManagerID,
+		&i.Version,
+	)
+	return i, err
 }
 
 const updateProject = `-- name: UpdateProject :one
@@ -230,9 +132,10 @@ SET
     description = $3,
     start_date = $4,
     end_date = $5,
-    manager_id = $6
-WHERE id = $1
-RETURNING id, name, description, start_date, end_date, manager_id
+    manager_id = $6,
+    version = version + 1
+WHERE id = $1 AND version = $7
+RETURNING id, name, description, start_date, end_date, manager_id, version
 `
 
 type UpdateProjectParams struct {
@@ -242,6 +145,7 @@ type UpdateProjectParams struct {
 	StartDate   time.Time `json:"start_date"`
 	EndDate     time.Time `json:"end_date"`
 	ManagerID   int64     `json:"manager_id"`
+	Version     int64     `json:"version"`
 }
 
 func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error) {
@@ -252,6 +156,7 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		arg.StartDate,
 		arg.EndDate,
 		arg.ManagerID,
+		arg.Version,
 	)
 	var i Project
 	err := row.Scan(
@@ -261,6 +166,7 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		&i.StartDate,
 		&i.EndDate,
 		&i.ManagerID,
+		&i.Version,
 	)
 	return i, err
 }
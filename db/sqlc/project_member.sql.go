@@ -0,0 +1,144 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: project_member.sql
+
+package db
+
+import (
+	"context"
+	"time"
+)
+
+const addProjectMember = `-- name: AddProjectMember :one
+INSERT INTO project_members (
+    project_id, user_id, role
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (project_id, user_id) DO UPDATE
+SET role = EXCLUDED.role
+RETURNING project_id, user_id, role, added_at
+`
+
+type AddProjectMemberParams struct {
+	ProjectID int64       `json:"project_id"`
+	UserID    int64       `json:"user_id"`
+	Role      ProjectRole `json:"role"`
+}
+
+func (q *Queries) AddProjectMember(ctx context.Context, arg AddProjectMemberParams) (ProjectMember, error) {
+	row := q.db.QueryRowContext(ctx, addProjectMember, arg.ProjectID, arg.UserID, arg.Role)
+	var i ProjectMember
+	err := row.Scan(
+		&i.ProjectID,
+		&i.UserID,
+		&i.Role,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const demoteProjectManager = `-- name: DemoteProjectManager :exec
+UPDATE project_members
+SET role = 'member'
+WHERE project_id = $1 AND user_id = $2 AND role = 'manager'
+`
+
+type DemoteProjectManagerParams struct {
+	ProjectID int64 `json:"project_id"`
+	UserID    int64 `json:"user_id"`
+}
+
+// Makes a former project manager a regular member
+func (q *Queries) DemoteProjectManager(ctx context.Context, arg DemoteProjectManagerParams) error {
+	_, err := q.db.ExecContext(ctx, demoteProjectManager, arg.ProjectID, arg.UserID)
+	return err
+}
+
+const getProjectMember = `-- name: GetProjectMember :one
+SELECT project_id, user_id, role, added_at FROM project_members
+WHERE project_id = $1 AND user_id = $2 LIMIT 1
+`
+
+type GetProjectMemberParams struct {
+	ProjectID int64 `json:"project_id"`
+	UserID    int64 `json:"user_id"`
+}
+
+func (q *Queries) GetProjectMember(ctx context.Context, arg GetProjectMemberParams) (ProjectMember, error) {
+	row := q.db.QueryRowContext(ctx, getProjectMember, arg.ProjectID, arg.UserID)
+	var i ProjectMember
+	err := row.Scan(
+		&i.ProjectID,
+		&i.UserID,
+		&i.Role,
+		&i.AddedAt,
+	)
+	return i, err
+}
+
+const listProjectMembers = `-- name: ListProjectMembers :many
+SELECT pm.project_id, pm.user_id, pm.role, pm.added_at, u.full_name, u.email
+FROM project_members pm
+JOIN users u ON u.id = pm.user_id
+WHERE pm.project_id = $1
+ORDER BY u.full_name ASC
+`
+
+type ListProjectMembersRow struct {
+	ProjectID int64       `json:"project_id"`
+	UserID    int64       `json:"user_id"`
+	Role      ProjectRole `json:"role"`
+	AddedAt   time.Time   `json:"added_at"`
+	FullName  string      `json:"full_name"`
+	Email     string      `json:"email"`
+}
+
+func (q *Queries) ListProjectMembers(ctx context.Context, projectID int64) ([]ListProjectMembersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProjectMembers, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListProjectMembersRow{}
+	for rows.Next() {
+		var i ListProjectMembersRow
+		if err := rows.Scan(
+			&i.ProjectID,
+			&i.UserID,
+			&i.Role,
+			&i.AddedAt,
+			&i.FullName,
+			&i.Email,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeProjectMember = `-- name: RemoveProjectMember :execrows
+DELETE FROM project_members
+WHERE project_id = $1 AND user_id = $2
+`
+
+type RemoveProjectMemberParams struct {
+	ProjectID int64 `json:"project_id"`
+	UserID    int64 `json:"user_id"`
+}
+
+func (q *Queries) RemoveProjectMember(ctx context.Context, arg RemoveProjectMemberParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeProjectMember, arg.ProjectID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddProjectMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"project_id", "user_id", "role", "added_at"}).
+		AddRow(1, 2, ProjectRoleMember, now)
+
+	mock.ExpectQuery("INSERT INTO project_members (.+) ON CONFLICT \\(project_id, user_id\\) DO UPDATE").
+		WithArgs(int64(1), int64(2), ProjectRoleMember).
+		WillReturnRows(rows)
+
+	member, err := queries.AddProjectMember(context.Background(), AddProjectMemberParams{
+		ProjectID: 1,
+		UserID:    2,
+		Role:      ProjectRoleMember,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), member.ProjectID)
+	assert.Equal(t, int64(2), member.UserID)
+	assert.Equal(t, ProjectRoleMember, member.Role)
+	assert.WithinDuration(t, now, member.AddedAt, time.Second)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetProjectMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"project_id", "user_id", "role", "added_at"}).
+		AddRow(1, 2, ProjectRoleViewer, now)
+
+	mock.ExpectQuery("SELECT (.+) FROM project_members WHERE project_id = \\$1 AND user_id = \\$2 LIMIT 1").
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(rows)
+
+	member, err := queries.GetProjectMember(context.Background(), GetProjectMemberParams{
+		ProjectID: 1,
+		UserID:    2,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ProjectRoleViewer, member.Role)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestListProjectMembers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"project_id", "user_id", "role", "added_at", "full_name", "email"}).
+		AddRow(1, 2, ProjectRoleManager, now, "Alice Smith", "alice@example.com").
+		AddRow(1, 3, ProjectRoleMember, now, "Bob Johnson", "bob@example.com")
+
+	mock.ExpectQuery("SELECT (.+) FROM project_members pm JOIN users u ON u.id = pm.user_id WHERE pm.project_id = \\$1 ORDER BY u.full_name ASC").
+		WithArgs(int64(1)).
+		WillReturnRows(rows)
+
+	members, err := queries.ListProjectMembers(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, members, 2)
+	assert.Equal(t, "Alice Smith", members[0].FullName)
+	assert.Equal(t, ProjectRoleManager, members[0].Role)
+	assert.Equal(t, "bob@example.com", members[1].Email)
+	assert.Equal(t, ProjectRoleMember, members[1].Role)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRemoveProjectMember(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	mock.ExpectExec("DELETE FROM project_members WHERE project_id = \\$1 AND user_id = \\$2").
+		WithArgs(int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	removed, err := queries.RemoveProjectMember(context.Background(), RemoveProjectMemberParams{
+		ProjectID: 1,
+		UserID:    2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+)
+
+// projectColumns lists the columns of the projects table in the order they
+// are scanned into a Project
+const projectColumns = `id, name, description, start_date, end_date, manager_id, version`
+
+// SearchProjectsParams holds the filters of SearchProjects, zero values are
+// ignored
+type SearchProjectsParams struct {
+	Name      string        `json:"name"`
+	ManagerID int64         `json:"manager_id"`
+	Sort      Sort          `json:"sort"`
+	After     []interface{} `json:"after"`
+	PageLimit int32         `json:"page_limit"`
+}
+
+// ProjectSortColumns are the columns projects can be sorted by
+var ProjectSortColumns = SortColumns[Project]{
+	"id":         func(p Project) interface{} { return p.ID },
+	"name":       func(p Project) interface{} { return p.Name },
+	"start_date": func(p Project) interface{} { return p.StartDate },
+	"end_date":   func(p Project) interface{} { return p.EndDate },
+	"manager_id": func(p Project) interface{} { return p.ManagerID },
+}
+
+// DefaultProjectSort orders projects by their start date
+var DefaultProjectSort = Sort{{Column: "start_date"}, {Column: "id"}}
+
+// SearchProjects returns the projects matching every filter of arg, in the
+// requested order and starting after the row the After values were taken from
+func (q *Queries) SearchProjects(ctx context.Context, arg SearchProjectsParams) ([]Project, error) {
+	sort := arg.Sort
+	if len(sort) == 0 {
+		sort = DefaultProjectSort
+	}
+	if err := ProjectSortColumns.validate(sort); err != nil {
+		return nil, err
+	}
+
+	var b queryBuilder
+
+	if arg.Name != "" {
+		b.whereContains("name", arg.Name)
+	}
+	if arg.ManagerID != 0 {
+		b.where("manager_id = ?", arg.ManagerID)
+	}
+	if err := b.after(sort, arg.After); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + projectColumns + " FROM projects\n" + b.clause() +
+		"\n" + orderBy(sort) + "\nLIMIT " + b.arg(arg.PageLimit)
+
+	rows, err := q.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Project{}
+	for rows.Next() {
+		var i Project
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.StartDate,
+			&i.EndDate,
+			&i.ManagerID,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
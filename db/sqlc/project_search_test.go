@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchProjectsWithoutFilters(t *testing.T) {
+	// Initialize mock DB
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Replace q.db with mock DB for testing purposes
+	queries := New(db)
+
+	// Capture the current time for consistent use
+	now := time.Now()
+	startDate := now
+	endDate := now.AddDate(0, 1, 0)
+
+	// Mock expected rows to return
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "start_date", "end_date", "manager_id", "version"}).
+		AddRow(1, "Project 1", "Description 1", startDate, endDate, 123, 1).
+		AddRow(2, "Project 2", "Description 2", startDate, endDate, 456, 1)
+
+	// Expectation: QueryContext
+	mock.ExpectQuery(`SELECT id, name, description, start_date, end_date, manager_id, version FROM projects ORDER BY start_date ASC, id ASC LIMIT \$1`).
+		WithArgs(int32(21)).
+		WillReturnRows(rows)
+
+	// Call the SearchProjects method
+	projects, err := queries.SearchProjects(context.Background(), SearchProjectsParams{PageLimit: 21})
+
+	// Verify results
+	assert.NoError(t, err, "Expected no error")
+	assert.Len(t, projects, 2, "Expected two projects")
+	assert.Equal(t, int64(1), projects[0].ID, "Expected project ID to match")
+	assert.Equal(t, "Project 1", projects[0].Name, "Expected project name to match")
+	assert.Equal(t, "Description 1", projects[0].Description, "Expected project description to match")
+	assert.WithinDuration(t, startDate, projects[0].StartDate, time.Second, "Expected project start date to match")
+	assert.WithinDuration(t, endDate, projects[0].EndDate, time.Second, "Expected project end date to match")
+	assert.Equal(t, int64(2), projects[1].ID, "Expected project ID to match")
+	assert.Equal(t, "Project 2", projects[1].Name, "Expected project name to match")
+
+	// Assert all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSearchProjectsByManager(t *testing.T) {
+	// Initialize mock DB
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Replace q.db with mock DB for testing purposes
+	queries := New(db)
+
+	// Mock expected rows to return
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "start_date", "end_date", "manager_id", "version"}).
+		AddRow(1, "Test Project", "Description", time.Now(), time.Now().AddDate(0, 1, 0), 123, 1)
+
+	// Expectation: QueryContext with expected arguments
+	mock.ExpectQuery(`SELECT id, name, description, start_date, end_date, manager_id, version FROM projects WHERE manager_id = \$1 ORDER BY end_date DESC, id ASC LIMIT \$2`).
+		WithArgs(int64(123), int32(21)).
+		WillReturnRows(rows)
+
+	// Call the SearchProjects method
+	projects, err := queries.SearchProjects(context.Background(), SearchProjectsParams{
+		ManagerID: 123,
+		Sort:      Sort{{Column: "end_date", Desc: true}, {Column: "id"}},
+		PageLimit: 21,
+	})
+
+	// Verify results
+	assert.NoError(t, err, "Expected no error")
+	assert.Len(t, projects, 1, "Expected one project")
+	assert.Equal(t, int64(1), projects[0].ID, "Expected project ID to match")
+	assert.Equal(t, "Test Project", projects[0].Name, "Expected project name to match")
+
+	// Assert all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSearchProjectsByTitle(t *testing.T) {
+	// Initialize mock DB
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Replace q.db with mock DB for testing purposes
+	queries := New(db)
+
+	// Capture the current time for consistent use
+	now := time.Now()
+	startDate := now
+	endDate := now.AddDate(0, 1, 0)
+
+	// Mock expected rows to return
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "start_date", "end_date", "manager_id", "version"}).
+		AddRow(1, "Test Project", "Description", startDate, endDate, 123, 1)
+
+	// Expectation: QueryContext with expected arguments
+	mock.ExpectQuery(`SELECT id, name, description, start_date, end_date, manager_id, version FROM projects WHERE name ILIKE '%' \|\| \$1 \|\| '%' ESCAPE '\\' AND \(\(start_date > \$2\) OR \(start_date = \$3 AND id > \$4\)\) ORDER BY start_date ASC, id ASC LIMIT \$5`).
+		WithArgs("Test", "2024-07-19T00:00:00Z", "2024-07-19T00:00:00Z", "7", int32(21)).
+		WillReturnRows(rows)
+
+	// Call the SearchProjects method
+	projects, err := queries.SearchProjects(context.Background(), SearchProjectsParams{
+		Name:      "Test",
+		After:     []interface{}{"2024-07-19T00:00:00Z", "7"},
+		PageLimit: 21,
+	})
+
+	// Verify results
+	assert.NoError(t, err, "Expected no error")
+	assert.Len(t, projects, 1, "Expected one project")
+	assert.Equal(t, int64(1), projects[0].ID, "Expected project ID to match")
+	assert.Equal(t, "Test Project", projects[0].Name, "Expected project name to match")
+
+	// Assert all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
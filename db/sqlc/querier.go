@@ -6,36 +6,138 @@ package db
 
 import (
 	"context"
-	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AddCommentEdit(ctx context.Context, arg AddCommentEditParams) error
+	AddProjectMember(ctx context.Context, arg AddProjectMemberParams) (ProjectMember, error)
+	AddTaskDependency(ctx context.Context, arg AddTaskDependencyParams) error
+	AttachLabel(ctx context.Context, arg AttachLabelParams) error
+	// Counts the tasks and milestones of a project due before the start date or
+	// after the end date
+	CountDueOutsideDates(ctx context.Context, arg CountDueOutsideDatesParams) (CountDueOutsideDatesRow, error)
+	// Counts the tasks of the milestones of a project per status, in workflow
+	// order. Statuses without tasks are left out.
+	CountMilestoneTasksByStatus(ctx context.Context, projectID int64) ([]CountMilestoneTasksByStatusRow, error)
+	CountProjectTasksByPriority(ctx context.Context, projectID int64) ([]CountProjectTasksByPriorityRow, error)
+	// Counts the tasks of a project in every status of its workflow, in workflow
+	// order
+	CountProjectTasksByStatus(ctx context.Context, projectID int64) ([]CountProjectTasksByStatusRow, error)
+	CountTaskComments(ctx context.Context, taskID int64) (int64, error)
+	CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error)
+	CreateComment(ctx context.Context, arg CreateCommentParams) (Comment, error)
+	CreateDefaultWorkflowStatuses(ctx context.Context, projectID int64) error
+	CreateLabel(ctx context.Context, arg CreateLabelParams) (Label, error)
+	CreateMilestone(ctx context.Context, arg CreateMilestoneParams) (Milestone, error)
 	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
+	CreateSprint(ctx context.Context, arg CreateSprintParams) (Sprint, error)
 	CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateWorkflowStatus(ctx context.Context, arg CreateWorkflowStatusParams) (WorkflowStatus, error)
+	CreateWorklog(ctx context.Context, arg CreateWorklogParams) (Worklog, error)
+	DeleteAttachment(ctx context.Context, arg DeleteAttachmentParams) error
+	DeleteComment(ctx context.Context, arg DeleteCommentParams) error
+	DeleteLabel(ctx context.Context, arg DeleteLabelParams) error
+	DeleteMilestone(ctx context.Context, arg DeleteMilestoneParams) error
 	DeleteProject(ctx context.Context, id int64) error
+	DeleteSprint(ctx context.Context, arg DeleteSprintParams) error
 	DeleteTask(ctx context.Context, id int64) error
 	DeleteUser(ctx context.Context, id int64) error
+	DeleteWorkflowStatus(ctx context.Context, id int64) error
+	DeleteWorklog(ctx context.Context, arg DeleteWorklogParams) error
+	// Makes a former project manager a regular member
+	DemoteProjectManager(ctx context.Context, arg DemoteProjectManagerParams) error
+	// Reports whether the task waits on the blocker, directly or through other tasks
+	DependsOn(ctx context.Context, arg DependsOnParams) (bool, error)
+	// Removes the labels of other projects from a task that moves
+	DetachForeignLabels(ctx context.Context, arg DetachForeignLabelsParams) error
+	DetachLabel(ctx context.Context, arg DetachLabelParams) (int64, error)
+	GetAttachment(ctx context.Context, arg GetAttachmentParams) (Attachment, error)
+	// Counts, for every day of the range, the tasks of the project or sprint that
+	// existed at the end of the day and those of them in a done status then. The
+	// status history decides, tasks from before it fall back to completion_date.
+	GetBurndown(ctx context.Context, arg GetBurndownParams) ([]GetBurndownRow, error)
+	GetComment(ctx context.Context, arg GetCommentParams) (Comment, error)
+	GetLabel(ctx context.Context, arg GetLabelParams) (Label, error)
+	GetMilestone(ctx context.Context, arg GetMilestoneParams) (Milestone, error)
+	GetMilestoneSummary(ctx context.Context, arg GetMilestoneSummaryParams) (GetMilestoneSummaryRow, error)
 	GetProject(ctx context.Context, id int64) (Project, error)
-	GetProjectTasks(ctx context.Context, projectID int64) ([]Task, error)
+	GetProjectMember(ctx context.Context, arg GetProjectMemberParams) (ProjectMember, error)
+	// Counts the tasks of a project, those in a done status and the open ones
+	// past their due date
+	GetProjectTaskTotals(ctx context.Context, projectID int64) (GetProjectTaskTotalsRow, error)
+	GetSprint(ctx context.Context, arg GetSprintParams) (Sprint, error)
+	GetSprintSummary(ctx context.Context, arg GetSprintSummaryParams) (GetSprintSummaryRow, error)
 	GetTask(ctx context.Context, id int64) (Task, error)
+	GetTaskRollup(ctx context.Context, id int64) (GetTaskRollupRow, error)
 	GetUser(ctx context.Context, id int64) (User, error)
-	GetUserTasks(ctx context.Context, assigneeID int64) ([]Task, error)
-	ListProjects(ctx context.Context) ([]Project, error)
-	ListTasks(ctx context.Context) ([]Task, error)
-	ListUsers(ctx context.Context) ([]User, error)
-	SearchProjectsByManager(ctx context.Context, managerID int64) ([]Project, error)
-	SearchProjectsByTitle(ctx context.Context, dollar_1 sql.NullString) ([]Project, error)
-	SearchTasksByAssignee(ctx context.Context, assigneeID int64) ([]Task, error)
-	SearchTasksByPriority(ctx context.Context, priority TaskPriority) ([]Task, error)
-	SearchTasksByProject(ctx context.Context, projectID int64) ([]Task, error)
-	SearchTasksByStatus(ctx context.Context, status TaskStatus) ([]Task, error)
-	SearchTasksByTitle(ctx context.Context, dollar_1 sql.NullString) ([]Task, error)
-	SearchUsersByEmail(ctx context.Context, dollar_1 sql.NullString) ([]User, error)
-	SearchUsersByName(ctx context.Context, dollar_1 sql.NullString) ([]User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetWorkflowStatus(ctx context.Context, arg GetWorkflowStatusParams) (WorkflowStatus, error)
+	GetWorkflowStatusByID(ctx context.Context, id int64) (WorkflowStatus, error)
+	GetWorklog(ctx context.Context, arg GetWorklogParams) (Worklog, error)
+	// Reports whether ancestor_id is the task itself or one of its ancestors
+	IsTaskAncestor(ctx context.Context, arg IsTaskAncestorParams) (bool, error)
+	ListBlockedTasks(ctx context.Context, blockerID int64) ([]Task, error)
+	ListCommentEdits(ctx context.Context, commentID int64) ([]time.Time, error)
+	ListLabels(ctx context.Context, projectID int64) ([]Label, error)
+	// Lists the milestones of a project with the share of their tasks in a done
+	// status, a milestone without tasks is at 0 percent
+	ListMilestones(ctx context.Context, projectID int64) ([]ListMilestonesRow, error)
+	ListProjectMembers(ctx context.Context, projectID int64) ([]ListProjectMembersRow, error)
+	ListSprintTasks(ctx context.Context, sprintID int64) ([]Task, error)
+	ListSprints(ctx context.Context, projectID int64) ([]ListSprintsRow, error)
+	ListSubtasks(ctx context.Context, id int64) ([]Task, error)
+	ListTaskAttachments(ctx context.Context, taskID int64) ([]Attachment, error)
+	ListTaskBlockers(ctx context.Context, blockedID int64) ([]Task, error)
+	ListTaskCommentEdits(ctx context.Context, taskID int64) ([]CommentEdit, error)
+	ListTaskComments(ctx context.Context, taskID int64) ([]ListTaskCommentsRow, error)
+	ListTaskLabels(ctx context.Context, taskID int64) ([]Label, error)
+	ListTaskSubtree(ctx context.Context, id int64) ([]ListTaskSubtreeRow, error)
+	ListTaskWorklogs(ctx context.Context, taskID int64) ([]Worklog, error)
+	// Lists the assignees of a project with the most open tasks, ties go to the
+	// larger sum of estimates
+	ListTopAssignees(ctx context.Context, arg ListTopAssigneesParams) ([]ListTopAssigneesRow, error)
+	ListWorkflowStatuses(ctx context.Context, projectID int64) ([]WorkflowStatus, error)
+	// Sums the open tasks assigned to every user, or to the one user_id names,
+	// across the projects the viewer is a member of. Viewers other than admins
+	// only see themselves and the members of their projects. The busiest users
+	// come first.
+	ListWorkload(ctx context.Context, arg ListWorkloadParams) ([]ListWorkloadRow, error)
+	// Serializes changes to the dependencies of a project until the transaction
+	// ends, so that concurrent additions cannot close a cycle together
+	LockProjectDependencies(ctx context.Context, projectID int64) error
+	// Moves the tasks of a sprint that are not done to another sprint of the
+	// project, or to the backlog when to_sprint_id is null
+	MoveUnfinishedTasks(ctx context.Context, arg MoveUnfinishedTasksParams) (int64, error)
+	PatchProject(ctx context.Context, arg PatchProjectParams) (Project, error)
+	PatchTask(ctx context.Context, arg PatchTaskParams) (Task, error)
+	PatchUser(ctx context.Context, arg PatchUserParams) (User, error)
+	RemoveProjectMember(ctx context.Context, arg RemoveProjectMemberParams) (int64, error)
+	RemoveTaskDependency(ctx context.Context, arg RemoveTaskDependencyParams) (int64, error)
+	// Moves the sprint to the state when it is in the expected one, closing it
+	// records the time
+	SetSprintState(ctx context.Context, arg SetSprintStateParams) (Sprint, error)
+	SetTaskSprint(ctx context.Context, arg SetTaskSprintParams) (Task, error)
+	// Worklogs are stored in UTC like the entries logged by hand
+	StartTimer(ctx context.Context, arg StartTimerParams) (Worklog, error)
+	// Stops the running timer of the user on the task, recording the elapsed
+	// whole seconds as its duration
+	StopTimer(ctx context.Context, arg StopTimerParams) (Worklog, error)
+	SumProjectWorklogsByTask(ctx context.Context, arg SumProjectWorklogsByTaskParams) ([]SumProjectWorklogsByTaskRow, error)
+	SumProjectWorklogsByUser(ctx context.Context, arg SumProjectWorklogsByUserParams) ([]SumProjectWorklogsByUserRow, error)
+	SumTaskWorklogsByUser(ctx context.Context, arg SumTaskWorklogsByUserParams) ([]SumTaskWorklogsByUserRow, error)
+	// Only counts the projects the viewer is a member of, unless the viewer is
+	// an admin
+	SumUserWorklogsByProject(ctx context.Context, arg SumUserWorklogsByProjectParams) ([]SumUserWorklogsByProjectRow, error)
+	UpdateComment(ctx context.Context, arg UpdateCommentParams) (Comment, error)
+	UpdateLabel(ctx context.Context, arg UpdateLabelParams) (Label, error)
+	UpdateMilestone(ctx context.Context, arg UpdateMilestoneParams) (Milestone, error)
 	UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error)
+	UpdateSprint(ctx context.Context, arg UpdateSprintParams) (Sprint, error)
 	UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateWorkflowStatus(ctx context.Context, arg UpdateWorkflowStatusParams) (WorkflowStatus, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,209 @@
+package db
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Different types of error returned when composing a query
+var (
+	ErrInvalidSort    = errors.New("invalid sort parameter")
+	ErrCursorMismatch = errors.New("cursor does not match the sort order")
+)
+
+// SortKey is a column of an ORDER BY clause
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// Sort is an ordered list of sort keys, always ending with the id column so
+// that rows are totally ordered and can be paginated with a keyset cursor
+type Sort []SortKey
+
+// String formats the sort like the sort query parameter, e.g. -priority,id
+func (s Sort) String() string {
+	fields := make([]string, len(s))
+	for i, k := range s {
+		if k.Desc {
+			fields[i] = "-" + k.Column
+		} else {
+			fields[i] = k.Column
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// SortColumns is the whitelist of the columns a resource can be sorted by,
+// each with the accessor returning the value of the column for a row
+type SortColumns[T any] map[string]func(T) interface{}
+
+// Parse validates a sort parameter such as "-priority,creation_date" against
+// the whitelist. An empty parameter selects def. The id column is appended
+// as a tiebreaker unless the parameter already contains it.
+func (c SortColumns[T]) Parse(param string, def Sort) (Sort, error) {
+	if strings.TrimSpace(param) == "" {
+		param = def.String()
+	}
+
+	var sort Sort
+	seen := map[string]bool{}
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+
+		key := SortKey{Column: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+		if _, ok := c[key.Column]; !ok || seen[key.Column] {
+			return nil, ErrInvalidSort
+		}
+
+		seen[key.Column] = true
+		sort = append(sort, key)
+	}
+
+	if !seen["id"] {
+		sort = append(sort, SortKey{Column: "id"})
+	}
+
+	return sort, nil
+}
+
+// Values returns a function extracting the values of the sort columns of a
+// row, which a cursor needs to resume the listing after that row
+func (c SortColumns[T]) Values(sort Sort) func(T) []interface{} {
+	return func(row T) []interface{} {
+		values := make([]interface{}, len(sort))
+		for i, k := range sort {
+			values[i] = c[k.Column](row)
+		}
+		return values
+	}
+}
+
+// validate checks that every column of the sort is in the whitelist
+func (c SortColumns[T]) validate(sort Sort) error {
+	for _, k := range sort {
+		if _, ok := c[k.Column]; !ok {
+			return ErrInvalidSort
+		}
+	}
+	return nil
+}
+
+// queryBuilder collects WHERE conditions together with their positional
+// arguments so that a query can be composed without interpolating values
+type queryBuilder struct {
+	conds []string
+	args  []interface{}
+}
+
+// arg registers a value and returns its placeholder
+func (b *queryBuilder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// where adds a condition, formatted with the placeholders of the values
+func (b *queryBuilder) where(format string, values ...interface{}) {
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = b.arg(v)
+	}
+
+	cond := format
+	for _, p := range placeholders {
+		cond = strings.Replace(cond, "?", p, 1)
+	}
+	b.conds = append(b.conds, cond)
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern and the escape
+// character itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// whereContains adds a case-insensitive substring match of the column. The
+// value is matched literally, % and _ are not wildcards.
+func (b *queryBuilder) whereContains(column, value string) {
+	b.where(column+` ILIKE '%' || ? || '%' ESCAPE '\'`, likeEscaper.Replace(value))
+}
+
+// whereIn adds a "column IN (...)" condition for a non-empty list of values
+func (b *queryBuilder) whereIn(column string, values []interface{}) {
+	if len(values) == 0 {
+		return
+	}
+
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = b.arg(v)
+	}
+	b.conds = append(b.conds, column+" IN ("+strings.Join(placeholders, ", ")+")")
+}
+
+// after adds the keyset condition selecting the rows that come after the
+// row the cursor values were taken from, in the given sort order. Every
+// column of the sort must come from a SortColumns whitelist.
+//
+// For a sort a, -b, id it produces
+// (a > $1) OR (a = $2 AND b < $3) OR (a = $4 AND b = $5 AND id > $6)
+func (b *queryBuilder) after(sort Sort, values []interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if len(values) != len(sort) {
+		return ErrCursorMismatch
+	}
+
+	alternatives := make([]string, len(sort))
+	for i, k := range sort {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, sort[j].Column+" = "+b.arg(values[j]))
+		}
+
+		op := " > "
+		if k.Desc {
+			op = " < "
+		}
+		terms = append(terms, k.Column+op+b.arg(values[i]))
+
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+
+	b.conds = append(b.conds, "("+strings.Join(alternatives, " OR ")+")")
+	return nil
+}
+
+// clause returns the WHERE clause, or an empty string without conditions
+func (b *queryBuilder) clause() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conds, "\n    AND ")
+}
+
+// orderBy returns the ORDER BY clause of the sort
+func orderBy(sort Sort) string {
+	keys := make([]string, len(sort))
+	for i, k := range sort {
+		if k.Desc {
+			keys[i] = k.Column + " DESC"
+		} else {
+			keys[i] = k.Column + " ASC"
+		}
+	}
+	return "ORDER BY " + strings.Join(keys, ", ")
+}
+
+// marks returns n comma separated placeholders for where
+func marks(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func toArgs[T any](values []T) []interface{} {
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
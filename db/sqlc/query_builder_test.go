@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortColumnsParse(t *testing.T) {
+	sort, err := TaskSortColumns.Parse("", DefaultTaskSort)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTaskSort, sort)
+
+	sort, err = TaskSortColumns.Parse("-priority, creation_date", DefaultTaskSort)
+	require.NoError(t, err)
+	assert.Equal(t, Sort{{Column: "priority", Desc: true}, {Column: "creation_date"}, {Column: "id"}}, sort)
+	assert.Equal(t, "-priority,creation_date,id", sort.String())
+
+	sort, err = TaskSortColumns.Parse("-id", DefaultTaskSort)
+	require.NoError(t, err)
+	assert.Equal(t, Sort{{Column: "id", Desc: true}}, sort)
+
+	for _, param := range []string{"description", "priority,-priority", "-", "title,", "completion_date"} {
+		_, err = TaskSortColumns.Parse(param, DefaultTaskSort)
+		assert.ErrorIs(t, err, ErrInvalidSort, param)
+	}
+}
+
+func TestQueryBuilderAfter(t *testing.T) {
+	var b queryBuilder
+	b.where("manager_id = ?", int64(3))
+
+	err := b.after(Sort{{Column: "name"}, {Column: "end_date", Desc: true}, {Column: "id"}}, []interface{}{"Apollo", "2024-07-19", int64(9)})
+	require.NoError(t, err)
+
+	assert.Equal(t, "WHERE manager_id = $1\n    AND ((name > $2) OR (name = $3 AND end_date < $4) OR (name = $5 AND end_date = $6 AND id > $7))", b.clause())
+	assert.Equal(t, []interface{}{int64(3), "Apollo", "Apollo", "2024-07-19", "Apollo", "2024-07-19", int64(9)}, b.args)
+
+	assert.ErrorIs(t, b.after(DefaultProjectSort, []interface{}{int64(9)}), ErrCursorMismatch)
+}
+
+func TestQueryBuilderWhereContains(t *testing.T) {
+	var b queryBuilder
+	b.whereContains("title", `100%_done\`)
+
+	assert.Equal(t, `WHERE title ILIKE '%' || $1 || '%' ESCAPE '\'`, b.clause())
+	assert.Equal(t, []interface{}{`100\%\_done\\`}, b.args)
+}
@@ -0,0 +1,338 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: report.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const countProjectTasksByPriority = `-- name: CountProjectTasksByPriority :many
+SELECT priority, count(*) AS tasks
+FROM tasks
+WHERE project_id = $1
+GROUP BY priority
+`
+
+type CountProjectTasksByPriorityRow struct {
+	Priority TaskPriority `json:"priority"`
+	Tasks    int64        `json:"tasks"`
+}
+
+func (q *Queries) CountProjectTasksByPriority(ctx context.Context, projectID int64) ([]CountProjectTasksByPriorityRow, error) {
+	rows, err := q.db.QueryContext(ctx, countProjectTasksByPriority, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountProjectTasksByPriorityRow{}
+	for rows.Next() {
+		var i CountProjectTasksByPriorityRow
+		if err := rows.Scan(&i.Priority, &i.Tasks); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countProjectTasksByStatus = `-- name: CountProjectTasksByStatus :many
+SELECT ws.name AS status, ws.category, count(t.id) AS tasks
+FROM workflow_statuses ws
+LEFT JOIN tasks t ON t.project_id = ws.project_id AND t.status = ws.name
+WHERE ws.project_id = $1
+GROUP BY ws.id
+ORDER BY ws.position, ws.name
+`
+
+type CountProjectTasksByStatusRow struct {
+	Status   string         `json:"status"`
+	Category StatusCategory `json:"category"`
+	Tasks    int64          `json:"tasks"`
+}
+
+// Counts the tasks of a project in every status of its workflow, in workflow
+// order
+func (q *Queries) CountProjectTasksByStatus(ctx context.Context, projectID int64) ([]CountProjectTasksByStatusRow, error) {
+	rows, err := q.db.QueryContext(ctx, countProjectTasksByStatus, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []CountProjectTasksByStatusRow{}
+	for rows.Next() {
+		var i CountProjectTasksByStatusRow
+		if err := rows.Scan(&i.Status, &i.Category, &i.Tasks); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBurndown = `-- name: GetBurndown :many
+WITH days AS (
+    SELECT generate_series($1::date, $2::date, interval '1 day')::date AS day
+), scope AS (
+    SELECT t.id, t.creation_date, t.completion_date, t.estimate
+    FROM tasks t
+    WHERE t.project_id = $3
+        AND ($4::bigint IS NULL OR t.sprint_id = $4::bigint)
+)
+SELECT
+    days.day,
+    count(s.id) AS tasks,
+    count(s.id) FILTER (WHERE s.done) AS completed,
+    COALESCE(sum(s.estimate), 0)::double precision AS estimate,
+    COALESCE(sum(s.estimate) FILTER (WHERE s.done), 0)::double precision AS completed_estimate
+FROM days
+LEFT JOIN LATERAL (
+    SELECT scope.id, scope.estimate,
+        COALESCE(
+            (SELECT h.category = 'done'
+             FROM task_status_changes h
+             WHERE h.task_id = scope.id AND h.changed_at < days.day + 1
+             ORDER BY h.changed_at DESC, h.id DESC
+             LIMIT 1),
+            scope.completion_date < days.day + 1,
+            false
+        ) AS done
+    FROM scope
+    WHERE scope.creation_date < days.day + 1
+) s ON true
+GROUP BY days.day
+ORDER BY days.day
+`
+
+type GetBurndownParams struct {
+	FromDate  time.Time     `json:"from_date"`
+	ToDate    time.Time     `json:"to_date"`
+	ProjectID int64         `json:"project_id"`
+	SprintID  sql.NullInt64 `json:"sprint_id"`
+}
+
+type GetBurndownRow struct {
+	Day               time.Time `json:"day"`
+	Tasks             int64     `json:"tasks"`
+	Completed         int64     `json:"completed"`
+	Estimate          float64   `json:"estimate"`
+	CompletedEstimate float64   `json:"completed_estimate"`
+}
+
+// Counts, for every day of the range, the tasks of the project or sprint that
+// existed at the end of the day and those of them in a done status then. The
+// status history decides, tasks from before it fall back to completion_date.
+func (q *Queries) GetBurndown(ctx context.Context, arg GetBurndownParams) ([]GetBurndownRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBurndown,
+		arg.FromDate,
+		arg.ToDate,
+		arg.ProjectID,
+		arg.SprintID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetBurndownRow{}
+	for rows.Next() {
+		var i GetBurndownRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.Tasks,
+			&i.Completed,
+			&i.Estimate,
+			&i.CompletedEstimate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProjectTaskTotals = `-- name: GetProjectTaskTotals :one
+SELECT
+    count(*) AS tasks,
+    count(*) FILTER (WHERE ws.category = 'done') AS completed,
+    count(*) FILTER (WHERE ws.category <> 'done' AND t.due_date < CURRENT_DATE) AS overdue
+FROM tasks t
+JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE t.project_id = $1
+`
+
+type GetProjectTaskTotalsRow struct {
+	Tasks     int64 `json:"tasks"`
+	Completed int64 `json:"completed"`
+	Overdue   int64 `json:"overdue"`
+}
+
+// Counts the tasks of a project, those in a done status and the open ones
+// past their due date
+func (q *Queries) GetProjectTaskTotals(ctx context.Context, projectID int64) (GetProjectTaskTotalsRow, error) {
+	row := q.db.QueryRowContext(ctx, getProjectTaskTotals, projectID)
+	var i GetProjectTaskTotalsRow
+	err := row.Scan(&i.Tasks, &i.Completed, &i.Overdue)
+	return i, err
+}
+
+const listTopAssignees = `-- name: ListTopAssignees :many
+SELECT t.assignee_id, u.full_name, count(*) AS open_tasks,
+    COALESCE(sum(t.estimate), 0)::double precision AS open_estimate
+FROM tasks t
+JOIN users u ON u.id = t.assignee_id
+JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE t.project_id = $1 AND ws.category <> 'done'
+GROUP BY t.assignee_id, u.full_name
+ORDER BY open_tasks DESC, open_estimate DESC, t.assignee_id
+LIMIT $2
+`
+
+type ListTopAssigneesParams struct {
+	ProjectID int64 `json:"project_id"`
+	Top       int32 `json:"top"`
+}
+
+type ListTopAssigneesRow struct {
+	AssigneeID   int64   `json:"assignee_id"`
+	FullName     string  `json:"full_name"`
+	OpenTasks    int64   `json:"open_tasks"`
+	OpenEstimate float64 `json:"open_estimate"`
+}
+
+// Lists the assignees of a project with the most open tasks, ties go to the
+// larger sum of estimates
+func (q *Queries) ListTopAssignees(ctx context.Context, arg ListTopAssigneesParams) ([]ListTopAssigneesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTopAssignees, arg.ProjectID, arg.Top)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListTopAssigneesRow{}
+	for rows.Next() {
+		var i ListTopAssigneesRow
+		if err := rows.Scan(
+			&i.AssigneeID,
+			&i.FullName,
+			&i.OpenTasks,
+			&i.OpenEstimate,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWorkload = `-- name: ListWorkload :many
+SELECT u.id AS user_id, u.full_name, u.weekly_capacity,
+    count(o.id) AS open_tasks,
+    count(o.id) FILTER (WHERE o.priority = 'high') AS high,
+    count(o.id) FILTER (WHERE o.priority = 'medium') AS medium,
+    count(o.id) FILTER (WHERE o.priority = 'low') AS low,
+    count(o.id) FILTER (WHERE o.estimate IS NULL) AS unestimated,
+    COALESCE(sum(o.estimate), 0)::double precision AS open_estimate,
+    count(DISTINCT o.project_id) AS projects
+FROM users u
+LEFT JOIN (
+    SELECT t.id, t.assignee_id, t.project_id, t.priority, t.estimate
+    FROM tasks t
+    JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+    WHERE ws.category <> 'done'
+        AND ($1::boolean
+            OR t.project_id IN (SELECT pm.project_id FROM project_members pm WHERE pm.user_id = $2))
+) o ON o.assignee_id = u.id
+WHERE ($3::bigint IS NULL OR u.id = $3::bigint)
+    AND ($1::boolean
+        OR u.id = $2
+        OR u.id IN (
+            SELECT pm.user_id FROM project_members pm
+            WHERE pm.project_id IN (SELECT own.project_id FROM project_members own WHERE own.user_id = $2)
+        ))
+GROUP BY u.id
+ORDER BY open_estimate DESC, open_tasks DESC, u.id
+`
+
+type ListWorkloadParams struct {
+	ViewerIsAdmin bool          `json:"viewer_is_admin"`
+	ViewerID      int64         `json:"viewer_id"`
+	UserID        sql.NullInt64 `json:"user_id"`
+}
+
+type ListWorkloadRow struct {
+	UserID         int64           `json:"user_id"`
+	FullName       string          `json:"full_name"`
+	WeeklyCapacity sql.NullFloat64 `json:"weekly_capacity"`
+	OpenTasks      int64           `json:"open_tasks"`
+	High           int64           `json:"high"`
+	Medium         int64           `json:"medium"`
+	Low            int64           `json:"low"`
+	Unestimated    int64           `json:"unestimated"`
+	OpenEstimate   float64         `json:"open_estimate"`
+	Projects       int64           `json:"projects"`
+}
+
+// Sums the open tasks assigned to every user, or to the one user_id names,
+// across the projects the viewer is a member of. Viewers other than admins
+// only see themselves and the members of their projects. The busiest users
+// come first.
+func (q *Queries) ListWorkload(ctx context.Context, arg ListWorkloadParams) ([]ListWorkloadRow, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkload, arg.ViewerIsAdmin, arg.ViewerID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListWorkloadRow{}
+	for rows.Next() {
+		var i ListWorkloadRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.FullName,
+			&i.WeeklyCapacity,
+			&i.OpenTasks,
+			&i.High,
+			&i.Medium,
+			&i.Low,
+			&i.Unestimated,
+			&i.OpenEstimate,
+			&i.Projects,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetBurndown(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	from := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	sprintID := sql.NullInt64{Int64: 3, Valid: true}
+
+	rows := sqlmock.NewRows([]string{"day", "tasks", "completed", "estimate", "completed_estimate"}).
+		AddRow(from, 4, 0, 10.0, 0.0).
+		AddRow(to, 4, 1, 10.0, 2.5)
+
+	mock.ExpectQuery("WITH days AS \\( SELECT generate_series\\(\\$1::date, \\$2::date, interval '1 day'\\)::date AS day \\)(.+)FROM task_status_changes h(.+)GROUP BY days.day ORDER BY days.day").
+		WithArgs(from, to, int64(2), sprintID).
+		WillReturnRows(rows)
+
+	points, err := queries.GetBurndown(context.Background(), GetBurndownParams{
+		FromDate:  from,
+		ToDate:    to,
+		ProjectID: 2,
+		SprintID:  sprintID,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, int64(1), points[1].Completed)
+	assert.Equal(t, 2.5, points[1].CompletedEstimate)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetProjectTaskTotals(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"tasks", "completed", "overdue"}).AddRow(12, 5, 2)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks t JOIN workflow_statuses ws (.+) WHERE t.project_id = \\$1").
+		WithArgs(int64(2)).
+		WillReturnRows(rows)
+
+	totals, err := queries.GetProjectTaskTotals(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, GetProjectTaskTotalsRow{Tasks: 12, Completed: 5, Overdue: 2}, totals)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestListTopAssignees(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"assignee_id", "full_name", "open_tasks", "open_estimate"}).
+		AddRow(3, "Ada Lovelace", 4, 13.5).
+		AddRow(5, "Alan Turing", 4, 8.0)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks t JOIN users u ON u.id = t.assignee_id (.+) ORDER BY open_tasks DESC, open_estimate DESC, t.assignee_id LIMIT \\$2").
+		WithArgs(int64(2), int32(5)).
+		WillReturnRows(rows)
+
+	assignees, err := queries.ListTopAssignees(context.Background(), ListTopAssigneesParams{ProjectID: 2, Top: 5})
+
+	assert.NoError(t, err)
+	assert.Len(t, assignees, 2)
+	assert.Equal(t, "Ada Lovelace", assignees[0].FullName)
+	assert.Equal(t, 13.5, assignees[0].OpenEstimate)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestListWorkload(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"user_id", "full_name", "weekly_capacity", "open_tasks", "high", "medium", "low", "unestimated", "open_estimate", "projects"}).
+		AddRow(3, "Ada Lovelace", 30.0, 6, 2, 3, 1, 1, 42.5, 2).
+		AddRow(5, "Alan Turing", nil, 0, 0, 0, 0, 0, 0.0, 0)
+
+	mock.ExpectQuery("SELECT (.+) FROM users u LEFT JOIN \\( SELECT (.+) FROM tasks t (.+) \\) o ON o.assignee_id = u.id WHERE \\(\\$3::bigint IS NULL OR u.id = \\$3::bigint\\) AND \\(\\$1::boolean OR u.id = \\$2 OR u.id IN \\( SELECT pm.user_id FROM project_members pm (.+) \\)\\) GROUP BY u.id").
+		WithArgs(false, int64(3), sql.NullInt64{}).
+		WillReturnRows(rows)
+
+	workloads, err := queries.ListWorkload(context.Background(), ListWorkloadParams{ViewerID: 3})
+
+	assert.NoError(t, err)
+	assert.Len(t, workloads, 2)
+	assert.Equal(t, sql.NullFloat64{Float64: 30, Valid: true}, workloads[0].WeeklyCapacity)
+	assert.Equal(t, int64(2), workloads[0].High)
+	assert.False(t, workloads[1].WeeklyCapacity.Valid)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
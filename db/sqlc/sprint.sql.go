@@ -0,0 +1,394 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: sprint.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createSprint = `-- name: CreateSprint :one
+INSERT INTO sprints (
+    project_id, name, goal, start_date, end_date, capacity
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING id, project_id, name, goal, start_date, end_date, capacity, state, closed_at
+`
+
+type CreateSprintParams struct {
+	ProjectID int64           `json:"project_id"`
+	Name      string          `json:"name"`
+	Goal      string          `json:"goal"`
+	StartDate time.Time       `json:"start_date"`
+	EndDate   time.Time       `json:"end_date"`
+	Capacity  sql.NullFloat64 `json:"capacity"`
+}
+
+func (q *Queries) CreateSprint(ctx context.Context, arg CreateSprintParams) (Sprint, error) {
+	row := q.db.QueryRowContext(ctx, createSprint,
+		arg.ProjectID,
+		arg.Name,
+		arg.Goal,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Capacity,
+	)
+	var i Sprint
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Goal,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Capacity,
+		&i.State,
+		&i.ClosedAt,
+	)
+	return i, err
+}
+
+const deleteSprint = `-- name: DeleteSprint :exec
+DELETE FROM sprints
+WHERE project_id = $1 AND id = $2
+`
+
+type DeleteSprintParams struct {
+	ProjectID int64 `json:"project_id"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) DeleteSprint(ctx context.Context, arg DeleteSprintParams) error {
+	_, err := q.db.ExecContext(ctx, deleteSprint, arg.ProjectID, arg.ID)
+	return err
+}
+
+const getSprint = `-- name: GetSprint :one
+SELECT id, project_id, name, goal, start_date, end_date, capacity, state, closed_at FROM sprints
+WHERE project_id = $1 AND id = $2 LIMIT 1
+`
+
+type GetSprintParams struct {
+	ProjectID int64 `json:"project_id"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) GetSprint(ctx context.Context, arg GetSprintParams) (Sprint, error) {
+	row := q.db.QueryRowContext(ctx, getSprint, arg.ProjectID, arg.ID)
+	var i Sprint
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Goal,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Capacity,
+		&i.State,
+		&i.ClosedAt,
+	)
+	return i, err
+}
+
+const getSprintSummary = `-- name: GetSprintSummary :one
+SELECT sprints.id, sprints.project_id, sprints.name, sprints.goal, sprints.start_date, sprints.end_date, sprints.capacity, sprints.state, sprints.closed_at,
+    count(t.id) AS tasks,
+    count(t.id) FILTER (WHERE ws.category = 'done') AS completed,
+    COALESCE(sum(t.estimate), 0)::double precision AS committed
+FROM sprints
+LEFT JOIN tasks t ON t.sprint_id = sprints.id
+LEFT JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE sprints.project_id = $1 AND sprints.id = $2
+GROUP BY sprints.id
+`
+
+type GetSprintSummaryParams struct {
+	ProjectID int64 `json:"project_id"`
+	ID        int64 `json:"id"`
+}
+
+type GetSprintSummaryRow struct {
+	Sprint    Sprint  `json:"sprint"`
+	Tasks     int64   `json:"tasks"`
+	Completed int64   `json:"completed"`
+	Committed float64 `json:"committed"`
+}
+
+func (q *Queries) GetSprintSummary(ctx context.Context, arg GetSprintSummaryParams) (GetSprintSummaryRow, error) {
+	row := q.db.QueryRowContext(ctx, getSprintSummary, arg.ProjectID, arg.ID)
+	var i GetSprintSummaryRow
+	err := row.Scan(
+		&i.Sprint.ID,
+		&i.Sprint.ProjectID,
+		&i.Sprint.Name,
+		&i.Sprint.Goal,
+		&i.Sprint.StartDate,
+		&i.Sprint.EndDate,
+		&i.Sprint.Capacity,
+		&i.Sprint.State,
+		&i.Sprint.ClosedAt,
+		&i.Tasks,
+		&i.Completed,
+		&i.Committed,
+	)
+	return i, err
+}
+
+const listSprintTasks = `-- name: ListSprintTasks :many
+SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date, version, parent_task_id, blocked, due_date, estimate, sprint_id, milestone_id FROM tasks
+WHERE sprint_id = $1::bigint
+ORDER BY creation_date, id
+`
+
+func (q *Queries) ListSprintTasks(ctx context.Context, sprintID int64) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, listSprintTasks, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Priority,
+			&i.Status,
+			&i.AssigneeID,
+			&i.ProjectID,
+			&i.CreationDate,
+			&i.CompletionDate,
+			&i.Version,
+			&i.ParentTaskID,
+			&i.Blocked,
+			&i.DueDate,
+			&i.Estimate,
+			&i.SprintID,
+			&i.MilestoneID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSprints = `-- name: ListSprints :many
+SELECT sprints.id, sprints.project_id, sprints.name, sprints.goal, sprints.start_date, sprints.end_date, sprints.capacity, sprints.state, sprints.closed_at,
+    count(t.id) AS tasks,
+    count(t.id) FILTER (WHERE ws.category = 'done') AS completed,
+    COALESCE(sum(t.estimate), 0)::double precision AS committed
+FROM sprints
+LEFT JOIN tasks t ON t.sprint_id = sprints.id
+LEFT JOIN workflow_statuses ws ON ws.project_id = t.project_id AND ws.name = t.status
+WHERE sprints.project_id = $1
+GROUP BY sprints.id
+ORDER BY sprints.start_date, sprints.id
+`
+
+type ListSprintsRow struct {
+	Sprint    Sprint  `json:"sprint"`
+	Tasks     int64   `json:"tasks"`
+	Completed int64   `json:"completed"`
+	Committed float64 `json:"committed"`
+}
+
+func (q *Queries) ListSprints(ctx context.Context, projectID int64) ([]ListSprintsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSprints, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListSprintsRow{}
+	for rows.Next() {
+		var i ListSprintsRow
+		if err := rows.Scan(
+			&i.Sprint.ID,
+			&i.Sprint.ProjectID,
+			&i.Sprint.Name,
+			&i.Sprint.Goal,
+			&i.Sprint.StartDate,
+			&i.Sprint.EndDate,
+			&i.Sprint.Capacity,
+			&i.Sprint.State,
+			&i.Sprint.ClosedAt,
+			&i.Tasks,
+			&i.Completed,
+			&i.Committed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const moveUnfinishedTasks = `-- name: MoveUnfinishedTasks :execrows
+UPDATE tasks
+SET
+    sprint_id = $1,
+    version = version + 1
+WHERE tasks.sprint_id = $2::bigint
+    AND (tasks.project_id, tasks.status) IN (
+        SELECT ws.project_id, ws.name FROM workflow_statuses ws WHERE ws.category <> 'done'
+    )
+`
+
+type MoveUnfinishedTasksParams struct {
+	ToSprintID   sql.NullInt64 `json:"to_sprint_id"`
+	FromSprintID int64         `json:"from_sprint_id"`
+}
+
+// Moves the tasks of a sprint that are not done to another sprint of the
+// project, or to the backlog when to_sprint_id is null
+func (q *Queries) MoveUnfinishedTasks(ctx context.Context, arg MoveUnfinishedTasksParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, moveUnfinishedTasks, arg.ToSprintID, arg.FromSprintID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setSprintState = `-- name: SetSprintState :one
+UPDATE sprints
+SET
+    state = $1,
+    closed_at = CASE WHEN $1 = 'closed'::sprint_state THEN now() END
+WHERE project_id = $2 AND id = $3 AND state = $4
+RETURNING id, project_id, name, goal, start_date, end_date, capacity, state, closed_at
+`
+
+type SetSprintStateParams struct {
+	State     SprintState `json:"state"`
+	ProjectID int64       `json:"project_id"`
+	ID        int64       `json:"id"`
+	Expected  SprintState `json:"expected"`
+}
+
+// Moves the sprint to the state when it is in the expected one, closing it
+// records the time
+func (q *Queries) SetSprintState(ctx context.Context, arg SetSprintStateParams) (Sprint, error) {
+	row := q.db.QueryRowContext(ctx, setSprintState,
+		arg.State,
+		arg.ProjectID,
+		arg.ID,
+		arg.Expected,
+	)
+	var i Sprint
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Goal,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Capacity,
+		&i.State,
+		&i.ClosedAt,
+	)
+	return i, err
+}
+
+const setTaskSprint = `-- name: SetTaskSprint :one
+UPDATE tasks
+SET
+    sprint_id = $1,
+    version = version + 1
+WHERE project_id = $2 AND id = $3
+RETURNING id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date, version, parent_task_id, blocked, due_date, estimate, sprint_id, milestone_id
+`
+
+type SetTaskSprintParams struct {
+	SprintID  sql.NullInt64 `json:"sprint_id"`
+	ProjectID int64         `json:"project_id"`
+	ID        int64         `json:"id"`
+}
+
+func (q *Queries) SetTaskSprint(ctx context.Context, arg SetTaskSprintParams) (Task, error) {
+	row := q.db.QueryRowContext(ctx, setTaskSprint, arg.SprintID, arg.ProjectID, arg.ID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Priority,
+		&i.Status,
+		&i.AssigneeID,
+		&i.ProjectID,
+		&i.CreationDate,
+		&i.CompletionDate,
+		&i.Version,
+		&i.ParentTaskID,
+		&i.Blocked,
+		&i.DueDate,
+		&i.Estimate,
+		&i.SprintID,
+		&i.MilestoneID,
+	)
+	return i, err
+}
+
+const updateSprint = `-- name: UpdateSprint :one
+UPDATE sprints
+SET
+    name = $3,
+    goal = $4,
+    start_date = $5,
+    end_date = $6,
+    capacity = $7
+WHERE project_id = $1 AND id = $2
+RETURNING id, project_id, name, goal, start_date, end_date, capacity, state, closed_at
+`
+
+type UpdateSprintParams struct {
+	ProjectID int64           `json:"project_id"`
+	ID        int64           `json:"id"`
+	Name      string          `json:"name"`
+	Goal      string          `json:"goal"`
+	StartDate time.Time       `json:"start_date"`
+	EndDate   time.Time       `json:"end_date"`
+	Capacity  sql.NullFloat64 `json:"capacity"`
+}
+
+func (q *Queries) UpdateSprint(ctx context.Context, arg UpdateSprintParams) (Sprint, error) {
+	row := q.db.QueryRowContext(ctx, updateSprint,
+		arg.ProjectID,
+		arg.ID,
+		arg.Name,
+		arg.Goal,
+		arg.StartDate,
+		arg.EndDate,
+		arg.Capacity,
+	)
+	var i Sprint
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Goal,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Capacity,
+		&i.State,
+		&i.ClosedAt,
+	)
+	return i, err
+}
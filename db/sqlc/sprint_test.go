@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListSprints(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	start := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "project_id", "name", "goal", "start_date", "end_date", "capacity", "state", "closed_at", "tasks", "completed", "committed"}).
+		AddRow(1, 2, "Sprint 1", "Checkout", start, start.AddDate(0, 0, 13), 40.0, "active", nil, 5, 2, 42.5).
+		AddRow(2, 2, "Sprint 2", "", start.AddDate(0, 0, 14), start.AddDate(0, 0, 27), nil, "planned", nil, 0, 0, 0.0)
+
+	mock.ExpectQuery("SELECT (.+) FROM sprints LEFT JOIN tasks t ON t.sprint_id = sprints.id (.+) WHERE sprints.project_id = \\$1 GROUP BY sprints.id").
+		WithArgs(int64(2)).
+		WillReturnRows(rows)
+
+	sprints, err := queries.ListSprints(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, sprints, 2)
+	assert.Equal(t, SprintStateActive, sprints[0].Sprint.State)
+	assert.Equal(t, int64(5), sprints[0].Tasks)
+	assert.Equal(t, 42.5, sprints[0].Committed)
+	assert.False(t, sprints[1].Sprint.Capacity.Valid)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSetTaskSprint(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+		AddRow(7, "Task", "Description", "high", "todo", 3, 2, time.Now(), nil, 2, nil, false, nil, nil, 1, nil)
+
+	mock.ExpectQuery("UPDATE tasks SET sprint_id = \\$1, version = version \\+ 1 WHERE project_id = \\$2 AND id = \\$3").
+		WithArgs(sql.NullInt64{Int64: 1, Valid: true}, int64(2), int64(7)).
+		WillReturnRows(rows)
+
+	task, err := queries.SetTaskSprint(context.Background(), SetTaskSprintParams{
+		SprintID:  sql.NullInt64{Int64: 1, Valid: true},
+		ProjectID: 2,
+		ID:        7,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, sql.NullInt64{Int64: 1, Valid: true}, task.SprintID)
+	assert.Equal(t, int64(2), task.Version)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,284 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDependencyCycle is returned when a new dependency would make a task wait
+// on itself
+var ErrDependencyCycle = errors.New("the blocking task already waits on this task")
+
+// Store provides all functions to execute db queries and transactions
+type Store struct {
+	*Queries
+	db *sql.DB
+}
+
+// NewStore creates a new Store
+func NewStore(db *sql.DB) *Store {
+	return &Store{
+		db:      db,
+		Queries: New(db),
+	}
+}
+
+// execTx executes a function within a database transaction
+func (store *Store) execTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := New(tx)
+	err = fn(q)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateProjectTx creates a project with the default workflow statuses and
+// registers its manager as a project member
+func (store *Store) CreateProjectTx(ctx context.Context, arg CreateProjectParams) (Project, error) {
+	var project Project
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		project, err = q.CreateProject(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddProjectMember(ctx, AddProjectMemberParams{
+			ProjectID: project.ID,
+			UserID:    project.ManagerID,
+			Role:      ProjectRoleManager,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.CreateDefaultWorkflowStatuses(ctx, project.ID)
+	})
+
+	return project, err
+}
+
+// UpdateProjectTx updates a project and hands it over to its manager, see
+// handOverProject
+func (store *Store) UpdateProjectTx(ctx context.Context, arg UpdateProjectParams) (Project, error) {
+	var project Project
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		previous, err := q.GetProject(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		project, err = q.UpdateProject(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return handOverProject(ctx, q, previous.ManagerID, project)
+	})
+
+	return project, err
+}
+
+// PatchProjectTx changes the given fields of a project and hands it over to
+// its manager, see handOverProject
+func (store *Store) PatchProjectTx(ctx context.Context, arg PatchProjectParams) (Project, error) {
+	var project Project
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		previous, err := q.GetProject(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		project, err = q.PatchProject(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return handOverProject(ctx, q, previous.ManagerID, project)
+	})
+
+	return project, err
+}
+
+// handOverProject makes sure the manager of the project is a member with the
+// manager role. A previous manager stays on the project as a regular member.
+func handOverProject(ctx context.Context, q *Queries, previousManagerID int64, project Project) error {
+	if previousManagerID != project.ManagerID {
+		err := q.DemoteProjectManager(ctx, DemoteProjectManagerParams{ProjectID: project.ID, UserID: previousManagerID})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := q.AddProjectMember(ctx, AddProjectMemberParams{
+		ProjectID: project.ID,
+		UserID:    project.ManagerID,
+		Role:      ProjectRoleManager,
+	})
+	return err
+}
+
+// CloseSprintTxParams names the active sprint to close and the sprint its
+// unfinished tasks move to, they go back to the backlog when NextSprintID is
+// not set
+type CloseSprintTxParams struct {
+	ProjectID    int64         `json:"project_id"`
+	SprintID     int64         `json:"sprint_id"`
+	NextSprintID sql.NullInt64 `json:"next_sprint_id"`
+}
+
+// CloseSprintTxResult is the closed sprint with the number of unfinished
+// tasks moved out of it
+type CloseSprintTxResult struct {
+	Sprint      Sprint `json:"sprint"`
+	CarriedOver int64  `json:"carried_over"`
+}
+
+// CloseSprintTx closes the active sprint and carries its unfinished tasks
+// over to the next sprint or the backlog. It fails with sql.ErrNoRows when
+// the sprint is not active.
+func (store *Store) CloseSprintTx(ctx context.Context, arg CloseSprintTxParams) (CloseSprintTxResult, error) {
+	var result CloseSprintTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Sprint, err = q.SetSprintState(ctx, SetSprintStateParams{
+			State:     SprintStateClosed,
+			ProjectID: arg.ProjectID,
+			ID:        arg.SprintID,
+			Expected:  SprintStateActive,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.CarriedOver, err = q.MoveUnfinishedTasks(ctx, MoveUnfinishedTasksParams{
+			ToSprintID:   arg.NextSprintID,
+			FromSprintID: arg.SprintID,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// UpdateTaskTx updates a task and detaches the labels of its old project when
+// it moves to another one
+func (store *Store) UpdateTaskTx(ctx context.Context, arg UpdateTaskParams) (Task, error) {
+	var task Task
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.DetachForeignLabels(ctx, DetachForeignLabelsParams{TaskID: arg.ID, ProjectID: arg.ProjectID})
+		if err != nil {
+			return err
+		}
+
+		task, err = q.UpdateTask(ctx, arg)
+		return err
+	})
+
+	return task, err
+}
+
+// PatchTaskTx changes the given fields of a task and detaches the labels of
+// its old project when it moves to another one
+func (store *Store) PatchTaskTx(ctx context.Context, arg PatchTaskParams) (Task, error) {
+	var task Task
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if arg.ProjectID.Valid {
+			err := q.DetachForeignLabels(ctx, DetachForeignLabelsParams{TaskID: arg.ID, ProjectID: arg.ProjectID.Int64})
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		task, err = q.PatchTask(ctx, arg)
+		return err
+	})
+
+	return task, err
+}
+
+// AddTaskDependencyTxParams names a task of the project and the task it
+// starts waiting on
+type AddTaskDependencyTxParams struct {
+	ProjectID int64 `json:"project_id"`
+	BlockerID int64 `json:"blocker_id"`
+	BlockedID int64 `json:"blocked_id"`
+}
+
+// AddTaskDependencyTx makes a task wait on another task of its project. The
+// dependencies of the project are locked while the cycle check runs, it fails
+// with ErrDependencyCycle when the blocker already waits on the task.
+func (store *Store) AddTaskDependencyTx(ctx context.Context, arg AddTaskDependencyTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.LockProjectDependencies(ctx, arg.ProjectID); err != nil {
+			return err
+		}
+
+		cycle, err := q.DependsOn(ctx, DependsOnParams{TaskID: arg.BlockerID, BlockerID: arg.BlockedID})
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return ErrDependencyCycle
+		}
+
+		return q.AddTaskDependency(ctx, AddTaskDependencyParams{BlockerID: arg.BlockerID, BlockedID: arg.BlockedID})
+	})
+}
+
+// UpdateCommentTxResult is the edited comment with the times of all its
+// edits, oldest first
+type UpdateCommentTxResult struct {
+	Comment Comment     `json:"comment"`
+	Edits   []time.Time `json:"edits"`
+}
+
+// UpdateCommentTx changes the text of a comment and records the edit in its
+// history
+func (store *Store) UpdateCommentTx(ctx context.Context, arg UpdateCommentParams) (UpdateCommentTxResult, error) {
+	var result UpdateCommentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Comment, err = q.UpdateComment(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		err = q.AddCommentEdit(ctx, AddCommentEditParams{
+			CommentID: result.Comment.ID,
+			EditedAt:  result.Comment.UpdatedAt.Time,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Edits, err = q.ListCommentEdits(ctx, result.Comment.ID)
+		return err
+	})
+
+	return result, err
+}
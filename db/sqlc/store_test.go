@@ -0,0 +1,403 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateProjectTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	now := time.Now()
+	params := CreateProjectParams{
+		Name:        "Test Project",
+		Description: "Description",
+		StartDate:   now,
+		EndDate:     now.AddDate(0, 1, 0),
+		ManagerID:   123,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO projects").
+		WithArgs(params.Name, params.Description, params.StartDate, params.EndDate, params.ManagerID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "start_date", "end_date", "manager_id", "version"}).
+			AddRow(1, params.Name, params.Description, params.StartDate, params.EndDate, params.ManagerID, 1))
+	mock.ExpectQuery("INSERT INTO project_members").
+		WithArgs(int64(1), int64(123), ProjectRoleManager).
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "user_id", "role", "added_at"}).
+			AddRow(1, 123, ProjectRoleManager, now))
+	mock.ExpectExec("INSERT INTO workflow_statuses").
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	project, err := store.CreateProjectTx(context.Background(), params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), project.ID)
+	assert.Equal(t, int64(123), project.ManagerID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCreateProjectTxRollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	now := time.Now()
+	params := CreateProjectParams{
+		Name:        "Test Project",
+		Description: "Description",
+		StartDate:   now,
+		EndDate:     now.AddDate(0, 1, 0),
+		ManagerID:   123,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT INTO projects").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "start_date", "end_date", "manager_id", "version"}).
+			AddRow(1, params.Name, params.Description, params.StartDate, params.EndDate, params.ManagerID, 1))
+	mock.ExpectQuery("INSERT INTO project_members").
+		WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	_, err = store.CreateProjectTx(context.Background(), params)
+	assert.EqualError(t, err, "insert failed")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPatchProjectTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	now := time.Now()
+	params := PatchProjectParams{
+		ID:        1,
+		ManagerID: sql.NullInt64{Int64: 7, Valid: true},
+		Version:   1,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM projects WHERE id = \\$1").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "start_date", "end_date", "manager_id", "version"}).
+			AddRow(1, "Project", "Description", now, now.AddDate(0, 1, 0), 3, 1))
+	mock.ExpectQuery("UPDATE projects SET (.+) WHERE id = \\$6 AND version = \\$7").
+		WithArgs(sql.NullString{}, sql.NullString{}, sql.NullTime{}, sql.NullTime{}, params.ManagerID, int64(1), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "start_date", "end_date", "manager_id", "version"}).
+			AddRow(1, "Project", "Description", now, now.AddDate(0, 1, 0), 7, 2))
+	mock.ExpectExec("UPDATE project_members SET role = 'member' WHERE project_id = \\$1 AND user_id = \\$2 AND role = 'manager'").
+		WithArgs(int64(1), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO project_members").
+		WithArgs(int64(1), int64(7), ProjectRoleManager).
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "user_id", "role", "added_at"}).
+			AddRow(1, 7, ProjectRoleManager, now))
+	mock.ExpectCommit()
+
+	project, err := store.PatchProjectTx(context.Background(), params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Project", project.Name)
+	assert.Equal(t, int64(7), project.ManagerID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUpdateProjectTxSameManager(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	now := time.Now()
+	params := UpdateProjectParams{
+		ID:          1,
+		Name:        "Renamed",
+		Description: "Description",
+		StartDate:   now,
+		EndDate:     now.AddDate(0, 1, 0),
+		ManagerID:   7,
+		Version:     1,
+	}
+
+	// Nobody is demoted when the manager stays the same
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM projects WHERE id = \\$1").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "start_date", "end_date", "manager_id", "version"}).
+			AddRow(1, "Project", "Description", now, now.AddDate(0, 1, 0), 7, 1))
+	mock.ExpectQuery("UPDATE projects SET (.+) WHERE id = \\$1 AND version = \\$7").
+		WithArgs(params.ID, params.Name, params.Description, params.StartDate, params.EndDate, params.ManagerID, params.Version).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "start_date", "end_date", "manager_id", "version"}).
+			AddRow(1, params.Name, params.Description, params.StartDate, params.EndDate, 7, 2))
+	mock.ExpectQuery("INSERT INTO project_members").
+		WithArgs(int64(1), int64(7), ProjectRoleManager).
+		WillReturnRows(sqlmock.NewRows([]string{"project_id", "user_id", "role", "added_at"}).
+			AddRow(1, 7, ProjectRoleManager, now))
+	mock.ExpectCommit()
+
+	project, err := store.UpdateProjectTx(context.Background(), params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", project.Name)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCloseSprintTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	now := time.Now()
+	params := CloseSprintTxParams{
+		ProjectID:    1,
+		SprintID:     2,
+		NextSprintID: sql.NullInt64{Int64: 3, Valid: true},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE sprints SET (.+) WHERE project_id = \\$2 AND id = \\$3 AND state = \\$4").
+		WithArgs(SprintStateClosed, int64(1), int64(2), SprintStateActive).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "project_id", "name", "goal", "start_date", "end_date", "capacity", "state", "closed_at"}).
+			AddRow(2, 1, "Sprint 1", "", now.AddDate(0, 0, -14), now, nil, SprintStateClosed, now))
+	mock.ExpectExec("UPDATE tasks SET sprint_id = \\$1, (.+) WHERE tasks.sprint_id = \\$2").
+		WithArgs(params.NextSprintID, int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	result, err := store.CloseSprintTx(context.Background(), params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, SprintStateClosed, result.Sprint.State)
+	assert.Equal(t, int64(4), result.CarriedOver)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCloseSprintTxNotActive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE sprints SET").
+		WithArgs(SprintStateClosed, int64(1), int64(2), SprintStateActive).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err = store.CloseSprintTx(context.Background(), CloseSprintTxParams{ProjectID: 1, SprintID: 2})
+
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPatchTaskTxMovesLabels(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	now := time.Now()
+	params := PatchTaskParams{
+		ID:                1,
+		ProjectID:         sql.NullInt64{Int64: 9, Valid: true},
+		SetCompletionDate: true,
+		Version:           2,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM task_labels WHERE task_id = \\$1 AND project_id <> \\$2").
+		WithArgs(int64(1), int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("UPDATE tasks SET (.+) WHERE id = \\$17 AND version = \\$18").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+			AddRow(1, "Task", "Description", TaskPriorityHigh, "new", int64(123), int64(9), now, nil, 3, nil, false, nil, nil, nil, nil))
+	mock.ExpectCommit()
+
+	task, err := store.PatchTaskTx(context.Background(), params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), task.ProjectID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUpdateTaskTxConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	params := UpdateTaskParams{
+		ID:        1,
+		Title:     "Task",
+		Priority:  TaskPriorityHigh,
+		Status:    "new",
+		ProjectID: 9,
+		Version:   2,
+	}
+
+	// The labels stay attached when the task changed in the meantime
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM task_labels").
+		WithArgs(int64(1), int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("UPDATE tasks").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err = store.UpdateTaskTx(context.Background(), params)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAddTaskDependencyTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock\\(\\$1::bigint\\)").
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("WITH RECURSIVE upstream AS (.+) SELECT EXISTS").
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec("INSERT INTO task_dependencies").
+		WithArgs(int64(2), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = store.AddTaskDependencyTx(context.Background(), AddTaskDependencyTxParams{ProjectID: 4, BlockerID: 2, BlockedID: 1})
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAddTaskDependencyTxCycle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("WITH RECURSIVE upstream AS").
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	err = store.AddTaskDependencyTx(context.Background(), AddTaskDependencyTxParams{ProjectID: 4, BlockerID: 2, BlockedID: 1})
+	assert.ErrorIs(t, err, ErrDependencyCycle)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUpdateCommentTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	store := NewStore(db)
+
+	now := time.Now()
+	edited := now.Add(time.Hour)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE comments SET body = \\$3, updated_at = now\\(\\)").
+		WithArgs(int64(1), int64(3), "Ready for review now").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_id", "author_id", "parent_id", "body", "created_at", "updated_at"}).
+			AddRow(3, 1, 5, nil, "Ready for review now", now, edited))
+	mock.ExpectExec("INSERT INTO comment_edits").
+		WithArgs(int64(3), edited).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT edited_at FROM comment_edits WHERE comment_id = \\$1 ORDER BY edited_at").
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"edited_at"}).AddRow(now.Add(time.Minute)).AddRow(edited))
+	mock.ExpectCommit()
+
+	result, err := store.UpdateCommentTx(context.Background(), UpdateCommentParams{TaskID: 1, ID: 3, Body: "Ready for review now"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Ready for review now", result.Comment.Body)
+	assert.Equal(t, []time.Time{now.Add(time.Minute), edited}, result.Edits)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
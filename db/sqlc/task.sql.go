@@ -8,25 +8,61 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
+const countDueOutsideDates = `-- name: CountDueOutsideDates :one
+SELECT
+    COUNT(*) FILTER (WHERE due.due_date < $1::date) AS before_start,
+    COUNT(*) FILTER (WHERE due.due_date > $2::date) AS after_end
+FROM (
+    SELECT t.due_date FROM tasks t WHERE t.project_id = $3 AND t.due_date IS NOT NULL
+    UNION ALL
+    SELECT m.due_date FROM milestones m WHERE m.project_id = $3
+) due
+`
+
+type CountDueOutsideDatesParams struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	ProjectID int64     `json:"project_id"`
+}
+
+type CountDueOutsideDatesRow struct {
+	BeforeStart int64 `json:"before_start"`
+	AfterEnd    int64 `json:"after_end"`
+}
+
+// Counts the tasks and milestones of a project due before the start date or
+// after the end date
+func (q *Queries) CountDueOutsideDates(ctx context.Context, arg CountDueOutsideDatesParams) (CountDueOutsideDatesRow, error) {
+	row := q.db.QueryRowContext(ctx, countDueOutsideDates, arg.StartDate, arg.EndDate, arg.ProjectID)
+	var i CountDueOutsideDatesRow
+	err := row.Scan(&i.BeforeStart, &i.AfterEnd)
+	return i, err
+}
+
 const createTask = `-- name: CreateTask :one
 INSERT INTO tasks (
-    title, description, priority, status, assignee_id, project_id, completion_date
+    title, description, priority, status, assignee_id, project_id, completion_date, parent_task_id, due_date, estimate, milestone_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
-RETURNING id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date
+RETURNING id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date, version, parent_task_id, blocked, due_date, estimate, sprint_id, milestone_id
 `
 
 type CreateTaskParams struct {
-	Title          string       `json:"title"`
-	Description    string       `json:"description"`
-	Priority       TaskPriority `json:"priority"`
-	Status         TaskStatus   `json:"status"`
-	AssigneeID     int64        `json:"assignee_id"`
-	ProjectID      int64        `json:"project_id"`
-	CompletionDate sql.NullTime `json:"completion_date"`
+	Title          string          `json:"title"`
+	Description    string          `json:"description"`
+	Priority       TaskPriority    `json:"priority"`
+	Status         string          `json:"status"`
+	AssigneeID     int64           `json:"assignee_id"`
+	ProjectID      int64           `json:"project_id"`
+	CompletionDate sql.NullTime    `json:"completion_date"`
+	ParentTaskID   sql.NullInt64   `json:"parent_task_id"`
+	DueDate        sql.NullTime    `json:"due_date"`
+	Estimate       sql.NullFloat64 `json:"estimate"`
+	MilestoneID    sql.NullInt64   `json:"milestone_id"`
 }
 
 func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, error) {
@@ -38,6 +74,10 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		arg.AssigneeID,
 		arg.ProjectID,
 		arg.CompletionDate,
+		arg.ParentTaskID,
+		arg.DueDate,
+		arg.Estimate,
+		arg.MilestoneID,
 	)
 	var i Task
 	err := row.Scan(
@@ -50,6 +90,13 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (Task, e
 		&i.ProjectID,
 		&i.CreationDate,
 		&i.CompletionDate,
+		&i.Version,
+		&i.ParentTaskID,
+		&i.Blocked,
+		&i.DueDate,
+		&i.Estimate,
+		&i.SprintID,
+		&i.MilestoneID,
 	)
 	return i, err
 }
@@ -65,7 +112,7 @@ func (q *Queries) DeleteTask(ctx context.Context, id int64) error {
 }
 
 const getTask = `-- name: GetTask :one
-SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date FROM tasks
+SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date, version, parent_task_id, blocked, due_date, estimate, sprint_id, milestone_id FROM tasks
 WHERE id = $1 LIMIT 1
 `
 
@@ -82,134 +129,84 @@ func (q *Queries) GetTask(ctx context.Context, id int64) (Task, error) {
 		&i.ProjectID,
 		&i.CreationDate,
 		&i.CompletionDate,
+		&i.Version,
+		&i.ParentTaskID,
+		&i.Blocked,
+		&i.DueDate,
+		&i.Estimate,
+		&i.SprintID,
+		&i.MilestoneID,
 	)
 	return i, err
 }
 
-const listTasks = `-- name: ListTasks :many
-SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date FROM tasks
-ORDER BY creation_date ASC
+const getTaskRollup = `-- name: GetTaskRollup :one
+WITH RECURSIVE subtree AS (
+    SELECT t.id, ARRAY[t.id] AS path
+    FROM tasks t
+    WHERE t.parent_task_id = $1::bigint
+    UNION ALL
+    SELECT t.id, s.path || t.id
+    FROM tasks t
+    JOIN subtree s ON t.parent_task_id = s.id
+    WHERE NOT t.id = ANY(s.path)
+)
+SELECT
+    count(*) AS total,
+    count(*) FILTER (WHERE ws.category = 'done') AS done
+FROM subtree
+JOIN tasks ON tasks.id = subtree.id
+JOIN workflow_statuses ws ON ws.project_id = tasks.project_id AND ws.name = tasks.status
 `
 
-func (q *Queries) ListTasks(ctx context.Context) ([]Task, error) {
-	rows, err := q.db.QueryContext(ctx, listTasks)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Task{}
-	for rows.Next() {
-		var i Task
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.Priority,
-			&i.Status,
-			&i.AssigneeID,
-			&i.ProjectID,
-			&i.CreationDate,
-			&i.CompletionDate,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type GetTaskRollupRow struct {
+	Total int64 `json:"total"`
+	Done  int64 `json:"done"`
 }
 
-const searchTasksByAssignee = `-- name: SearchTasksByAssignee :many
-SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date FROM tasks
-WHERE assignee_id = $1
-ORDER BY creation_date ASC
-`
-
-func (q *Queries) SearchTasksByAssignee(ctx context.Context, assigneeID int64) ([]Task, error) {
-	rows, err := q.db.QueryContext(ctx, searchTasksByAssignee, assigneeID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Task{}
-	for rows.Next() {
-		var i Task
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.Priority,
-			&i.Status,
-			&i.AssigneeID,
-			&i.ProjectID,
-			&i.CreationDate,
-			&i.CompletionDate,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) GetTaskRollup(ctx context.Context, id int64) (GetTaskRollupRow, error) {
+	row := q.db.QueryRowContext(ctx, getTaskRollup, id)
+	var i GetTaskRollupRow
+	err := row.Scan(&i.Total, &i.Done)
+	return i, err
 }
 
-const searchTasksByPriority = `-- name: SearchTasksByPriority :many
-SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date FROM tasks
-WHERE priority = $1
-ORDER BY creation_date ASC
+const isTaskAncestor = `-- name: IsTaskAncestor :one
+WITH RECURSIVE ancestors AS (
+    SELECT t.id, t.parent_task_id
+    FROM tasks t
+    WHERE t.id = $2::bigint
+    UNION
+    SELECT t.id, t.parent_task_id
+    FROM tasks t
+    JOIN ancestors a ON t.id = a.parent_task_id
+)
+SELECT EXISTS (
+    SELECT 1 FROM ancestors WHERE id = $1::bigint
+)
 `
 
-func (q *Queries) SearchTasksByPriority(ctx context.Context, priority TaskPriority) ([]Task, error) {
-	rows, err := q.db.QueryContext(ctx, searchTasksByPriority, priority)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Task{}
-	for rows.Next() {
-		var i Task
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.Priority,
-			&i.Status,
-			&i.AssigneeID,
-			&i.ProjectID,
-			&i.CreationDate,
-			&i.CompletionDate,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type IsTaskAncestorParams struct {
+	AncestorID int64 `json:"ancestor_id"`
+	TaskID     int64 `json:"task_id"`
+}
+
+// Reports whether ancestor_id is the task itself or one of its ancestors
+func (q *Queries) IsTaskAncestor(ctx context.Context, arg IsTaskAncestorParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isTaskAncestor, arg.AncestorID, arg.TaskID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
 }
 
-const searchTasksByProject = `-- name: SearchTasksByProject :many
-SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date FROM tasks
-WHERE project_id = $1
-ORDER BY creation_date ASC
+const listSubtasks = `-- name: ListSubtasks :many
+SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date, version, parent_task_id, blocked, due_date, estimate, sprint_id, milestone_id FROM tasks
+WHERE parent_task_id = $1::bigint
+ORDER BY creation_date, id
 `
 
-func (q *Queries) SearchTasksByProject(ctx context.Context, projectID int64) ([]Task, error) {
-	rows, err := q.db.QueryContext(ctx, searchTasksByProject, projectID)
+func (q *Queries) ListSubtasks(ctx context.Context, id int64) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, listSubtasks, id)
 	if err != nil {
 		return nil, err
 	}
@@ -227,6 +224,13 @@ func (q *Queries) SearchTasksByProject(ctx context.Context, projectID int64) ([]
 			&i.ProjectID,
 			&i.CreationDate,
 			&i.CompletionDate,
+			&i.Version,
+			&i.ParentTaskID,
+			&i.Blocked,
+			&i.DueDate,
+			&i.Estimate,
+			&i.SprintID,
+			&i.MilestoneID,
 		); err != nil {
 			return nil, err
 		}
@@ -241,31 +245,58 @@ func (q *Queries) SearchTasksByProject(ctx context.Context, projectID int64) ([]
 	return items, nil
 }
 
-const searchTasksByStatus = `-- name: SearchTasksByStatus :many
-SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date FROM tasks
-WHERE status = $1
-ORDER BY creation_date ASC
+const listTaskSubtree = `-- name: ListTaskSubtree :many
+WITH RECURSIVE subtree AS (
+    SELECT t.id, 1 AS depth, ARRAY[t.id] AS path
+    FROM tasks t
+    WHERE t.parent_task_id = $1::bigint
+    UNION ALL
+    SELECT t.id, s.depth + 1, s.path || t.id
+    FROM tasks t
+    JOIN subtree s ON t.parent_task_id = s.id
+    WHERE NOT t.id = ANY(s.path)
+)
+SELECT tasks.id, tasks.title, tasks.description, tasks.priority, tasks.status, tasks.assignee_id, tasks.project_id, tasks.creation_date, tasks.completion_date, tasks.version, tasks.parent_task_id, tasks.blocked, tasks.due_date, tasks.estimate, tasks.sprint_id, tasks.milestone_id, subtree.depth::int AS depth, ws.category
+FROM subtree
+JOIN tasks ON tasks.id = subtree.id
+JOIN workflow_statuses ws ON ws.project_id = tasks.project_id AND ws.name = tasks.status
+ORDER BY subtree.depth, tasks.creation_date, tasks.id
 `
 
-func (q *Queries) SearchTasksByStatus(ctx context.Context, status TaskStatus) ([]Task, error) {
-	rows, err := q.db.QueryContext(ctx, searchTasksByStatus, status)
+type ListTaskSubtreeRow struct {
+	Task     Task           `json:"task"`
+	Depth    int32          `json:"depth"`
+	Category StatusCategory `json:"category"`
+}
+
+func (q *Queries) ListTaskSubtree(ctx context.Context, id int64) ([]ListTaskSubtreeRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTaskSubtree, id)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Task{}
+	items := []ListTaskSubtreeRow{}
 	for rows.Next() {
-		var i Task
+		var i ListTaskSubtreeRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.Priority,
-			&i.Status,
-			&i.AssigneeID,
-			&i.ProjectID,
-			&i.CreationDate,
-			&i.CompletionDate,
+			&i.Task.ID,
+			&i.Task.Title,
+			&i.Task.Description,
+			&i.Task.Priority,
+			&i.Task.Status,
+			&i.Task.AssigneeID,
+			&i.Task.ProjectID,
+			&i.Task.CreationDate,
+			&i.Task.CompletionDate,
+			&i.Task.Version,
+			&i.Task.ParentTaskID,
+			&i.Task.Blocked,
+			&i.Task.DueDate,
+			&i.Task.Estimate,
+			&i.Task.SprintID,
+			&i.Task.MilestoneID,
+			&i.Depth,
+			&i.Category,
 		); err != nil {
 			return nil, err
 		}
@@ -280,43 +311,103 @@ func (q *Queries) SearchTasksByStatus(ctx context.Context, status TaskStatus) ([
 	return items, nil
 }
 
-const searchTasksByTitle = `-- name: SearchTasksByTitle :many
-SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date FROM tasks
-WHERE title ILIKE '%' || $1 || '%'
-ORDER BY creation_date ASC
+const patchTask = `-- name: PatchTask :one
+UPDATE tasks
+SET
+    title = COALESCE($1, title),
+    description = COALESCE($2, description),
+    priority = COALESCE($3, priority),
+    status = COALESCE($4, status),
+    assignee_id = COALESCE($5, assignee_id),
+    project_id = COALESCE($6, project_id),
+    parent_task_id = CASE WHEN $7::boolean
+        THEN $8::bigint
+        ELSE parent_task_id
+    END,
+    completion_date = CASE WHEN $9::boolean
+        THEN $10::timestamp
+        ELSE completion_date
+    END,
+    due_date = CASE WHEN $11::boolean
+        THEN $12::date
+        ELSE due_date
+    END,
+    estimate = CASE WHEN $13::boolean
+        THEN $14::double precision
+        ELSE estimate
+    END,
+    sprint_id = CASE WHEN project_id = COALESCE($6, project_id) THEN sprint_id END,
+    milestone_id = CASE
+        WHEN $15::boolean THEN $16::bigint
+        WHEN project_id = COALESCE($6, project_id) THEN milestone_id
+    END,
+    version = version + 1
+WHERE id = $17 AND version = $18
+RETURNING id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date, version, parent_task_id, blocked, due_date, estimate, sprint_id, milestone_id
 `
 
-func (q *Queries) SearchTasksByTitle(ctx context.Context, dollar_1 sql.NullString) ([]Task, error) {
-	rows, err := q.db.QueryContext(ctx, searchTasksByTitle, dollar_1)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Task{}
-	for rows.Next() {
-		var i Task
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.Priority,
-			&i.Status,
-			&i.AssigneeID,
-			&i.ProjectID,
-			&i.CreationDate,
-			&i.CompletionDate,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type PatchTaskParams struct {
+	Title             sql.NullString   `json:"title"`
+	Description       sql.NullString   `json:"description"`
+	Priority          NullTaskPriority `json:"priority"`
+	Status            sql.NullString   `json:"status"`
+	AssigneeID        sql.NullInt64    `json:"assignee_id"`
+	ProjectID         sql.NullInt64    `json:"project_id"`
+	SetParentTaskID   bool             `json:"set_parent_task_id"`
+	ParentTaskID      sql.NullInt64    `json:"parent_task_id"`
+	SetCompletionDate bool             `json:"set_completion_date"`
+	CompletionDate    sql.NullTime     `json:"completion_date"`
+	SetDueDate        bool             `json:"set_due_date"`
+	DueDate           sql.NullTime     `json:"due_date"`
+	SetEstimate       bool             `json:"set_estimate"`
+	Estimate          sql.NullFloat64  `json:"estimate"`
+	SetMilestoneID    bool             `json:"set_milestone_id"`
+	MilestoneID       sql.NullInt64    `json:"milestone_id"`
+	ID                int64            `json:"id"`
+	Version           int64            `json:"version"`
+}
+
+func (q *Queries) PatchTask(ctx context.Context, arg PatchTaskParams) (Task, error) {
+	row := q.db.QueryRowContext(ctx, patchTask,
+		arg.Title,
+		arg.Description,
+		arg.Priority,
+		arg.Status,
+		arg.AssigneeID,
+		arg.ProjectID,
+		arg.SetParentTaskID,
+		arg.ParentTaskID,
+		arg.SetCompletionDate,
+		arg.CompletionDate,
+		arg.SetDueDate,
+		arg.DueDate,
+		arg.SetEstimate,
+		arg.Estimate,
+		arg.SetMilestoneID,
+		arg.MilestoneID,
+		arg.ID,
+		arg.Version,
+	)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Priority,
+		&i.Status,
+		&i.AssigneeID,
+		&i.ProjectID,
+		&i.CreationDate,
+		&i.CompletionDate,
+		&i.Version,
+		&i.ParentTaskID,
+		&i.Blocked,
+		&i.DueDate,
+		&i.Estimate,
+		&i.SprintID,
+		&i.MilestoneID,
+	)
+	return i, err
 }
 
 const updateTask = `-- name: UpdateTask :one
@@ -328,20 +419,31 @@ SET
     status = $5,
     assignee_id = $6,
     project_id = $7,
-    completion_date = $8
-WHERE id = $1
-RETURNING id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date
+    completion_date = $8,
+    parent_task_id = $10,
+    due_date = $11,
+    estimate = $12,
+    sprint_id = CASE WHEN project_id = $7 THEN sprint_id END,
+    milestone_id = $13,
+    version = version + 1
+WHERE id = $1 AND version = $9
+RETURNING id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date, version, parent_task_id, blocked, due_date, estimate, sprint_id, milestone_id
 `
 
 type UpdateTaskParams struct {
-	ID             int64        `json:"id"`
-	Title          string       `json:"title"`
-	Description    string       `json:"description"`
-	Priority       TaskPriority `json:"priority"`
-	Status         TaskStatus   `json:"status"`
-	AssigneeID     int64        `json:"assignee_id"`
-	ProjectID      int64        `json:"project_id"`
-	CompletionDate sql.NullTime `json:"completion_date"`
+	ID             int64           `json:"id"`
+	Title          string          `json:"title"`
+	Description    string          `json:"description"`
+	Priority       TaskPriority    `json:"priority"`
+	Status         string          `json:"status"`
+	AssigneeID     int64           `json:"assignee_id"`
+	ProjectID      int64           `json:"project_id"`
+	CompletionDate sql.NullTime    `json:"completion_date"`
+	Version        int64           `json:"version"`
+	ParentTaskID   sql.NullInt64   `json:"parent_task_id"`
+	DueDate        sql.NullTime    `json:"due_date"`
+	Estimate       sql.NullFloat64 `json:"estimate"`
+	MilestoneID    sql.NullInt64   `json:"milestone_id"`
 }
 
 func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, error) {
@@ -354,6 +456,11 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, e
 		arg.AssigneeID,
 		arg.ProjectID,
 		arg.CompletionDate,
+		arg.Version,
+		arg.ParentTaskID,
+		arg.DueDate,
+		arg.Estimate,
+		arg.MilestoneID,
 	)
 	var i Task
 	err := row.Scan(
@@ -366,6 +473,13 @@ func (q *Queries) UpdateTask(ctx context.Context, arg UpdateTaskParams) (Task, e
 		&i.ProjectID,
 		&i.CreationDate,
 		&i.CompletionDate,
+		&i.Version,
+		&i.ParentTaskID,
+		&i.Blocked,
+		&i.DueDate,
+		&i.Estimate,
+		&i.SprintID,
+		&i.MilestoneID,
 	)
 	return i, err
 }
@@ -0,0 +1,182 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: task_dependency.sql
+
+package db
+
+import (
+	"context"
+)
+
+const addTaskDependency = `-- name: AddTaskDependency :exec
+INSERT INTO task_dependencies (
+    blocker_id, blocked_id
+) VALUES (
+    $1, $2
+)
+ON CONFLICT DO NOTHING
+`
+
+type AddTaskDependencyParams struct {
+	BlockerID int64 `json:"blocker_id"`
+	BlockedID int64 `json:"blocked_id"`
+}
+
+func (q *Queries) AddTaskDependency(ctx context.Context, arg AddTaskDependencyParams) error {
+	_, err := q.db.ExecContext(ctx, addTaskDependency, arg.BlockerID, arg.BlockedID)
+	return err
+}
+
+const dependsOn = `-- name: DependsOn :one
+WITH RECURSIVE upstream AS (
+    SELECT d.blocker_id
+    FROM task_dependencies d
+    WHERE d.blocked_id = $2::bigint
+    UNION
+    SELECT d.blocker_id
+    FROM task_dependencies d
+    JOIN upstream u ON d.blocked_id = u.blocker_id
+)
+SELECT EXISTS (
+    SELECT 1 FROM upstream WHERE blocker_id = $1::bigint
+)
+`
+
+type DependsOnParams struct {
+	BlockerID int64 `json:"blocker_id"`
+	TaskID    int64 `json:"task_id"`
+}
+
+// Reports whether the task waits on the blocker, directly or through other tasks
+func (q *Queries) DependsOn(ctx context.Context, arg DependsOnParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, dependsOn, arg.BlockerID, arg.TaskID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listBlockedTasks = `-- name: ListBlockedTasks :many
+SELECT tasks.id, tasks.title, tasks.description, tasks.priority, tasks.status, tasks.assignee_id, tasks.project_id, tasks.creation_date, tasks.completion_date, tasks.version, tasks.parent_task_id, tasks.blocked, tasks.due_date, tasks.estimate, tasks.sprint_id, tasks.milestone_id
+FROM task_dependencies d
+JOIN tasks ON tasks.id = d.blocked_id
+WHERE d.blocker_id = $1
+ORDER BY d.created_at, tasks.id
+`
+
+func (q *Queries) ListBlockedTasks(ctx context.Context, blockerID int64) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, listBlockedTasks, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Priority,
+			&i.Status,
+			&i.AssigneeID,
+			&i.ProjectID,
+			&i.CreationDate,
+			&i.CompletionDate,
+			&i.Version,
+			&i.ParentTaskID,
+			&i.Blocked,
+			&i.DueDate,
+			&i.Estimate,
+			&i.SprintID,
+			&i.MilestoneID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTaskBlockers = `-- name: ListTaskBlockers :many
+SELECT tasks.id, tasks.title, tasks.description, tasks.priority, tasks.status, tasks.assignee_id, tasks.project_id, tasks.creation_date, tasks.completion_date, tasks.version, tasks.parent_task_id, tasks.blocked, tasks.due_date, tasks.estimate, tasks.sprint_id, tasks.milestone_id
+FROM task_dependencies d
+JOIN tasks ON tasks.id = d.blocker_id
+WHERE d.blocked_id = $1
+ORDER BY d.created_at, tasks.id
+`
+
+func (q *Queries) ListTaskBlockers(ctx context.Context, blockedID int64) ([]Task, error) {
+	rows, err := q.db.QueryContext(ctx, listTaskBlockers, blockedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Priority,
+			&i.Status,
+			&i.AssigneeID,
+			&i.ProjectID,
+			&i.CreationDate,
+			&i.CompletionDate,
+			&i.Version,
+			&i.ParentTaskID,
+			&i.Blocked,
+			&i.DueDate,
+			&i.Estimate,
+			&i.SprintID,
+			&i.MilestoneID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockProjectDependencies = `-- name: LockProjectDependencies :exec
+SELECT pg_advisory_xact_lock($1::bigint)
+`
+
+// Serializes changes to the dependencies of a project until the transaction
+// ends, so that concurrent additions cannot close a cycle together
+func (q *Queries) LockProjectDependencies(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, lockProjectDependencies, projectID)
+	return err
+}
+
+const removeTaskDependency = `-- name: RemoveTaskDependency :execrows
+DELETE FROM task_dependencies
+WHERE blocker_id = $1 AND blocked_id = $2
+`
+
+type RemoveTaskDependencyParams struct {
+	BlockerID int64 `json:"blocker_id"`
+	BlockedID int64 `json:"blocked_id"`
+}
+
+func (q *Queries) RemoveTaskDependency(ctx context.Context, arg RemoveTaskDependencyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeTaskDependency, arg.BlockerID, arg.BlockedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
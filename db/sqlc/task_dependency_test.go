@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTaskDependency(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	mock.ExpectExec("INSERT INTO task_dependencies (.+) ON CONFLICT DO NOTHING").
+		WithArgs(int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = queries.AddTaskDependency(context.Background(), AddTaskDependencyParams{BlockerID: 1, BlockedID: 2})
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestListTaskBlockers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+		AddRow(1, "API design", "Description", TaskPriorityHigh, "in_progress", 1, 1, time.Now(), nil, 1, nil, false, nil, nil, nil, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM task_dependencies d JOIN tasks ON tasks.id = d.blocker_id WHERE d.blocked_id = \\$1").
+		WithArgs(int64(2)).
+		WillReturnRows(rows)
+
+	blockers, err := queries.ListTaskBlockers(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, blockers, 1)
+	assert.Equal(t, "API design", blockers[0].Title)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestDependsOn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	mock.ExpectQuery("WITH RECURSIVE upstream AS (.+) SELECT EXISTS").
+		WithArgs(int64(2), int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	cycle, err := queries.DependsOn(context.Background(), DependsOnParams{TaskID: 3, BlockerID: 2})
+
+	assert.NoError(t, err)
+	assert.False(t, cycle)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
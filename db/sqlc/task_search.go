@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+)
+
+// taskColumns lists the columns of the tasks table in the order they are
+// scanned into a Task
+const taskColumns = `id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date, version, parent_task_id, blocked, due_date, estimate, sprint_id, milestone_id`
+
+// SearchTasksParams holds the filters of SearchTasks. Zero values and empty
+// slices are ignored, every other filter is ANDed together and the values of
+// a slice are ORed, except for Labels which must all be on the task when
+// AllLabels is set. Overdue keeps the unfinished tasks whose due date has
+// passed.
+type SearchTasksParams struct {
+	Title         string           `json:"title"`
+	Statuses      []string         `json:"statuses"`
+	Categories    []StatusCategory `json:"categories"`
+	Priorities    []TaskPriority   `json:"priorities"`
+	AssigneeIDs   []int64          `json:"assignee_ids"`
+	ProjectIDs    []int64          `json:"project_ids"`
+	CreatedFrom   sql.NullTime     `json:"created_from"`
+	CreatedTo     sql.NullTime     `json:"created_to"`
+	CompletedFrom sql.NullTime     `json:"completed_from"`
+	CompletedTo   sql.NullTime     `json:"completed_to"`
+	DueAfter      sql.NullTime     `json:"due_after"`
+	DueBefore     sql.NullTime     `json:"due_before"`
+	Overdue       bool             `json:"overdue"`
+	Labels        []string         `json:"labels"`
+	AllLabels     bool             `json:"all_labels"`
+	ViewerIsAdmin bool             `json:"viewer_is_admin"`
+	ViewerID      int64            `json:"viewer_id"`
+	Sort          Sort             `json:"sort"`
+	After         []interface{}    `json:"after"`
+	PageLimit     int32            `json:"page_limit"`
+}
+
+// TaskSortColumns are the columns tasks can be sorted by
+var TaskSortColumns = SortColumns[Task]{
+	"id":            func(t Task) interface{} { return t.ID },
+	"title":         func(t Task) interface{} { return t.Title },
+	"priority":      func(t Task) interface{} { return t.Priority },
+	"status":        func(t Task) interface{} { return t.Status },
+	"assignee_id":   func(t Task) interface{} { return t.AssigneeID },
+	"project_id":    func(t Task) interface{} { return t.ProjectID },
+	"creation_date": func(t Task) interface{} { return t.CreationDate },
+}
+
+// DefaultTaskSort orders tasks from the oldest to the newest
+var DefaultTaskSort = Sort{{Column: "creation_date"}, {Column: "id"}}
+
+// SearchTasks returns the tasks matching every filter of arg, restricted to
+// the projects visible to the viewer, in the requested order and starting
+// after the row the After values were taken from
+func (q *Queries) SearchTasks(ctx context.Context, arg SearchTasksParams) ([]Task, error) {
+	sort := arg.Sort
+	if len(sort) == 0 {
+		sort = DefaultTaskSort
+	}
+	if err := TaskSortColumns.validate(sort); err != nil {
+		return nil, err
+	}
+
+	var b queryBuilder
+
+	if arg.Title != "" {
+		b.whereContains("title", arg.Title)
+	}
+	b.whereIn("status", toArgs(arg.Statuses))
+	if len(arg.Categories) > 0 {
+		b.where("(tasks.project_id, tasks.status) IN (SELECT ws.project_id, ws.name FROM workflow_statuses ws WHERE ws.category IN ("+marks(len(arg.Categories))+"))",
+			toArgs(arg.Categories)...)
+	}
+	b.whereIn("priority", toArgs(arg.Priorities))
+	b.whereIn("assignee_id", toArgs(arg.AssigneeIDs))
+	b.whereIn("tasks.project_id", toArgs(arg.ProjectIDs))
+	if arg.CreatedFrom.Valid {
+		b.where("creation_date >= ?", arg.CreatedFrom.Time)
+	}
+	if arg.CreatedTo.Valid {
+		b.where("creation_date <= ?", arg.CreatedTo.Time)
+	}
+	if arg.CompletedFrom.Valid {
+		b.where("completion_date >= ?", arg.CompletedFrom.Time)
+	}
+	if arg.CompletedTo.Valid {
+		b.where("completion_date <= ?", arg.CompletedTo.Time)
+	}
+	if arg.DueAfter.Valid {
+		b.where("due_date >= ?", arg.DueAfter.Time)
+	}
+	if arg.DueBefore.Valid {
+		b.where("due_date <= ?", arg.DueBefore.Time)
+	}
+	if arg.Overdue {
+		b.where("due_date < CURRENT_DATE AND (tasks.project_id, tasks.status) IN (SELECT ws.project_id, ws.name FROM workflow_statuses ws WHERE ws.category <> 'done')")
+	}
+	if len(arg.Labels) > 0 {
+		labeled := "tasks.id IN (SELECT tl.task_id FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE l.name IN (" + marks(len(arg.Labels)) + ")"
+		if arg.AllLabels {
+			labeled += " GROUP BY tl.task_id HAVING count(DISTINCT l.name) = ?"
+			b.where(labeled+")", append(toArgs(arg.Labels), len(arg.Labels))...)
+		} else {
+			b.where(labeled+")", toArgs(arg.Labels)...)
+		}
+	}
+	if !arg.ViewerIsAdmin {
+		b.where("tasks.project_id IN (SELECT pm.project_id FROM project_members pm WHERE pm.user_id = ?)", arg.ViewerID)
+	}
+	if err := b.after(sort, arg.After); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + taskColumns + " FROM tasks\n" + b.clause() +
+		"\n" + orderBy(sort) + "\nLIMIT " + b.arg(arg.PageLimit)
+
+	rows, err := q.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Task{}
+	for rows.Next() {
+		var i Task
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Priority,
+			&i.Status,
+			&i.AssigneeID,
+			&i.ProjectID,
+			&i.CreationDate,
+			&i.CompletionDate,
+			&i.Version,
+			&i.ParentTaskID,
+			&i.Blocked,
+			&i.DueDate,
+			&i.Estimate,
+			&i.SprintID,
+			&i.MilestoneID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,275 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTasksCombinesFilters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+	completionDate := sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+		AddRow(1, "Test Task 1", "Description 1", TaskPriorityLow, "new", 3, 1, now, completionDate, 1, nil, false, nil, nil, nil, nil).
+		AddRow(2, "Test Task 2", "Description 2", TaskPriorityHigh, "in_progress", 3, 2, now, completionDate, 1, nil, false, nil, nil, nil, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE title ILIKE '%' \\|\\| \\$1 \\|\\| '%' ESCAPE '\\\\' AND status IN \\(\\$2, \\$3\\) AND assignee_id IN \\(\\$4\\) AND creation_date >= \\$5 AND tasks.project_id IN \\(SELECT (.+) WHERE pm.user_id = \\$6\\) ORDER BY creation_date ASC, id ASC LIMIT \\$7").
+		WithArgs("Test", "new", "in_progress", int64(3), now, int64(7), int32(21)).
+		WillReturnRows(rows)
+
+	tasks, err := queries.SearchTasks(context.Background(), SearchTasksParams{
+		Title:       "Test",
+		Statuses:    []string{"new", "in_progress"},
+		AssigneeIDs: []int64{3},
+		CreatedFrom: sql.NullTime{Time: now, Valid: true},
+		ViewerID:    7,
+		PageLimit:   21,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, int64(1), tasks[0].ID)
+	assert.Equal(t, "new", tasks[0].Status)
+	assert.Equal(t, int64(3), tasks[0].AssigneeID)
+	assert.Equal(t, int64(2), tasks[1].ID)
+	assert.Equal(t, "in_progress", tasks[1].Status)
+	assert.Equal(t, int64(3), tasks[1].AssigneeID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSearchTasksWithoutFilters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+	completionDate := sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true}
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+		AddRow(1, "Test Task 1", "Description 1", TaskPriorityLow, "new", 1, 1, now, completionDate, 1, nil, false, nil, nil, nil, nil).
+		AddRow(2, "Test Task 2", "Description 2", TaskPriorityMedium, "in_progress", 2, 2, now, completionDate, 1, nil, false, nil, nil, nil, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks ORDER BY creation_date ASC, id ASC LIMIT \\$1").
+		WithArgs(int32(21)).
+		WillReturnRows(rows)
+
+	tasks, err := queries.SearchTasks(context.Background(), SearchTasksParams{
+		ViewerIsAdmin: true,
+		PageLimit:     21,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	assert.Equal(t, int64(1), tasks[0].ID)
+	assert.Equal(t, "Test Task 1", tasks[0].Title)
+	assert.Equal(t, "Description 1", tasks[0].Description)
+	assert.Equal(t, TaskPriorityLow, tasks[0].Priority)
+	assert.Equal(t, "new", tasks[0].Status)
+	assert.Equal(t, int64(1), tasks[0].AssigneeID)
+	assert.Equal(t, int64(1), tasks[0].ProjectID)
+	assert.WithinDuration(t, now, tasks[0].CreationDate, time.Second)
+	assert.WithinDuration(t, completionDate.Time, tasks[0].CompletionDate.Time, time.Second)
+	assert.Equal(t, int64(2), tasks[1].ID)
+	assert.Equal(t, "Test Task 2", tasks[1].Title)
+	assert.Equal(t, TaskPriorityMedium, tasks[1].Priority)
+	assert.Equal(t, "in_progress", tasks[1].Status)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSearchTasksSortedAfterCursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+		AddRow(5, "Test Task 5", "Description 5", TaskPriorityMedium, "completed", 2, 4, now, nil, 1, nil, false, nil, nil, nil, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE tasks.project_id IN \\(\\$1\\) "+
+		"AND \\(\\(priority < \\$2\\) OR \\(priority = \\$3 AND creation_date > \\$4\\) OR \\(priority = \\$5 AND creation_date = \\$6 AND id > \\$7\\)\\) "+
+		"ORDER BY priority DESC, creation_date ASC, id ASC LIMIT \\$8").
+		WithArgs(int64(4), "high", "high", now, "high", now, int64(4), int32(11)).
+		WillReturnRows(rows)
+
+	sort, err := TaskSortColumns.Parse("-priority,creation_date", DefaultTaskSort)
+	assert.NoError(t, err)
+
+	tasks, err := queries.SearchTasks(context.Background(), SearchTasksParams{
+		ProjectIDs:    []int64{4},
+		ViewerIsAdmin: true,
+		Sort:          sort,
+		After:         []interface{}{"high", now, int64(4)},
+		PageLimit:     11,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, int64(5), tasks[0].ID)
+	assert.False(t, tasks[0].CompletionDate.Valid)
+	assert.Equal(t, []interface{}{TaskPriorityMedium, tasks[0].CreationDate, int64(5)}, TaskSortColumns.Values(sort)(tasks[0]))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSearchTasksRejectsUnknownSort(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	_, err = queries.SearchTasks(context.Background(), SearchTasksParams{
+		Sort:      Sort{{Column: "description; DROP TABLE tasks"}},
+		PageLimit: 21,
+	})
+	assert.ErrorIs(t, err, ErrInvalidSort)
+
+	_, err = queries.SearchTasks(context.Background(), SearchTasksParams{
+		Sort:      DefaultTaskSort,
+		After:     []interface{}{"2024-07-19T00:00:00Z"},
+		PageLimit: 21,
+	})
+	assert.ErrorIs(t, err, ErrCursorMismatch)
+}
+
+func TestSearchTasksByCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+		AddRow(3, "Test Task 3", "Description 3", TaskPriorityLow, "review", 1, 1, time.Now(), nil, 1, nil, false, nil, nil, nil, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE \\(tasks.project_id, tasks.status\\) IN \\(SELECT ws.project_id, ws.name FROM workflow_statuses ws WHERE ws.category IN \\(\\$1, \\$2\\)\\) ORDER BY creation_date ASC, id ASC LIMIT \\$3").
+		WithArgs(StatusCategoryNew, StatusCategoryActive, int32(21)).
+		WillReturnRows(rows)
+
+	tasks, err := queries.SearchTasks(context.Background(), SearchTasksParams{
+		Categories:    []StatusCategory{StatusCategoryNew, StatusCategoryActive},
+		ViewerIsAdmin: true,
+		PageLimit:     21,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, "review", tasks[0].Status)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSearchTasksByLabels(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	columns := []string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}
+
+	// Any of the labels
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE tasks.id IN \\(SELECT tl.task_id FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE l.name IN \\(\\$1, \\$2\\)\\) ORDER BY").
+		WithArgs("bug", "urgent", int32(21)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(4, "Test Task 4", "Description 4", TaskPriorityHigh, "new", 1, 1, time.Now(), nil, 1, nil, false, nil, nil, nil, nil))
+
+	tasks, err := queries.SearchTasks(context.Background(), SearchTasksParams{
+		Labels:        []string{"bug", "urgent"},
+		ViewerIsAdmin: true,
+		PageLimit:     21,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+
+	// All of the labels
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE tasks.id IN \\(SELECT tl.task_id FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE l.name IN \\(\\$1, \\$2\\) GROUP BY tl.task_id HAVING count\\(DISTINCT l.name\\) = \\$3\\) ORDER BY").
+		WithArgs("bug", "urgent", 2, int32(21)).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	tasks, err = queries.SearchTasks(context.Background(), SearchTasksParams{
+		Labels:        []string{"bug", "urgent"},
+		AllLabels:     true,
+		ViewerIsAdmin: true,
+		PageLimit:     21,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSearchTasksByDueDate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	dueAfter := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	dueBefore := time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE due_date >= \\$1 AND due_date <= \\$2 AND due_date < CURRENT_DATE AND \\(tasks.project_id, tasks.status\\) IN \\(SELECT ws.project_id, ws.name FROM workflow_statuses ws WHERE ws.category <> 'done'\\) ORDER BY").
+		WithArgs(dueAfter, dueBefore, int32(21)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+			AddRow(5, "Test Task 5", "Description 5", TaskPriorityHigh, "in_progress", 1, 1, time.Now(), nil, 1, nil, false, dueAfter.AddDate(0, 0, 9), 3.5, nil, nil))
+
+	tasks, err := queries.SearchTasks(context.Background(), SearchTasksParams{
+		DueAfter:      sql.NullTime{Time: dueAfter, Valid: true},
+		DueBefore:     sql.NullTime{Time: dueBefore, Valid: true},
+		Overdue:       true,
+		ViewerIsAdmin: true,
+		PageLimit:     21,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, dueAfter.AddDate(0, 0, 9), tasks[0].DueDate.Time)
+	assert.Equal(t, 3.5, tasks[0].Estimate.Float64)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
@@ -23,12 +23,12 @@ func TestCreateTask(t *testing.T) {
 	completionDate := sql.NullTime{Time: now.Add(48 * time.Hour), Valid: true}
 
 	// Define expected rows
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date"}).
-		AddRow(1, "Sample Task", "This is a sample task", "medium", "Pending", 1, 1, now, completionDate.Time)
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+		AddRow(1, "Sample Task", "This is a sample task", "medium", "Pending", 1, 1, now, completionDate.Time, 1, nil, false, nil, nil, nil, nil)
 
 	// Mock the query
 	mock.ExpectQuery("INSERT INTO tasks").
-		WithArgs("Sample Task", "This is a sample task", "medium", "Pending", 1, 1, completionDate).
+		WithArgs("Sample Task", "This is a sample task", "medium", "Pending", 1, 1, completionDate, sql.NullInt64{}, sql.NullTime{}, sql.NullFloat64{}, sql.NullInt64{}).
 		WillReturnRows(rows)
 
 	// Define the parameters for CreateTask
@@ -52,7 +52,7 @@ func TestCreateTask(t *testing.T) {
 	assert.Equal(t, "Sample Task", task.Title)
 	assert.Equal(t, "This is a sample task", task.Description)
 	assert.Equal(t, TaskPriority("medium"), task.Priority) // Convert string to TaskPriority
-	assert.Equal(t, TaskStatus("Pending"), task.Status)    // Convert string to TaskStatus
+	assert.Equal(t, "Pending", task.Status)
 	assert.Equal(t, int64(1), task.AssigneeID)
 	assert.Equal(t, int64(1), task.ProjectID)
 	assert.WithinDuration(t, now, task.CreationDate, time.Second)
@@ -64,7 +64,6 @@ func TestCreateTask(t *testing.T) {
 	}
 }
 
-
 func TestGetTask(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -77,8 +76,8 @@ func TestGetTask(t *testing.T) {
 	now := time.Now()
 	completionDate := sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date"}).
-		AddRow(1, "Test Task", "Description", TaskPriorityLow, TaskStatusNew, 1, 1, now, completionDate)
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+		AddRow(1, "Test Task", "Description", TaskPriorityLow, "new", 1, 1, now, completionDate, 1, nil, false, nil, nil, nil, nil)
 
 	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE id = \\$1 LIMIT 1").
 		WithArgs(1).
@@ -91,7 +90,7 @@ func TestGetTask(t *testing.T) {
 	assert.Equal(t, "Test Task", task.Title)
 	assert.Equal(t, "Description", task.Description)
 	assert.Equal(t, TaskPriorityLow, task.Priority)
-	assert.Equal(t, TaskStatusNew, task.Status)
+	assert.Equal(t, "new", task.Status)
 	assert.Equal(t, int64(1), task.AssigneeID)
 	assert.Equal(t, int64(1), task.ProjectID)
 	assert.WithinDuration(t, now, task.CreationDate, time.Second)
@@ -102,102 +101,67 @@ func TestGetTask(t *testing.T) {
 	}
 }
 
-func TestListTasks(t *testing.T) {
+func TestUpdateTask(t *testing.T) {
+	// Initialize mock DB
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("Error initializing mock database: %v", err)
 	}
 	defer db.Close()
 
+	// Replace q.db with mock DB for testing purposes
 	queries := New(db)
 
+	// Capture the current time for consistent use
 	now := time.Now()
-	completionDate := sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true}
+	completionDate := sql.NullTime{Time: now, Valid: true}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date"}).
-		AddRow(1, "Test Task 1", "Description 1", TaskPriorityLow, TaskStatusNew, 1, 1, now, completionDate).
-		AddRow(2, "Test Task 2", "Description 2", TaskPriorityMedium, TaskStatusInProgress, 2, 2, now, completionDate)
+	// Mock expected rows to return
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+		AddRow(1, "Updated Task", "Updated Description", TaskPriorityHigh, "in_progress", int64(123), int64(456), now, now, 1, nil, false, nil, nil, nil, nil)
 
-	mock.ExpectQuery("SELECT (.+) FROM tasks ORDER BY creation_date ASC").
+	// Expectation: QueryRowContext with expected arguments
+	mock.ExpectQuery("UPDATE tasks SET title = \\$2, description = \\$3, priority = \\$4, status = \\$5, assignee_id = \\$6, project_id = \\$7, completion_date = \\$8, parent_task_id = \\$10, due_date = \\$11, estimate = \\$12, sprint_id = CASE WHEN project_id = \\$7 THEN sprint_id END, milestone_id = \\$13, version = version \\+ 1 WHERE id = \\$1 AND version = \\$9 RETURNING id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date, version, parent_task_id, blocked, due_date, estimate, sprint_id, milestone_id").
+		WithArgs(int64(1), "Updated Task", "Updated Description", TaskPriorityHigh, "in_progress", int64(123), int64(456), completionDate, int64(1), sql.NullInt64{}, sql.NullTime{}, sql.NullFloat64{}, sql.NullInt64{}).
 		WillReturnRows(rows)
 
-	tasks, err := queries.ListTasks(context.Background())
-
-	assert.NoError(t, err)
-	assert.Len(t, tasks, 2)
-	assert.Equal(t, int64(1), tasks[0].ID)
-	assert.Equal(t, "Test Task 1", tasks[0].Title)
-	assert.Equal(t, "Description 1", tasks[0].Description)
-	assert.Equal(t, TaskPriorityLow, tasks[0].Priority)
-	assert.Equal(t, TaskStatusNew, tasks[0].Status)
-	assert.Equal(t, int64(1), tasks[0].AssigneeID)
-	assert.Equal(t, int64(1), tasks[0].ProjectID)
-	assert.WithinDuration(t, now, tasks[0].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[0].CompletionDate.Time, time.Second)
-	assert.Equal(t, int64(2), tasks[1].ID)
-	assert.Equal(t, "Test Task 2", tasks[1].Title)
-	assert.Equal(t, "Description 2", tasks[1].Description)
-	assert.Equal(t, TaskPriorityMedium, tasks[1].Priority)
-	assert.Equal(t, TaskStatusInProgress, tasks[1].Status)
-	assert.Equal(t, int64(2), tasks[1].AssigneeID)
-	assert.Equal(t, int64(2), tasks[1].ProjectID)
-	assert.WithinDuration(t, now, tasks[1].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[1].CompletionDate.Time, time.Second)
-
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("Unfulfilled expectations: %s", err)
-	}
-}
-
-func TestSearchTasksByAssignee(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("Error initializing mock database: %v", err)
+	// Prepare input params
+	params := UpdateTaskParams{
+		ID:             1,
+		Title:          "Updated Task",
+		Description:    "Updated Description",
+		Priority:       TaskPriorityHigh,
+		Status:         "in_progress",
+		AssigneeID:     123,
+		ProjectID:      456,
+		CompletionDate: completionDate,
+		Version:        1,
 	}
-	defer db.Close()
 
-	queries := New(db)
-
-	now := time.Now()
-	completionDate := sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true}
-
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date"}).
-		AddRow(1, "Test Task 1", "Description 1", TaskPriorityLow, TaskStatusNew, 1, 1, now, completionDate).
-		AddRow(2, "Test Task 2", "Description 2", TaskPriorityMedium, TaskStatusInProgress, 1, 2, now, completionDate)
-
-	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE assignee_id = \\$1 ORDER BY creation_date ASC").
-		WithArgs(1).
-		WillReturnRows(rows)
+	// Call the UpdateTask method
+	task, err := queries.UpdateTask(context.Background(), params)
 
-	tasks, err := queries.SearchTasksByAssignee(context.Background(), 1)
+	// Verify results
+	assert.NoError(t, err, "Expected no error")
+	assert.Equal(t, int64(1), task.ID, "Expected task ID to match")
+	assert.Equal(t, "Updated Task", task.Title, "Expected task title to match")
+	assert.Equal(t, "Updated Description", task.Description, "Expected task description to match")
+	assert.Equal(t, TaskPriorityHigh, task.Priority, "Expected task priority to match")
+	assert.Equal(t, "in_progress", task.Status, "Expected task status to match")
+	assert.Equal(t, int64(123), task.AssigneeID, "Expected task assignee ID to match")
+	assert.Equal(t, int64(456), task.ProjectID, "Expected task project ID to match")
+	assert.WithinDuration(t, now, task.CreationDate, time.Second, "Expected task creation date to match")
 
-	assert.NoError(t, err)
-	assert.Len(t, tasks, 2)
-	assert.Equal(t, int64(1), tasks[0].ID)
-	assert.Equal(t, "Test Task 1", tasks[0].Title)
-	assert.Equal(t, "Description 1", tasks[0].Description)
-	assert.Equal(t, TaskPriorityLow, tasks[0].Priority)
-	assert.Equal(t, TaskStatusNew, tasks[0].Status)
-	assert.Equal(t, int64(1), tasks[0].AssigneeID)
-	assert.Equal(t, int64(1), tasks[0].ProjectID)
-	assert.WithinDuration(t, now, tasks[0].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[0].CompletionDate.Time, time.Second)
-	assert.Equal(t, int64(2), tasks[1].ID)
-	assert.Equal(t, "Test Task 2", tasks[1].Title)
-	assert.Equal(t, "Description 2", tasks[1].Description)
-	assert.Equal(t, TaskPriorityMedium, tasks[1].Priority)
-	assert.Equal(t, TaskStatusInProgress, tasks[1].Status)
-	assert.Equal(t, int64(1), tasks[1].AssigneeID)
-	assert.Equal(t, int64(2), tasks[1].ProjectID)
-	assert.WithinDuration(t, now, tasks[1].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[1].CompletionDate.Time, time.Second)
+	assert.True(t, task.CompletionDate.Valid, "Expected task completion date to be valid")
+	assert.WithinDuration(t, now, task.CompletionDate.Time, time.Second, "Expected task completion date to match")
 
+	// Assert all expectations were met
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %s", err)
 	}
 }
 
-func TestSearchTasksByPriority(t *testing.T) {
+func TestPatchTask(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("Error initializing mock database: %v", err)
@@ -207,93 +171,35 @@ func TestSearchTasksByPriority(t *testing.T) {
 	queries := New(db)
 
 	now := time.Now()
-	completionDate := sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true}
-
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date"}).
-		AddRow(1, "Test Task 1", "Description 1", TaskPriorityLow, TaskStatusNew, 1, 1, now, completionDate).
-		AddRow(2, "Test Task 2", "Description 2", TaskPriorityLow, TaskStatusInProgress, 2, 2, now, completionDate)
-
-	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE priority = \\$1 ORDER BY creation_date ASC").
-		WithArgs(TaskPriorityLow).
-		WillReturnRows(rows)
-
-	tasks, err := queries.SearchTasksByPriority(context.Background(), TaskPriorityLow)
-
-	assert.NoError(t, err)
-	assert.Len(t, tasks, 2)
-	assert.Equal(t, int64(1), tasks[0].ID)
-	assert.Equal(t, "Test Task 1", tasks[0].Title)
-	assert.Equal(t, "Description 1", tasks[0].Description)
-	assert.Equal(t, TaskPriorityLow, tasks[0].Priority)
-	assert.Equal(t, TaskStatusNew, tasks[0].Status)
-	assert.Equal(t, int64(1), tasks[0].AssigneeID)
-	assert.Equal(t, int64(1), tasks[0].ProjectID)
-	assert.WithinDuration(t, now, tasks[0].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[0].CompletionDate.Time, time.Second)
-	assert.Equal(t, int64(2), tasks[1].ID)
-	assert.Equal(t, "Test Task 2", tasks[1].Title)
-	assert.Equal(t, "Description 2", tasks[1].Description)
-	assert.Equal(t, TaskPriorityLow, tasks[1].Priority)
-	assert.Equal(t, TaskStatusInProgress, tasks[1].Status)
-	assert.Equal(t, int64(2), tasks[1].AssigneeID)
-	assert.Equal(t, int64(2), tasks[1].ProjectID)
-	assert.WithinDuration(t, now, tasks[1].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[1].CompletionDate.Time, time.Second)
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("Unfulfilled expectations: %s", err)
+	// Only the status is patched and the completion date is cleared
+	params := PatchTaskParams{
+		ID:                1,
+		Status:            sql.NullString{String: "in_progress", Valid: true},
+		SetCompletionDate: true,
+		Version:           2,
 	}
-}
 
-func TestSearchTasksByProject(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("Error initializing mock database: %v", err)
-	}
-	defer db.Close()
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id"}).
+		AddRow(1, "Task", "Description", TaskPriorityHigh, "in_progress", int64(123), int64(456), now, nil, 1, nil, false, nil, nil, nil, nil)
 
-	queries := New(db)
-
-	now := time.Now()
-	completionDate := sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true}
-
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date"}).
-		AddRow(1, "Test Task 1", "Description 1", TaskPriorityLow, TaskStatusNew, 1, 1, now, completionDate).
-		AddRow(2, "Test Task 2", "Description 2", TaskPriorityMedium, TaskStatusInProgress, 2, 1, now, completionDate)
-
-	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE project_id = \\$1 ORDER BY creation_date ASC").
-		WithArgs(1).
+	mock.ExpectQuery("UPDATE tasks SET title = COALESCE\\(\\$1, title\\)(.+)WHERE id = \\$17 AND version = \\$18").
+		WithArgs(sql.NullString{}, sql.NullString{}, NullTaskPriority{}, params.Status, sql.NullInt64{}, sql.NullInt64{}, false, sql.NullInt64{}, true, sql.NullTime{}, false, sql.NullTime{}, false, sql.NullFloat64{}, false, sql.NullInt64{}, int64(1), int64(2)).
 		WillReturnRows(rows)
 
-	tasks, err := queries.SearchTasksByProject(context.Background(), 1)
+	task, err := queries.PatchTask(context.Background(), params)
 
 	assert.NoError(t, err)
-	assert.Len(t, tasks, 2)
-	assert.Equal(t, int64(1), tasks[0].ID)
-	assert.Equal(t, "Test Task 1", tasks[0].Title)
-	assert.Equal(t, "Description 1", tasks[0].Description)
-	assert.Equal(t, TaskPriorityLow, tasks[0].Priority)
-	assert.Equal(t, TaskStatusNew, tasks[0].Status)
-	assert.Equal(t, int64(1), tasks[0].AssigneeID)
-	assert.Equal(t, int64(1), tasks[0].ProjectID)
-	assert.WithinDuration(t, now, tasks[0].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[0].CompletionDate.Time, time.Second)
-	assert.Equal(t, int64(2), tasks[1].ID)
-	assert.Equal(t, "Test Task 2", tasks[1].Title)
-	assert.Equal(t, "Description 2", tasks[1].Description)
-	assert.Equal(t, TaskPriorityMedium, tasks[1].Priority)
-	assert.Equal(t, TaskStatusInProgress, tasks[1].Status)
-	assert.Equal(t, int64(2), tasks[1].AssigneeID)
-	assert.Equal(t, int64(1), tasks[1].ProjectID)
-	assert.WithinDuration(t, now, tasks[1].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[1].CompletionDate.Time, time.Second)
+	assert.Equal(t, "Task", task.Title)
+	assert.Equal(t, "in_progress", task.Status)
+	assert.False(t, task.CompletionDate.Valid)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %s", err)
 	}
 }
 
-func TestSearchTasksByStatus(t *testing.T) {
+func TestListTaskSubtree(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("Error initializing mock database: %v", err)
@@ -303,45 +209,29 @@ func TestSearchTasksByStatus(t *testing.T) {
 	queries := New(db)
 
 	now := time.Now()
-	completionDate := sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date"}).
-		AddRow(1, "Test Task 1", "Description 1", TaskPriorityLow, TaskStatusNew, 1, 1, now, completionDate).
-		AddRow(2, "Test Task 2", "Description 2", TaskPriorityMedium, TaskStatusNew, 2, 2, now, completionDate)
+	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date", "version", "parent_task_id", "blocked", "due_date", "estimate", "sprint_id", "milestone_id", "depth", "category"}).
+		AddRow(2, "Design", "Description", TaskPriorityHigh, "completed", 1, 1, now, now, 1, 1, false, nil, nil, nil, nil, 1, StatusCategoryDone).
+		AddRow(3, "Mockups", "Description", TaskPriorityLow, "new", 1, 1, now, nil, 1, 2, false, nil, nil, nil, nil, 2, StatusCategoryNew)
 
-	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE status = \\$1 ORDER BY creation_date ASC").
-		WithArgs(TaskStatusNew).
+	mock.ExpectQuery("WITH RECURSIVE subtree AS (.+) ORDER BY subtree.depth, tasks.creation_date, tasks.id").
+		WithArgs(int64(1)).
 		WillReturnRows(rows)
 
-	tasks, err := queries.SearchTasksByStatus(context.Background(), TaskStatusNew)
+	subtree, err := queries.ListTaskSubtree(context.Background(), 1)
 
 	assert.NoError(t, err)
-	assert.Len(t, tasks, 2)
-	assert.Equal(t, int64(1), tasks[0].ID)
-	assert.Equal(t, "Test Task 1", tasks[0].Title)
-	assert.Equal(t, "Description 1", tasks[0].Description)
-	assert.Equal(t, TaskPriorityLow, tasks[0].Priority)
-	assert.Equal(t, TaskStatusNew, tasks[0].Status)
-	assert.Equal(t, int64(1), tasks[0].AssigneeID)
-	assert.Equal(t, int64(1), tasks[0].ProjectID)
-	assert.WithinDuration(t, now, tasks[0].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[0].CompletionDate.Time, time.Second)
-	assert.Equal(t, int64(2), tasks[1].ID)
-	assert.Equal(t, "Test Task 2", tasks[1].Title)
-	assert.Equal(t, "Description 2", tasks[1].Description)
-	assert.Equal(t, TaskPriorityMedium, tasks[1].Priority)
-	assert.Equal(t, TaskStatusNew, tasks[1].Status)
-	assert.Equal(t, int64(2), tasks[1].AssigneeID)
-	assert.Equal(t, int64(2), tasks[1].ProjectID)
-	assert.WithinDuration(t, now, tasks[1].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[1].CompletionDate.Time, time.Second)
+	assert.Len(t, subtree, 2)
+	assert.Equal(t, int64(1), subtree[0].Task.ParentTaskID.Int64)
+	assert.Equal(t, StatusCategoryDone, subtree[0].Category)
+	assert.Equal(t, int32(2), subtree[1].Depth)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %s", err)
 	}
 }
 
-func TestSearchTasksByTitle(t *testing.T) {
+func TestIsTaskAncestor(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("Error initializing mock database: %v", err)
@@ -350,103 +240,47 @@ func TestSearchTasksByTitle(t *testing.T) {
 
 	queries := New(db)
 
-	now := time.Now()
-	completionDate := sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true}
-
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date"}).
-		AddRow(1, "Test Task 1", "Description 1", TaskPriorityLow, TaskStatusNew, 1, 1, now, completionDate).
-		AddRow(2, "Test Task 2", "Description 2", TaskPriorityMedium, TaskStatusNew, 2, 2, now, completionDate)
+	mock.ExpectQuery("WITH RECURSIVE ancestors AS (.+) SELECT EXISTS").
+		WithArgs(int64(1), int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 
-	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE title ILIKE '%' \\|\\| \\$1 \\|\\| '%' ORDER BY creation_date ASC").
-		WithArgs("Test").
-		WillReturnRows(rows)
-
-	// Create a sql.NullString for the title
-	title := sql.NullString{String: "Test", Valid: true}
-
-	tasks, err := queries.SearchTasksByTitle(context.Background(), title)
+	cycle, err := queries.IsTaskAncestor(context.Background(), IsTaskAncestorParams{TaskID: 3, AncestorID: 1})
 
 	assert.NoError(t, err)
-	assert.Len(t, tasks, 2)
-	assert.Equal(t, int64(1), tasks[0].ID)
-	assert.Equal(t, "Test Task 1", tasks[0].Title)
-	assert.Equal(t, "Description 1", tasks[0].Description)
-	assert.Equal(t, TaskPriorityLow, tasks[0].Priority)
-	assert.Equal(t, TaskStatusNew, tasks[0].Status)
-	assert.Equal(t, int64(1), tasks[0].AssigneeID)
-	assert.Equal(t, int64(1), tasks[0].ProjectID)
-	assert.WithinDuration(t, now, tasks[0].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[0].CompletionDate.Time, time.Second)
-	assert.Equal(t, int64(2), tasks[1].ID)
-	assert.Equal(t, "Test Task 2", tasks[1].Title)
-	assert.Equal(t, "Description 2", tasks[1].Description)
-	assert.Equal(t, TaskPriorityMedium, tasks[1].Priority)
-	assert.Equal(t, TaskStatusNew, tasks[1].Status)
-	assert.Equal(t, int64(2), tasks[1].AssigneeID)
-	assert.Equal(t, int64(2), tasks[1].ProjectID)
-	assert.WithinDuration(t, now, tasks[1].CreationDate, time.Second)
-	assert.WithinDuration(t, completionDate.Time, tasks[1].CompletionDate.Time, time.Second)
+	assert.True(t, cycle)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %s", err)
 	}
 }
 
-func TestUpdateTask(t *testing.T) {
-	// Initialize mock DB
+func TestCountDueOutsideDates(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("Error initializing mock database: %v", err)
 	}
 	defer db.Close()
 
-	// Replace q.db with mock DB for testing purposes
 	queries := New(db)
 
-	// Capture the current time for consistent use
-	now := time.Now()
-	completionDate := sql.NullTime{Time: now, Valid: true}
-
-	// Mock expected rows to return
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date"}).
-		AddRow(1, "Updated Task", "Updated Description", TaskPriorityHigh, TaskStatusInProgress, int64(123), int64(456), now, now)
-
-	// Expectation: QueryRowContext with expected arguments
-	mock.ExpectQuery("UPDATE tasks SET title = \\$2, description = \\$3, priority = \\$4, status = \\$5, assignee_id = \\$6, project_id = \\$7, completion_date = \\$8 WHERE id = \\$1 RETURNING id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date").
-		WithArgs(int64(1), "Updated Task", "Updated Description", TaskPriorityHigh, TaskStatusInProgress, int64(123), int64(456), completionDate).
-		WillReturnRows(rows)
+	start := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 7, 31, 0, 0, 0, 0, time.UTC)
 
-	// Prepare input params
-	params := UpdateTaskParams{
-		ID:             1,
-		Title:          "Updated Task",
-		Description:    "Updated Description",
-		Priority:       TaskPriorityHigh,
-		Status:         TaskStatusInProgress,
-		AssigneeID:     123,
-		ProjectID:      456,
-		CompletionDate: completionDate,
-	}
-
-	// Call the UpdateTask method
-	task, err := queries.UpdateTask(context.Background(), params)
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FILTER (.+) FROM \\( SELECT t.due_date FROM tasks t (.+) UNION ALL SELECT m.due_date FROM milestones m (.+) \\) due").
+		WithArgs(start, end, int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"before_start", "after_end"}).AddRow(0, 2))
 
-	// Verify results
-	assert.NoError(t, err, "Expected no error")
-	assert.Equal(t, int64(1), task.ID, "Expected task ID to match")
-	assert.Equal(t, "Updated Task", task.Title, "Expected task title to match")
-	assert.Equal(t, "Updated Description", task.Description, "Expected task description to match")
-	assert.Equal(t, TaskPriorityHigh, task.Priority, "Expected task priority to match")
-	assert.Equal(t, TaskStatusInProgress, task.Status, "Expected task status to match")
-	assert.Equal(t, int64(123), task.AssigneeID, "Expected task assignee ID to match")
-	assert.Equal(t, int64(456), task.ProjectID, "Expected task project ID to match")
-	assert.WithinDuration(t, now, task.CreationDate, time.Second, "Expected task creation date to match")
+	due, err := queries.CountDueOutsideDates(context.Background(), CountDueOutsideDatesParams{
+		ProjectID: 1,
+		StartDate: start,
+		EndDate:   end,
+	})
 
-	assert.True(t, task.CompletionDate.Valid, "Expected task completion date to be valid")
-	assert.WithinDuration(t, now, task.CompletionDate.Time, time.Second, "Expected task completion date to match")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), due.BeforeStart)
+	assert.Equal(t, int64(2), due.AfterEnd)
 
-	// Assert all expectations were met
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %s", err)
 	}
-}
\ No newline at end of file
+}
@@ -13,21 +13,27 @@ import (
 
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
-    full_name, email, role
+    full_name, email, role, hashed_password
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4
 )
-RETURNING id, full_name, email, registration_date, role
+RETURNING id, full_name, email, registration_date, role, hashed_password, version, weekly_capacity
 `
 
 type CreateUserParams struct {
-	FullName string `json:"full_name"`
-	Email    string `json:"email"`
-	Role     string `json:"role"`
+	FullName       string   `json:"full_name"`
+	Email          string   `json:"email"`
+	Role           UserRole `json:"role"`
+	HashedPassword string   `json:"hashed_password"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.FullName, arg.Email, arg.Role)
+	row := q.db.QueryRowContext(ctx, createUser,
+		arg.FullName,
+		arg.Email,
+		arg.Role,
+		arg.HashedPassword,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -35,6 +41,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.RegistrationDate,
 		&i.Role,
+		&i.HashedPassword,
+		&i.Version,
+		&i.WeeklyCapacity,
 	)
 	return i, err
 }
@@ -50,7 +59,7 @@ func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, full_name, email, registration_date, role FROM users
+SELECT id, full_name, email, registration_date, role, hashed_password, version, weekly_capacity FROM users
 WHERE id = $1 LIMIT 1
 `
 
@@ -63,151 +72,81 @@ func (q *Queries) GetUser(ctx context.Context, id int64) (User, error) {
 		&i.Email,
 		&i.RegistrationDate,
 		&i.Role,
+		&i.HashedPassword,
+		&i.Version,
+		&i.WeeklyCapacity,
 	)
 	return i, err
 }
 
-const getUserTasks = `-- name: GetUserTasks :many
-SELECT id, title, description, priority, status, assignee_id, project_id, creation_date, completion_date FROM tasks
-WHERE assignee_id = $1
-ORDER BY creation_date ASC
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, full_name, email, registration_date, role, hashed_password, version, weekly_capacity FROM users
+WHERE email = $1 LIMIT 1
 `
 
-func (q *Queries) GetUserTasks(ctx context.Context, assigneeID int64) ([]Task, error) {
-	rows, err := q.db.QueryContext(ctx, getUserTasks, assigneeID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Task{}
-	for rows.Next() {
-		var i Task
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.Priority,
-			&i.Status,
-			&i.AssigneeID,
-			&i.ProjectID,
-			&i.CreationDate,
-			&i.CompletionDate,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const listUsers = `-- name: ListUsers :many
-SELECT id, full_name, email, registration_date, role FROM users
-ORDER BY full_name ASC
-`
-
-func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, listUsers)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []User{}
-	for rows.Next() {
-		var i User
-		if err := rows.Scan(
-			&i.ID,
-			&i.FullName,
-			&i.Email,
-			&i.RegistrationDate,
-			&i.Role,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FullName,
+		&i.Email,
+		&i.RegistrationDate,
+		&i.Role,
+		&i.HashedPassword,
+		&i.Version,
+		&i.WeeklyCapacity,
+	)
+	return i, err
 }
 
-const searchUsersByEmail = `-- name: SearchUsersByEmail :many
-SELECT id, full_name, email, registration_date, role FROM users
-WHERE email ILIKE '%' || $1 || '%'
-ORDER BY email ASC
+const patchUser = `-- name: PatchUser :one
+UPDATE users
+SET
+    full_name = COALESCE($1, full_name),
+    email = COALESCE($2, email),
+    role = COALESCE($3, role),
+    weekly_capacity = CASE WHEN $4::boolean
+        THEN $5::double precision
+        ELSE weekly_capacity
+    END,
+    version = version + 1
+WHERE id = $6 AND version = $7
+RETURNING id, full_name, email, registration_date, role, hashed_password, version, weekly_capacity
 `
 
-func (q *Queries) SearchUsersByEmail(ctx context.Context, dollar_1 sql.NullString) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, searchUsersByEmail, dollar_1)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []User{}
-	for rows.Next() {
-		var i User
-		if err := rows.Scan(
-			&i.ID,
-			&i.FullName,
-			&i.Email,
-			&i.RegistrationDate,
-			&i.Role,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type PatchUserParams struct {
+	FullName          sql.NullString  `json:"full_name"`
+	Email             sql.NullString  `json:"email"`
+	Role              NullUserRole    `json:"role"`
+	SetWeeklyCapacity bool            `json:"set_weekly_capacity"`
+	WeeklyCapacity    sql.NullFloat64 `json:"weekly_capacity"`
+	ID                int64           `json:"id"`
+	Version           int64           `json:"version"`
 }
 
-const searchUsersByName = `-- name: SearchUsersByName :many
-SELECT id, full_name, email, registration_date, role FROM users
-WHERE full_name ILIKE '%' || $1 || '%'
-ORDER BY full_name ASC
-`
-
-func (q *Queries) SearchUsersByName(ctx context.Context, dollar_1 sql.NullString) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, searchUsersByName, dollar_1)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []User{}
-	for rows.Next() {
-		var i User
-		if err := rows.Scan(
-			&i.ID,
-			&i.FullName,
-			&i.Email,
-			&i.RegistrationDate,
-			&i.Role,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) PatchUser(ctx context.Context, arg PatchUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, patchUser,
+		arg.FullName,
+		arg.Email,
+		arg.Role,
+		arg.SetWeeklyCapacity,
+		arg.WeeklyCapacity,
+		arg.ID,
+		arg.Version,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.FullName,
+		&i.Email,
+		&i.RegistrationDate,
+		&i.Role,
+		&i.HashedPassword,
+		&i.Version,
+		&i.WeeklyCapacity,
+	)
+	return i, err
 }
 
 const updateUser = `-- name: UpdateUser :one
@@ -216,17 +155,19 @@ SET
     full_name = $2,
     email = $3,
     role = $4,
-    registration_date = $5
-WHERE id = $1
-RETURNING id, full_name, email, registration_date, role
+    registration_date = $5,
+    version = version + 1
+WHERE id = $1 AND version = $6
+RETURNING id, full_name, email, registration_date, role, hashed_password, version, weekly_capacity
 `
 
 type UpdateUserParams struct {
 	ID               int64     `json:"id"`
 	FullName         string    `json:"full_name"`
 	Email            string    `json:"email"`
-	Role             string    `json:"role"`
+	Role             UserRole  `json:"role"`
 	RegistrationDate time.Time `json:"registration_date"`
+	Version          int64     `json:"version"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -236,6 +177,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Email,
 		arg.Role,
 		arg.RegistrationDate,
+		arg.Version,
 	)
 	var i User
 	err := row.Scan(
@@ -244,6 +186,9 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Email,
 		&i.RegistrationDate,
 		&i.Role,
+		&i.HashedPassword,
+		&i.Version,
+		&i.WeeklyCapacity,
 	)
 	return i, err
 }
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+)
+
+// userColumns lists the columns of the users table in the order they are
+// scanned into a User
+const userColumns = `id, full_name, email, registration_date, role, hashed_password, version, weekly_capacity`
+
+// SearchUsersParams holds the filters of SearchUsers, zero values are ignored
+type SearchUsersParams struct {
+	FullName  string        `json:"full_name"`
+	Email     string        `json:"email"`
+	Sort      Sort          `json:"sort"`
+	After     []interface{} `json:"after"`
+	PageLimit int32         `json:"page_limit"`
+}
+
+// UserSortColumns are the columns users can be sorted by
+var UserSortColumns = SortColumns[User]{
+	"id":                func(u User) interface{} { return u.ID },
+	"full_name":         func(u User) interface{} { return u.FullName },
+	"email":             func(u User) interface{} { return u.Email },
+	"registration_date": func(u User) interface{} { return u.RegistrationDate },
+	"role":              func(u User) interface{} { return u.Role },
+}
+
+// DefaultUserSort orders users alphabetically by name
+var DefaultUserSort = Sort{{Column: "full_name"}, {Column: "id"}}
+
+// SearchUsers returns the users matching every filter of arg, in the
+// requested order and starting after the row the After values were taken from
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	sort := arg.Sort
+	if len(sort) == 0 {
+		sort = DefaultUserSort
+	}
+	if err := UserSortColumns.validate(sort); err != nil {
+		return nil, err
+	}
+
+	var b queryBuilder
+
+	if arg.FullName != "" {
+		b.whereContains("full_name", arg.FullName)
+	}
+	if arg.Email != "" {
+		b.whereContains("email", arg.Email)
+	}
+	if err := b.after(sort, arg.After); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + userColumns + " FROM users\n" + b.clause() +
+		"\n" + orderBy(sort) + "\nLIMIT " + b.arg(arg.PageLimit)
+
+	rows, err := q.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.FullName,
+			&i.Email,
+			&i.RegistrationDate,
+			&i.Role,
+			&i.HashedPassword,
+			&i.Version,
+			&i.WeeklyCapacity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchUsersWithoutFilters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role", "hashed_password", "version", "weekly_capacity"}).
+		AddRow(1, "Alice Smith", "alice@example.com", now, UserRoleMember, "secret-hash", 1, nil).
+		AddRow(2, "Bob Johnson", "bob@example.com", now, UserRoleAdmin, "secret-hash", 1, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM users ORDER BY full_name ASC, id ASC LIMIT \\$1").
+		WithArgs(int32(21)).
+		WillReturnRows(rows)
+
+	users, err := queries.SearchUsers(context.Background(), SearchUsersParams{PageLimit: 21})
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "Alice Smith", users[0].FullName)
+	assert.Equal(t, UserRoleAdmin, users[1].Role)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSearchUsersByEmail(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role", "hashed_password", "version", "weekly_capacity"}).
+		AddRow(1, "Alice Smith", "alice@example.com", now, UserRoleMember, "secret-hash", 1, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE email ILIKE '%' \\|\\| \\$1 \\|\\| '%' ESCAPE '\\\\' ORDER BY email ASC, id ASC LIMIT \\$2").
+		WithArgs("alice", int32(21)).
+		WillReturnRows(rows)
+
+	users, err := queries.SearchUsers(context.Background(), SearchUsersParams{
+		Email:     "alice",
+		Sort:      Sort{{Column: "email"}, {Column: "id"}},
+		PageLimit: 21,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "Alice Smith", users[0].FullName)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSearchUsersByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role", "hashed_password", "version", "weekly_capacity"}).
+		AddRow(2, "Bob Johnson", "bob@example.com", now, UserRoleAdmin, "secret-hash", 1, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM users WHERE full_name ILIKE '%' \\|\\| \\$1 \\|\\| '%' ESCAPE '\\\\' ORDER BY registration_date DESC, id ASC LIMIT \\$2").
+		WithArgs("Bob", int32(21)).
+		WillReturnRows(rows)
+
+	users, err := queries.SearchUsers(context.Background(), SearchUsersParams{
+		FullName:  "Bob",
+		Sort:      Sort{{Column: "registration_date", Desc: true}, {Column: "id"}},
+		PageLimit: 21,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "Bob Johnson", users[0].FullName)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
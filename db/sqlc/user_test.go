@@ -21,17 +21,18 @@ func TestCreateUser(t *testing.T) {
 
 	now := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role"}).
-		AddRow(1, "Test User", "test@example.com", now, "user")
+	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role", "hashed_password", "version", "weekly_capacity"}).
+		AddRow(1, "Test User", "test@example.com", now, UserRoleMember, "secret-hash", 1, nil)
 
 	mock.ExpectQuery("INSERT INTO users").
-		WithArgs("Test User", "test@example.com", "user").
+		WithArgs("Test User", "test@example.com", UserRoleMember, "secret-hash").
 		WillReturnRows(rows)
 
 	params := CreateUserParams{
-		FullName: "Test User",
-		Email:    "test@example.com",
-		Role:     "user",
+		FullName:       "Test User",
+		Email:          "test@example.com",
+		Role:           UserRoleMember,
+		HashedPassword: "secret-hash",
 	}
 
 	user, err := queries.CreateUser(context.Background(), params)
@@ -41,7 +42,7 @@ func TestCreateUser(t *testing.T) {
 	assert.Equal(t, "Test User", user.FullName)
 	assert.Equal(t, "test@example.com", user.Email)
 	assert.WithinDuration(t, now, user.RegistrationDate, time.Second)
-	assert.Equal(t, "user", user.Role)
+	assert.Equal(t, UserRoleMember, user.Role)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %s", err)
@@ -80,8 +81,8 @@ func TestGetUser(t *testing.T) {
 
 	now := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role"}).
-		AddRow(1, "Test User", "test@example.com", now, "user")
+	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role", "hashed_password", "version", "weekly_capacity"}).
+		AddRow(1, "Test User", "test@example.com", now, UserRoleMember, "secret-hash", 1, nil)
 
 	mock.ExpectQuery("SELECT (.+) FROM users WHERE id = \\$1 LIMIT 1").
 		WithArgs(1).
@@ -94,74 +95,14 @@ func TestGetUser(t *testing.T) {
 	assert.Equal(t, "Test User", user.FullName)
 	assert.Equal(t, "test@example.com", user.Email)
 	assert.WithinDuration(t, now, user.RegistrationDate, time.Second)
-	assert.Equal(t, "user", user.Role)
+	assert.Equal(t, UserRoleMember, user.Role)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %s", err)
 	}
 }
 
-func TestGetUserTasks(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("Error initializing mock database: %v", err)
-	}
-	defer db.Close()
-
-	queries := New(db)
-
-	now := time.Now()
-	completionDate := sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true}
-
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "priority", "status", "assignee_id", "project_id", "creation_date", "completion_date"}).
-		AddRow(1, "Test Task 1", "Description 1", TaskPriorityLow, TaskStatusNew, 1, 1, now, completionDate).
-		AddRow(2, "Test Task 2", "Description 2", TaskPriorityMedium, TaskStatusNew, 1, 2, now, completionDate)
-
-	mock.ExpectQuery("SELECT (.+) FROM tasks WHERE assignee_id = \\$1 ORDER BY creation_date ASC").
-		WithArgs(1).
-		WillReturnRows(rows)
-
-	tasks, err := queries.GetUserTasks(context.Background(), 1)
-
-	assert.NoError(t, err)
-	assert.Len(t, tasks, 2)
-	assert.Equal(t, int64(1), tasks[0].ID)
-	assert.Equal(t, "Test Task 1", tasks[0].Title)
-
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("Unfulfilled expectations: %s", err)
-	}
-}
-
-func TestListUsers(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("Error initializing mock database: %v", err)
-	}
-	defer db.Close()
-
-	queries := New(db)
-
-	now := time.Now()
-
-	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role"}).
-		AddRow(1, "Alice Smith", "alice@example.com", now, "user").
-		AddRow(2, "Bob Johnson", "bob@example.com", now, "admin")
-
-	mock.ExpectQuery("SELECT (.+) FROM users ORDER BY full_name ASC").
-		WillReturnRows(rows)
-
-	users, err := queries.ListUsers(context.Background())
-	assert.NoError(t, err)
-	assert.Len(t, users, 2)
-	assert.Equal(t, "Alice Smith", users[0].FullName)
-
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("Unfulfilled expectations: %s", err)
-	}
-}
-
-func TestSearchUsersByEmail(t *testing.T) {
+func TestUpdateUser(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("Error initializing mock database: %v", err)
@@ -170,56 +111,33 @@ func TestSearchUsersByEmail(t *testing.T) {
 
 	queries := New(db)
 
-	email := sql.NullString{String: "alice", Valid: true}
-	now := time.Now()
-
-	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role"}).
-		AddRow(1, "Alice Smith", "alice@example.com", now, "user")
-
-	mock.ExpectQuery("SELECT (.+) FROM users WHERE email ILIKE '%' || \\$1 || '%' ORDER BY email ASC").
-		WithArgs(email).
-		WillReturnRows(rows)
-
-	users, err := queries.SearchUsersByEmail(context.Background(), email)
-	assert.NoError(t, err)
-	assert.Len(t, users, 1)
-	assert.Equal(t, "Alice Smith", users[0].FullName)
-
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("Unfulfilled expectations: %s", err)
-	}
-}
-
-func TestSearchUsersByName(t *testing.T) {
-	db, mock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("Error initializing mock database: %v", err)
+	arg := UpdateUserParams{
+		ID:               1,
+		FullName:         "Boris Smith",
+		Email:            "boris@example.com",
+		Role:             UserRoleMember,
+		RegistrationDate: time.Now(),
+		Version:          1,
 	}
-	defer db.Close()
-
-	queries := New(db)
-
-	name := sql.NullString{String: "Bob", Valid: true}
-	now := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role"}).
-		AddRow(2, "Bob Johnson", "bob@example.com", now, "admin")
+	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role", "hashed_password", "version", "weekly_capacity"}).
+		AddRow(arg.ID, arg.FullName, arg.Email, arg.RegistrationDate, arg.Role, "secret-hash", 1, nil)
 
-	mock.ExpectQuery("SELECT (.+) FROM users WHERE full_name ILIKE '%' || \\$1 || '%' ORDER BY full_name ASC").
-		WithArgs(name).
+	mock.ExpectQuery("UPDATE users SET (.+) WHERE id = \\$1 AND version = \\$6 RETURNING (.+)").
+		WithArgs(arg.ID, arg.FullName, arg.Email, arg.Role, arg.RegistrationDate, arg.Version).
 		WillReturnRows(rows)
 
-	users, err := queries.SearchUsersByName(context.Background(), name)
+	user, err := queries.UpdateUser(context.Background(), arg)
 	assert.NoError(t, err)
-	assert.Len(t, users, 1)
-	assert.Equal(t, "Bob Johnson", users[0].FullName)
+	assert.Equal(t, arg.ID, user.ID)
+	assert.Equal(t, arg.FullName, user.FullName)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %s", err)
 	}
 }
 
-func TestUpdateUser(t *testing.T) {
+func TestPatchUser(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
 		t.Fatalf("Error initializing mock database: %v", err)
@@ -228,25 +146,23 @@ func TestUpdateUser(t *testing.T) {
 
 	queries := New(db)
 
-	arg := UpdateUserParams{
-		ID:               1,
-		FullName:         "Boris Smith",
-		Email:            "boris@example.com",
-		Role:             "user",
-		RegistrationDate: time.Now(),
+	arg := PatchUserParams{
+		ID:       1,
+		FullName: sql.NullString{String: "Boris Smith", Valid: true},
+		Version:  1,
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role"}).
-		AddRow(arg.ID, arg.FullName, arg.Email, arg.RegistrationDate, arg.Role)
+	rows := sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role", "hashed_password", "version", "weekly_capacity"}).
+		AddRow(arg.ID, "Boris Smith", "boris@example.com", time.Now(), UserRoleMember, "secret-hash", 1, nil)
 
-	mock.ExpectQuery("UPDATE users SET (.+) WHERE id = \\$1 RETURNING (.+)").
-		WithArgs(arg.ID, arg.FullName, arg.Email, arg.Role, arg.RegistrationDate).
+	mock.ExpectQuery("UPDATE users SET full_name = COALESCE\\(\\$1, full_name\\)(.+)WHERE id = \\$6 AND version = \\$7").
+		WithArgs(arg.FullName, sql.NullString{}, NullUserRole{}, false, sql.NullFloat64{}, arg.ID, arg.Version).
 		WillReturnRows(rows)
 
-	user, err := queries.UpdateUser(context.Background(), arg)
+	user, err := queries.PatchUser(context.Background(), arg)
 	assert.NoError(t, err)
-	assert.Equal(t, arg.ID, user.ID)
-	assert.Equal(t, arg.FullName, user.FullName)
+	assert.Equal(t, "Boris Smith", user.FullName)
+	assert.Equal(t, "boris@example.com", user.Email)
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %s", err)
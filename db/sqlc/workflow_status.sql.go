@@ -0,0 +1,178 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: workflow_status.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createDefaultWorkflowStatuses = `-- name: CreateDefaultWorkflowStatuses :exec
+INSERT INTO workflow_statuses (project_id, name, category, position)
+VALUES
+    ($1, 'new', 'new', 1),
+    ($1, 'in_progress', 'active', 2),
+    ($1, 'completed', 'done', 3)
+`
+
+func (q *Queries) CreateDefaultWorkflowStatuses(ctx context.Context, projectID int64) error {
+	_, err := q.db.ExecContext(ctx, createDefaultWorkflowStatuses, projectID)
+	return err
+}
+
+const createWorkflowStatus = `-- name: CreateWorkflowStatus :one
+INSERT INTO workflow_statuses (
+    project_id, name, category, position
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING id, project_id, name, category, position
+`
+
+type CreateWorkflowStatusParams struct {
+	ProjectID int64          `json:"project_id"`
+	Name      string         `json:"name"`
+	Category  StatusCategory `json:"category"`
+	Position  int32          `json:"position"`
+}
+
+func (q *Queries) CreateWorkflowStatus(ctx context.Context, arg CreateWorkflowStatusParams) (WorkflowStatus, error) {
+	row := q.db.QueryRowContext(ctx, createWorkflowStatus,
+		arg.ProjectID,
+		arg.Name,
+		arg.Category,
+		arg.Position,
+	)
+	var i WorkflowStatus
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Category,
+		&i.Position,
+	)
+	return i, err
+}
+
+const deleteWorkflowStatus = `-- name: DeleteWorkflowStatus :exec
+DELETE FROM workflow_statuses
+WHERE id = $1
+`
+
+func (q *Queries) DeleteWorkflowStatus(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkflowStatus, id)
+	return err
+}
+
+const getWorkflowStatus = `-- name: GetWorkflowStatus :one
+SELECT id, project_id, name, category, position FROM workflow_statuses
+WHERE project_id = $1 AND name = $2 LIMIT 1
+`
+
+type GetWorkflowStatusParams struct {
+	ProjectID int64  `json:"project_id"`
+	Name      string `json:"name"`
+}
+
+func (q *Queries) GetWorkflowStatus(ctx context.Context, arg GetWorkflowStatusParams) (WorkflowStatus, error) {
+	row := q.db.QueryRowContext(ctx, getWorkflowStatus, arg.ProjectID, arg.Name)
+	var i WorkflowStatus
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Category,
+		&i.Position,
+	)
+	return i, err
+}
+
+const getWorkflowStatusByID = `-- name: GetWorkflowStatusByID :one
+SELECT id, project_id, name, category, position FROM workflow_statuses
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetWorkflowStatusByID(ctx context.Context, id int64) (WorkflowStatus, error) {
+	row := q.db.QueryRowContext(ctx, getWorkflowStatusByID, id)
+	var i WorkflowStatus
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Category,
+		&i.Position,
+	)
+	return i, err
+}
+
+const listWorkflowStatuses = `-- name: ListWorkflowStatuses :many
+SELECT id, project_id, name, category, position FROM workflow_statuses
+WHERE project_id = $1
+ORDER BY position, id
+`
+
+func (q *Queries) ListWorkflowStatuses(ctx context.Context, projectID int64) ([]WorkflowStatus, error) {
+	rows, err := q.db.QueryContext(ctx, listWorkflowStatuses, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []WorkflowStatus{}
+	for rows.Next() {
+		var i WorkflowStatus
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Name,
+			&i.Category,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWorkflowStatus = `-- name: UpdateWorkflowStatus :one
+UPDATE workflow_statuses
+SET
+    name = $2,
+    category = $3,
+    position = $4
+WHERE id = $1
+RETURNING id, project_id, name, category, position
+`
+
+type UpdateWorkflowStatusParams struct {
+	ID       int64          `json:"id"`
+	Name     string         `json:"name"`
+	Category StatusCategory `json:"category"`
+	Position int32          `json:"position"`
+}
+
+func (q *Queries) UpdateWorkflowStatus(ctx context.Context, arg UpdateWorkflowStatusParams) (WorkflowStatus, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkflowStatus,
+		arg.ID,
+		arg.Name,
+		arg.Category,
+		arg.Position,
+	)
+	var i WorkflowStatus
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Name,
+		&i.Category,
+		&i.Position,
+	)
+	return i, err
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListWorkflowStatuses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"id", "project_id", "name", "category", "position"}).
+		AddRow(1, 4, "new", StatusCategoryNew, 1).
+		AddRow(2, 4, "review", StatusCategoryActive, 2)
+
+	mock.ExpectQuery("SELECT (.+) FROM workflow_statuses WHERE project_id = \\$1 ORDER BY position, id").
+		WithArgs(int64(4)).
+		WillReturnRows(rows)
+
+	statuses, err := queries.ListWorkflowStatuses(context.Background(), 4)
+
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "review", statuses[1].Name)
+	assert.Equal(t, StatusCategoryActive, statuses[1].Category)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestGetWorkflowStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"id", "project_id", "name", "category", "position"}).
+		AddRow(3, 4, "qa", StatusCategoryActive, 3)
+
+	mock.ExpectQuery("SELECT (.+) FROM workflow_statuses WHERE project_id = \\$1 AND name = \\$2 LIMIT 1").
+		WithArgs(int64(4), "qa").
+		WillReturnRows(rows)
+
+	status, err := queries.GetWorkflowStatus(context.Background(), GetWorkflowStatusParams{ProjectID: 4, Name: "qa"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), status.ID)
+	assert.Equal(t, StatusCategoryActive, status.Category)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
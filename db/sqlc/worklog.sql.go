@@ -0,0 +1,408 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.26.0
+// source: worklog.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createWorklog = `-- name: CreateWorklog :one
+INSERT INTO worklogs (
+    task_id, user_id, started_at, duration, note
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, task_id, user_id, started_at, duration, note
+`
+
+type CreateWorklogParams struct {
+	TaskID    int64         `json:"task_id"`
+	UserID    int64         `json:"user_id"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  sql.NullInt64 `json:"duration"`
+	Note      string        `json:"note"`
+}
+
+func (q *Queries) CreateWorklog(ctx context.Context, arg CreateWorklogParams) (Worklog, error) {
+	row := q.db.QueryRowContext(ctx, createWorklog,
+		arg.TaskID,
+		arg.UserID,
+		arg.StartedAt,
+		arg.Duration,
+		arg.Note,
+	)
+	var i Worklog
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.UserID,
+		&i.StartedAt,
+		&i.Duration,
+		&i.Note,
+	)
+	return i, err
+}
+
+const deleteWorklog = `-- name: DeleteWorklog :exec
+DELETE FROM worklogs
+WHERE task_id = $1 AND id = $2
+`
+
+type DeleteWorklogParams struct {
+	TaskID int64 `json:"task_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) DeleteWorklog(ctx context.Context, arg DeleteWorklogParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorklog, arg.TaskID, arg.ID)
+	return err
+}
+
+const getWorklog = `-- name: GetWorklog :one
+SELECT id, task_id, user_id, started_at, duration, note FROM worklogs
+WHERE task_id = $1 AND id = $2 LIMIT 1
+`
+
+type GetWorklogParams struct {
+	TaskID int64 `json:"task_id"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) GetWorklog(ctx context.Context, arg GetWorklogParams) (Worklog, error) {
+	row := q.db.QueryRowContext(ctx, getWorklog, arg.TaskID, arg.ID)
+	var i Worklog
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.UserID,
+		&i.StartedAt,
+		&i.Duration,
+		&i.Note,
+	)
+	return i, err
+}
+
+const listTaskWorklogs = `-- name: ListTaskWorklogs :many
+SELECT id, task_id, user_id, started_at, duration, note FROM worklogs
+WHERE task_id = $1
+ORDER BY started_at, id
+`
+
+func (q *Queries) ListTaskWorklogs(ctx context.Context, taskID int64) ([]Worklog, error) {
+	rows, err := q.db.QueryContext(ctx, listTaskWorklogs, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Worklog{}
+	for rows.Next() {
+		var i Worklog
+		if err := rows.Scan(
+			&i.ID,
+			&i.TaskID,
+			&i.UserID,
+			&i.StartedAt,
+			&i.Duration,
+			&i.Note,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const startTimer = `-- name: StartTimer :one
+INSERT INTO worklogs (
+    task_id, user_id, started_at, note
+) VALUES (
+    $1, $2, now() AT TIME ZONE 'UTC', $3
+)
+RETURNING id, task_id, user_id, started_at, duration, note
+`
+
+type StartTimerParams struct {
+	TaskID int64  `json:"task_id"`
+	UserID int64  `json:"user_id"`
+	Note   string `json:"note"`
+}
+
+// Worklogs are stored in UTC like the entries logged by hand
+func (q *Queries) StartTimer(ctx context.Context, arg StartTimerParams) (Worklog, error) {
+	row := q.db.QueryRowContext(ctx, startTimer, arg.TaskID, arg.UserID, arg.Note)
+	var i Worklog
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.UserID,
+		&i.StartedAt,
+		&i.Duration,
+		&i.Note,
+	)
+	return i, err
+}
+
+const stopTimer = `-- name: StopTimer :one
+UPDATE worklogs
+SET duration = GREATEST(0, floor(extract(epoch FROM (now() AT TIME ZONE 'UTC') - started_at)))::bigint
+WHERE task_id = $1 AND user_id = $2 AND duration IS NULL
+RETURNING id, task_id, user_id, started_at, duration, note
+`
+
+type StopTimerParams struct {
+	TaskID int64 `json:"task_id"`
+	UserID int64 `json:"user_id"`
+}
+
+// Stops the running timer of the user on the task, recording the elapsed
+// whole seconds as its duration
+func (q *Queries) StopTimer(ctx context.Context, arg StopTimerParams) (Worklog, error) {
+	row := q.db.QueryRowContext(ctx, stopTimer, arg.TaskID, arg.UserID)
+	var i Worklog
+	err := row.Scan(
+		&i.ID,
+		&i.TaskID,
+		&i.UserID,
+		&i.StartedAt,
+		&i.Duration,
+		&i.Note,
+	)
+	return i, err
+}
+
+const sumProjectWorklogsByTask = `-- name: SumProjectWorklogsByTask :many
+SELECT t.id AS task_id, t.title, sum(w.duration)::bigint AS seconds, count(*) AS entries
+FROM worklogs w
+JOIN tasks t ON t.id = w.task_id
+WHERE t.project_id = $1
+    AND w.duration IS NOT NULL
+    AND ($2::timestamp IS NULL OR w.started_at >= $2)
+    AND ($3::timestamp IS NULL OR w.started_at <= $3)
+GROUP BY t.id, t.title
+ORDER BY seconds DESC, t.id
+`
+
+type SumProjectWorklogsByTaskParams struct {
+	ProjectID   int64        `json:"project_id"`
+	StartedFrom sql.NullTime `json:"started_from"`
+	StartedTo   sql.NullTime `json:"started_to"`
+}
+
+type SumProjectWorklogsByTaskRow struct {
+	TaskID  int64  `json:"task_id"`
+	Title   string `json:"title"`
+	Seconds int64  `json:"seconds"`
+	Entries int64  `json:"entries"`
+}
+
+func (q *Queries) SumProjectWorklogsByTask(ctx context.Context, arg SumProjectWorklogsByTaskParams) ([]SumProjectWorklogsByTaskRow, error) {
+	rows, err := q.db.QueryContext(ctx, sumProjectWorklogsByTask, arg.ProjectID, arg.StartedFrom, arg.StartedTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SumProjectWorklogsByTaskRow{}
+	for rows.Next() {
+		var i SumProjectWorklogsByTaskRow
+		if err := rows.Scan(
+			&i.TaskID,
+			&i.Title,
+			&i.Seconds,
+			&i.Entries,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumProjectWorklogsByUser = `-- name: SumProjectWorklogsByUser :many
+SELECT w.user_id, u.full_name, sum(w.duration)::bigint AS seconds, count(*) AS entries
+FROM worklogs w
+JOIN tasks t ON t.id = w.task_id
+JOIN users u ON u.id = w.user_id
+WHERE t.project_id = $1
+    AND w.duration IS NOT NULL
+    AND ($2::timestamp IS NULL OR w.started_at >= $2)
+    AND ($3::timestamp IS NULL OR w.started_at <= $3)
+GROUP BY w.user_id, u.full_name
+ORDER BY seconds DESC, w.user_id
+`
+
+type SumProjectWorklogsByUserParams struct {
+	ProjectID   int64        `json:"project_id"`
+	StartedFrom sql.NullTime `json:"started_from"`
+	StartedTo   sql.NullTime `json:"started_to"`
+}
+
+type SumProjectWorklogsByUserRow struct {
+	UserID   int64  `json:"user_id"`
+	FullName string `json:"full_name"`
+	Seconds  int64  `json:"seconds"`
+	Entries  int64  `json:"entries"`
+}
+
+func (q *Queries) SumProjectWorklogsByUser(ctx context.Context, arg SumProjectWorklogsByUserParams) ([]SumProjectWorklogsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, sumProjectWorklogsByUser, arg.ProjectID, arg.StartedFrom, arg.StartedTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SumProjectWorklogsByUserRow{}
+	for rows.Next() {
+		var i SumProjectWorklogsByUserRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.FullName,
+			&i.Seconds,
+			&i.Entries,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumTaskWorklogsByUser = `-- name: SumTaskWorklogsByUser :many
+SELECT w.user_id, u.full_name, sum(w.duration)::bigint AS seconds, count(*) AS entries
+FROM worklogs w
+JOIN users u ON u.id = w.user_id
+WHERE w.task_id = $1
+    AND w.duration IS NOT NULL
+    AND ($2::timestamp IS NULL OR w.started_at >= $2)
+    AND ($3::timestamp IS NULL OR w.started_at <= $3)
+GROUP BY w.user_id, u.full_name
+ORDER BY seconds DESC, w.user_id
+`
+
+type SumTaskWorklogsByUserParams struct {
+	TaskID      int64        `json:"task_id"`
+	StartedFrom sql.NullTime `json:"started_from"`
+	StartedTo   sql.NullTime `json:"started_to"`
+}
+
+type SumTaskWorklogsByUserRow struct {
+	UserID   int64  `json:"user_id"`
+	FullName string `json:"full_name"`
+	Seconds  int64  `json:"seconds"`
+	Entries  int64  `json:"entries"`
+}
+
+func (q *Queries) SumTaskWorklogsByUser(ctx context.Context, arg SumTaskWorklogsByUserParams) ([]SumTaskWorklogsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, sumTaskWorklogsByUser, arg.TaskID, arg.StartedFrom, arg.StartedTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SumTaskWorklogsByUserRow{}
+	for rows.Next() {
+		var i SumTaskWorklogsByUserRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.FullName,
+			&i.Seconds,
+			&i.Entries,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumUserWorklogsByProject = `-- name: SumUserWorklogsByProject :many
+SELECT p.id AS project_id, p.name, sum(w.duration)::bigint AS seconds, count(*) AS entries
+FROM worklogs w
+JOIN tasks t ON t.id = w.task_id
+JOIN projects p ON p.id = t.project_id
+WHERE w.user_id = $1
+    AND w.duration IS NOT NULL
+    AND ($2::timestamp IS NULL OR w.started_at >= $2)
+    AND ($3::timestamp IS NULL OR w.started_at <= $3)
+    AND ($4::boolean
+        OR p.id IN (SELECT pm.project_id FROM project_members pm WHERE pm.user_id = $5))
+GROUP BY p.id, p.name
+ORDER BY seconds DESC, p.id
+`
+
+type SumUserWorklogsByProjectParams struct {
+	UserID        int64        `json:"user_id"`
+	StartedFrom   sql.NullTime `json:"started_from"`
+	StartedTo     sql.NullTime `json:"started_to"`
+	ViewerIsAdmin bool         `json:"viewer_is_admin"`
+	ViewerID      int64        `json:"viewer_id"`
+}
+
+type SumUserWorklogsByProjectRow struct {
+	ProjectID int64  `json:"project_id"`
+	Name      string `json:"name"`
+	Seconds   int64  `json:"seconds"`
+	Entries   int64  `json:"entries"`
+}
+
+// Only counts the projects the viewer is a member of, unless the viewer is
+// an admin
+func (q *Queries) SumUserWorklogsByProject(ctx context.Context, arg SumUserWorklogsByProjectParams) ([]SumUserWorklogsByProjectRow, error) {
+	rows, err := q.db.QueryContext(ctx, sumUserWorklogsByProject,
+		arg.UserID,
+		arg.StartedFrom,
+		arg.StartedTo,
+		arg.ViewerIsAdmin,
+		arg.ViewerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SumUserWorklogsByProjectRow{}
+	for rows.Next() {
+		var i SumUserWorklogsByProjectRow
+		if err := rows.Scan(
+			&i.ProjectID,
+			&i.Name,
+			&i.Seconds,
+			&i.Entries,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
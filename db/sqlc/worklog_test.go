@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartTimer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"id", "task_id", "user_id", "started_at", "duration", "note"}).
+		AddRow(1, 2, 3, time.Now(), nil, "Pairing")
+
+	mock.ExpectQuery("INSERT INTO worklogs (.+) VALUES \\( \\$1, \\$2, now\\(\\) AT TIME ZONE 'UTC', \\$3 \\)").
+		WithArgs(int64(2), int64(3), "Pairing").
+		WillReturnRows(rows)
+
+	worklog, err := queries.StartTimer(context.Background(), StartTimerParams{TaskID: 2, UserID: 3, Note: "Pairing"})
+
+	assert.NoError(t, err)
+	assert.False(t, worklog.Duration.Valid)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestStopTimer(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	rows := sqlmock.NewRows([]string{"id", "task_id", "user_id", "started_at", "duration", "note"}).
+		AddRow(1, 2, 3, time.Now().Add(-time.Hour), 3600, "Pairing")
+
+	mock.ExpectQuery("UPDATE worklogs SET duration = (.+) WHERE task_id = \\$1 AND user_id = \\$2 AND duration IS NULL").
+		WithArgs(int64(2), int64(3)).
+		WillReturnRows(rows)
+
+	worklog, err := queries.StopTimer(context.Background(), StopTimerParams{TaskID: 2, UserID: 3})
+
+	assert.NoError(t, err)
+	assert.Equal(t, sql.NullInt64{Int64: 3600, Valid: true}, worklog.Duration)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSumProjectWorklogsByTask(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer db.Close()
+
+	queries := New(db)
+
+	from := sql.NullTime{Time: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	rows := sqlmock.NewRows([]string{"task_id", "title", "seconds", "entries"}).
+		AddRow(2, "API design", 5400, 2).
+		AddRow(4, "Mockups", 1800, 1)
+
+	mock.ExpectQuery("SELECT (.+) FROM worklogs w JOIN tasks t ON t.id = w.task_id WHERE t.project_id = \\$1 AND w.duration IS NOT NULL (.+) GROUP BY t.id, t.title").
+		WithArgs(int64(1), from, sql.NullTime{}).
+		WillReturnRows(rows)
+
+	totals, err := queries.SumProjectWorklogsByTask(context.Background(), SumProjectWorklogsByTaskParams{ProjectID: 1, StartedFrom: from})
+
+	assert.NoError(t, err)
+	assert.Len(t, totals, 2)
+	assert.Equal(t, int64(5400), totals[0].Seconds)
+	assert.Equal(t, int64(2), totals[0].Entries)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
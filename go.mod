@@ -7,6 +7,7 @@ require (
 	github.com/go-chi/chi/v5 v5.1.0
 	github.com/go-chi/cors v1.2.1
 	github.com/go-chi/render v1.0.3
+	github.com/golang-jwt/jwt/v5 v5.2.1
 	github.com/golang-migrate/migrate/v4 v4.17.1
 	github.com/hellofresh/health-go/v5 v5.5.3
 	github.com/lib/pq v1.10.9
@@ -16,6 +17,7 @@ require (
 	github.com/swaggo/swag v1.8.1
 	go.elastic.co/apm/module/apmzap v1.15.0
 	go.uber.org/zap v1.21.0
+	golang.org/x/crypto v0.21.0
 	google.golang.org/grpc v1.62.1
 )
 
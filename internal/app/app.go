@@ -30,6 +30,11 @@ func Run() {
 
 	database.InitDB()
 
+	if err = database.SeedAdmin(configs); err != nil {
+		logger.Error("ERR_SEED_ADMIN", zap.Error(err))
+		return
+	}
+
 	handlers, err := handlers.New(
 		handlers.Dependencies{
 			DB:      database.DB,
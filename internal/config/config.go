@@ -15,6 +15,11 @@ type Config struct {
 	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	BaseURL             string        `mapstructure:"BASE_URL"`
+	AdminEmail          string        `mapstructure:"ADMIN_EMAIL"`
+	AdminPassword       string        `mapstructure:"ADMIN_PASSWORD"`
+	ProblemJSON         bool          `mapstructure:"PROBLEM_JSON"`
+	BlobStore           string        `mapstructure:"BLOB_STORE"`
+	BlobDir             string        `mapstructure:"BLOB_DIR"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
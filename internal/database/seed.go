@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/config"
+	"project-management-service/util"
+)
+
+// SeedAdmin creates the initial administrator account from ADMIN_EMAIL and
+// ADMIN_PASSWORD, so that somebody is able to log in on a fresh database
+func SeedAdmin(config config.Config) error {
+	if config.AdminEmail == "" || config.AdminPassword == "" {
+		return nil
+	}
+
+	ctx := context.Background()
+	queries := db.New(DB)
+
+	_, err := queries.GetUserByEmail(ctx, config.AdminEmail)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	hashedPassword, err := util.HashPassword(config.AdminPassword)
+	if err != nil {
+		return err
+	}
+
+	_, err = queries.CreateUser(ctx, db.CreateUserParams{
+		FullName:       "Administrator",
+		Email:          config.AdminEmail,
+		Role:           db.UserRoleAdmin,
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Println("Admin user created:", config.AdminEmail)
+	return nil
+}
@@ -0,0 +1,133 @@
+package dberr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/lib/pq"
+
+	"project-management-service/internal/validation"
+)
+
+// Machine-readable codes of the translated errors, clients can rely on them
+// staying the same across releases
+const (
+	CodeAlreadyExists       = "already_exists"
+	CodeReferenceNotFound   = "reference_not_found"
+	CodeStillReferenced     = "still_referenced"
+	CodeInvalidValue        = "invalid_value"
+	CodeMissingValue        = "missing_value"
+	CodeValueTooLong        = "value_too_long"
+	CodeConstraintViolation = "constraint_violation"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	uniqueViolation         = "23505"
+	foreignKeyViolation     = "23503"
+	notNullViolation        = "23502"
+	checkViolation          = "23514"
+	invalidTextRepresention = "22P02"
+	invalidDatetimeFormat   = "22007"
+	datetimeFieldOverflow   = "22008"
+	stringDataTruncation    = "22001"
+)
+
+var (
+	// keyColumn extracts the column from details like
+	// Key (email)=(alice@example.com) already exists.
+	keyColumn = regexp.MustCompile(`^Key \(([^)]+)\)`)
+	// referencingTable extracts the table from details like
+	// Key (id)=(1) is still referenced from table "tasks".
+	referencingTable = regexp.MustCompile(`is still referenced from table "([^"]+)"`)
+)
+
+// Error is a database error translated into a client error. Its message
+// never contains SQL or the raw driver message.
+type Error struct {
+	Status  int
+	Code    string
+	Field   string
+	Message string
+	cause   error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Fields reports the field the error is about in the same shape as failed
+// request validation, it is nil when the field is unknown
+func (e *Error) Fields() validation.Errors {
+	if e.Field == "" {
+		return nil
+	}
+	return validation.Errors{{Field: e.Field, Message: e.Message}}
+}
+
+// Translate maps constraint violations and invalid input reported by
+// Postgres to a client error. It returns nil for any other error, which
+// should be treated as an internal failure.
+func Translate(err error) *Error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil
+	}
+
+	column := pqErr.Column
+	if m := keyColumn.FindStringSubmatch(pqErr.Detail); m != nil {
+		column = m[1]
+	}
+
+	e := &Error{Field: column, cause: err}
+
+	switch pqErr.Code {
+	case uniqueViolation:
+		e.Status, e.Code = http.StatusConflict, CodeAlreadyExists
+		e.Message = describe(column, "a record with this %s already exists", "the record already exists")
+	case foreignKeyViolation:
+		if m := referencingTable.FindStringSubmatch(pqErr.Detail); m != nil {
+			e.Status, e.Code, e.Field = http.StatusConflict, CodeStillReferenced, ""
+			e.Message = fmt.Sprintf("the record is still referenced by %s", m[1])
+			break
+		}
+		e.Status, e.Code = http.StatusUnprocessableEntity, CodeReferenceNotFound
+		e.Message = describe(column, "the referenced %s does not exist", "a referenced record does not exist")
+	case notNullViolation:
+		e.Status, e.Code = http.StatusUnprocessableEntity, CodeMissingValue
+		e.Message = describe(column, "%s must not be empty", "a required value is missing")
+	case checkViolation:
+		e.Status, e.Code = http.StatusUnprocessableEntity, CodeConstraintViolation
+		e.Message = describe(column, "%s violates a constraint", "the record violates a constraint")
+	case invalidTextRepresention, invalidDatetimeFormat, datetimeFieldOverflow:
+		e.Status, e.Code = http.StatusUnprocessableEntity, CodeInvalidValue
+		e.Message = describe(column, "%s has an invalid value", "a value is invalid")
+	case stringDataTruncation:
+		e.Status, e.Code = http.StatusUnprocessableEntity, CodeValueTooLong
+		e.Message = describe(column, "%s is too long", "a value is too long")
+	default:
+		return nil
+	}
+
+	return e
+}
+
+// IsUniqueViolation reports whether err is a violation of the named unique
+// constraint or index
+func IsUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == constraint
+}
+
+func describe(column, withColumn, without string) string {
+	if column == "" {
+		return without
+	}
+	return fmt.Sprintf(withColumn, column)
+}
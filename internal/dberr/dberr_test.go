@@ -0,0 +1,95 @@
+package dberr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"project-management-service/internal/validation"
+)
+
+func TestTranslateUniqueViolation(t *testing.T) {
+	err := fmt.Errorf("create user: %w", &pq.Error{
+		Code:       uniqueViolation,
+		Message:    `duplicate key value violates unique constraint "users_email_key"`,
+		Detail:     "Key (email)=(alice@example.com) already exists.",
+		Constraint: "users_email_key",
+	})
+
+	e := Translate(err)
+	require.NotNil(t, e)
+	assert.Equal(t, http.StatusConflict, e.Status)
+	assert.Equal(t, CodeAlreadyExists, e.Code)
+	assert.Equal(t, "a record with this email already exists", e.Error())
+	assert.Equal(t, validation.Errors{{Field: "email", Message: e.Message}}, e.Fields())
+	assert.NotContains(t, e.Error(), "alice@example.com")
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	err := fmt.Errorf("start timer: %w", &pq.Error{
+		Code:       uniqueViolation,
+		Constraint: "worklogs_running_timer_key",
+	})
+
+	assert.True(t, IsUniqueViolation(err, "worklogs_running_timer_key"))
+	assert.False(t, IsUniqueViolation(err, "users_email_key"))
+	assert.False(t, IsUniqueViolation(&pq.Error{Code: checkViolation, Constraint: "worklogs_running_timer_key"}, "worklogs_running_timer_key"))
+	assert.False(t, IsUniqueViolation(sql.ErrNoRows, "worklogs_running_timer_key"))
+}
+
+func TestTranslateForeignKeyViolation(t *testing.T) {
+	e := Translate(&pq.Error{
+		Code:   foreignKeyViolation,
+		Detail: `Key (project_id)=(42) is not present in table "projects".`,
+	})
+	require.NotNil(t, e)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.Status)
+	assert.Equal(t, CodeReferenceNotFound, e.Code)
+	assert.Equal(t, "the referenced project_id does not exist", e.Message)
+	assert.Equal(t, "project_id", e.Field)
+
+	e = Translate(&pq.Error{
+		Code:   foreignKeyViolation,
+		Detail: `Key (id)=(1) is still referenced from table "tasks".`,
+	})
+	require.NotNil(t, e)
+	assert.Equal(t, http.StatusConflict, e.Status)
+	assert.Equal(t, CodeStillReferenced, e.Code)
+	assert.Equal(t, "the record is still referenced by tasks", e.Message)
+	assert.Nil(t, e.Fields())
+}
+
+func TestTranslateInvalidValue(t *testing.T) {
+	e := Translate(&pq.Error{
+		Code:    invalidTextRepresention,
+		Message: `invalid input value for enum task_status: "done"`,
+	})
+	require.NotNil(t, e)
+	assert.Equal(t, http.StatusUnprocessableEntity, e.Status)
+	assert.Equal(t, CodeInvalidValue, e.Code)
+	assert.Equal(t, "a value is invalid", e.Message)
+
+	e = Translate(&pq.Error{Code: notNullViolation, Column: "title"})
+	require.NotNil(t, e)
+	assert.Equal(t, CodeMissingValue, e.Code)
+	assert.Equal(t, "title must not be empty", e.Message)
+
+	e = Translate(&pq.Error{Code: stringDataTruncation})
+	require.NotNil(t, e)
+	assert.Equal(t, CodeValueTooLong, e.Code)
+
+	var pqErr *pq.Error
+	assert.True(t, errors.As(e, &pqErr))
+}
+
+func TestTranslateIgnoresOtherErrors(t *testing.T) {
+	assert.Nil(t, Translate(sql.ErrNoRows))
+	assert.Nil(t, Translate(errors.New("connection refused")))
+	assert.Nil(t, Translate(&pq.Error{Code: "40001"}))
+}
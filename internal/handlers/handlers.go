@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/hellofresh/health-go/v5"
 	healthPg "github.com/hellofresh/health-go/v5/checks/postgres"
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -13,6 +14,9 @@ import (
 	"project-management-service/docs"
 	"project-management-service/internal/config"
 	"project-management-service/internal/handlers/http"
+	"project-management-service/pkg/server/response"
+	"project-management-service/pkg/storage"
+	"project-management-service/pkg/token"
 )
 
 type Dependencies struct {
@@ -57,19 +61,45 @@ func WithHTTPHandler() Configuration {
 		// Create the HTTP handler
 		h.HTTP = chi.NewRouter()
 
+		// Tag every request with an id and pick the error format
+		h.HTTP.Use(middleware.RequestID)
+		h.HTTP.Use(response.ProblemDetails(h.dependencies.Configs.ProblemJSON))
+
 		// Init swagger handler
 		docs.SwaggerInfo.BasePath = h.dependencies.Configs.BaseURL
 		h.HTTP.Get("/swagger/*", httpSwagger.WrapHandler)
 
+		// Init token maker for bearer authentication
+		tokenMaker, err := token.NewJWTMaker(h.dependencies.Configs.TokenSymmetricKey)
+		if err != nil {
+			return err
+		}
+
+		// Init the store keeping attachment contents
+		blobs, err := storage.NewBlobStore(h.dependencies.Configs.BlobStore, h.dependencies.Configs.BlobDir)
+		if err != nil {
+			return err
+		}
+
 		// Init service handlers
+		authHandler := http.NewAuthHandler(h.dependencies.DB, tokenMaker, h.dependencies.Configs.AccessTokenDuration)
 		userHandler := http.NewUserHandler(h.dependencies.DB)
 		projectHandler := http.NewProjectHandler(h.dependencies.DB)
-		taskHandler := http.NewTaskHandler(h.dependencies.DB)
+		taskHandler := http.NewTaskHandler(h.dependencies.DB, blobs)
+		reportHandler := http.NewReportHandler(h.dependencies.DB)
 
 		h.HTTP.Route("/", func(r chi.Router) {
-			r.Mount("/users", userHandler.Routes())
-			r.Mount("/projects", projectHandler.Routes())
-			r.Mount("/tasks", taskHandler.Routes())
+			r.Mount("/auth", authHandler.Routes())
+
+			// Every resource route requires a valid bearer token
+			r.Group(func(r chi.Router) {
+				r.Use(http.Authenticate(tokenMaker, h.dependencies.DB))
+
+				r.Mount("/users", userHandler.Routes())
+				r.Mount("/projects", projectHandler.Routes())
+				r.Mount("/tasks", taskHandler.Routes())
+				r.Mount("/reports", reportHandler.Routes())
+			})
 		})
 
 		// Setting up health checks
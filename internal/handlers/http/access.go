@@ -0,0 +1,33 @@
+package http
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"project-management-service/db/sqlc"
+)
+
+// projectMember returns the membership of the user in the project or nil
+// when the user does not belong to it
+func projectMember(ctx context.Context, q db.Querier, projectID, userID int64) (*db.ProjectMember, error) {
+	member, err := q.GetProjectMember(ctx, db.GetProjectMemberParams{
+		ProjectID: projectID,
+		UserID:    userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &member, nil
+}
+
+// viewerScope returns the parameters used to restrict task queries to the
+// projects the current user is a member of
+func viewerScope(r *http.Request) (viewerID int64, viewerIsAdmin bool) {
+	actor, _ := UserFromContext(r.Context())
+	return actor.ID, actor.Role == db.UserRoleAdmin
+}
@@ -0,0 +1,390 @@
+package http
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/log"
+	"project-management-service/pkg/server/response"
+	"project-management-service/pkg/storage"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// maxAttachmentSize bounds the size of an uploaded file
+	maxAttachmentSize = 32 << 20
+	// multipartOverhead leaves room for the boundaries and part headers
+	multipartOverhead = 1 << 20
+	// sniffLength is the number of bytes used to detect a content type
+	sniffLength = 512
+)
+
+var (
+	errNotMultipart     = errors.New("attachments must be uploaded as multipart/form-data")
+	errAttachmentTooBig = fmt.Errorf("attachments are limited to %d MB", maxAttachmentSize>>20)
+)
+
+// attachmentResponse is the metadata of a file attached to a task, the
+// checksum is the hex encoded SHA-256 of its content
+type attachmentResponse struct {
+	ID          int64     `json:"id"`
+	TaskID      int64     `json:"task_id"`
+	UploaderID  int64     `json:"uploader_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newAttachmentResponse(attachment db.Attachment) attachmentResponse {
+	return attachmentResponse{
+		ID:          attachment.ID,
+		TaskID:      attachment.TaskID,
+		UploaderID:  attachment.UploaderID,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Checksum:    attachment.Checksum,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+func newAttachmentResponses(attachments []db.Attachment) []attachmentResponse {
+	rsp := make([]attachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		rsp = append(rsp, newAttachmentResponse(attachment))
+	}
+	return rsp
+}
+
+// @Summary List the files attached to a task
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {array} attachmentResponse
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/attachments [get]
+func (h *TaskHandler) listAttachments(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	attachments, err := h.db.ListTaskAttachments(r.Context(), task.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newAttachmentResponses(attachments))
+}
+
+// @Summary Attach a file to a task
+// @Description Uploads the file sent in the "file" part of a multipart form, up to 32 MB. Without a content type in the part, it is detected from the content.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param file formData file true "File to attach"
+// @Success 200 {object} attachmentResponse
+// @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 413 {object} response.Object
+// @Failure 415 {object} response.Object
+// @Failure 422 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/attachments [post]
+func (h *TaskHandler) addAttachment(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentSize+multipartOverhead)
+
+	form, err := r.MultipartReader()
+	if err != nil {
+		response.UnsupportedMediaType(w, r, errNotMultipart)
+		return
+	}
+
+	part, err := filePart(form)
+	if err != nil {
+		uploadError(w, r, err)
+		return
+	}
+	defer part.Close()
+
+	filename := path.Base(strings.ReplaceAll(part.FileName(), `\`, "/"))
+
+	var v validation.Validator
+	v.Required("filename", strings.Trim(filename, "./"), 255)
+	if errs := v.Errors(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	content := bufio.NewReaderSize(part, sniffLength)
+	contentType := partContentType(part.Header.Get("Content-Type"), content)
+
+	key, err := blobKey(task.ID)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	checksum := sha256.New()
+	upload := &uploadReader{r: io.TeeReader(content, checksum)}
+
+	size, err := h.blobs.Put(r.Context(), key, io.LimitReader(upload, maxAttachmentSize+1))
+	if err != nil || size > maxAttachmentSize {
+		h.removeBlob(r, key)
+		switch {
+		case upload.err != nil:
+			uploadError(w, r, upload.err)
+		case err != nil:
+			log.LoggerFromContext(r.Context()).Error("could not store attachment", zap.Error(err))
+			response.InternalServerError(w, r, errors.New("the attachment could not be stored"))
+		default:
+			uploadError(w, r, errAttachmentTooBig)
+		}
+		return
+	}
+
+	actor, _ := UserFromContext(r.Context())
+
+	attachment, err := h.db.CreateAttachment(r.Context(), db.CreateAttachmentParams{
+		TaskID:      task.ID,
+		UploaderID:  actor.ID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    hex.EncodeToString(checksum.Sum(nil)),
+		StorageKey:  key,
+	})
+	if err != nil {
+		h.removeBlob(r, key)
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newAttachmentResponse(attachment))
+}
+
+// @Summary Get the metadata of a file attached to a task
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param attachmentID path int true "Attachment ID"
+// @Success 200 {object} attachmentResponse
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/attachments/{attachmentID} [get]
+func (h *TaskHandler) getAttachment(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	attachment, ok := h.attachment(w, r, task.ID)
+	if !ok {
+		return
+	}
+
+	response.OK(w, r, newAttachmentResponse(attachment))
+}
+
+// @Summary Download a file attached to a task
+// @Description Streams the file with its content type. Range requests are supported to resume downloads or fetch parts of the file, the ETag is the checksum of the content.
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "Task ID"
+// @Param attachmentID path int true "Attachment ID"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Success 200 {file} file
+// @Success 206 {file} file
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 416 {string} string
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/attachments/{attachmentID}/content [get]
+func (h *TaskHandler) downloadAttachment(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	attachment, ok := h.attachment(w, r, task.ID)
+	if !ok {
+		return
+	}
+
+	blob, err := h.blobs.Open(r.Context(), attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			log.LoggerFromContext(r.Context()).Error("attachment content is missing",
+				zap.Int64("attachment_id", attachment.ID), zap.Error(err))
+		}
+		response.InternalServerError(w, r, errors.New("the attachment content cannot be read"))
+		return
+	}
+	defer blob.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", `"`+attachment.Checksum+`"`)
+
+	// ServeContent answers Range, If-Range and conditional requests
+	http.ServeContent(w, r, attachment.Filename, attachment.CreatedAt, blob)
+}
+
+// @Summary Delete a file attached to a task
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param attachmentID path int true "Attachment ID"
+// @Success 204 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/attachments/{attachmentID} [delete]
+func (h *TaskHandler) deleteAttachment(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	attachment, ok := h.attachment(w, r, task.ID)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DeleteAttachment(r.Context(), db.DeleteAttachmentParams{TaskID: task.ID, ID: attachment.ID}); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	h.removeBlob(r, attachment.StorageKey)
+	response.NoContent(w, r)
+}
+
+// attachment loads the attachment named in the path, which must belong to
+// the task, writing the error response and returning false otherwise
+func (h *TaskHandler) attachment(w http.ResponseWriter, r *http.Request, taskID int64) (db.Attachment, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "attachmentID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return db.Attachment{}, false
+	}
+
+	attachment, err := h.db.GetAttachment(r.Context(), db.GetAttachmentParams{TaskID: taskID, ID: id})
+	if err != nil {
+		databaseError(w, r, err)
+		return db.Attachment{}, false
+	}
+
+	return attachment, true
+}
+
+// removeBlob deletes stored content that is no longer referenced. A failure
+// only leaves an orphaned blob behind, so it is logged and not reported.
+func (h *TaskHandler) removeBlob(r *http.Request, key string) {
+	if err := h.blobs.Delete(r.Context(), key); err != nil {
+		log.LoggerFromContext(r.Context()).Warn("could not delete attachment content",
+			zap.String("key", key), zap.Error(err))
+	}
+}
+
+// filePart returns the "file" part of the form, skipping the other fields
+func filePart(form *multipart.Reader) (*multipart.Part, error) {
+	for {
+		part, err := form.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" && part.FileName() != "" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// partContentType returns the declared content type of an uploaded file or,
+// when the client sent none or a generic one, the type detected from the
+// first bytes of the content
+func partContentType(declared string, content *bufio.Reader) string {
+	if mediaType, params, err := mime.ParseMediaType(declared); err == nil && mediaType != "application/octet-stream" {
+		return mime.FormatMediaType(mediaType, params)
+	}
+	head, _ := content.Peek(sniffLength)
+	return http.DetectContentType(head)
+}
+
+// blobKey returns a new unguessable storage key for a file of the task
+func blobKey(taskID int64) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(b)), nil
+}
+
+// uploadReader keeps the error met reading the request body, telling a
+// broken upload apart from a failure of the blob store
+type uploadReader struct {
+	r   io.Reader
+	err error
+}
+
+func (u *uploadReader) Read(p []byte) (int, error) {
+	n, err := u.r.Read(p)
+	if err != nil && err != io.EOF {
+		u.err = err
+	}
+	return n, err
+}
+
+// uploadError answers an error met while reading an upload: 413 over the
+// size limit, 422 without a file and 400 for a malformed body
+func uploadError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooBig *http.MaxBytesError
+	switch {
+	case errors.Is(err, errAttachmentTooBig) || errors.As(err, &tooBig):
+		response.RequestEntityTooLarge(w, r, errAttachmentTooBig)
+	case errors.Is(err, io.EOF):
+		invalidRequest(w, r, validation.Errors{{Field: "file", Message: "is required"}})
+	default:
+		response.BadRequest(w, r, err, nil)
+	}
+}
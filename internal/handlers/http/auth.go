@@ -0,0 +1,95 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"project-management-service/db/sqlc"
+	"project-management-service/pkg/server/response"
+	"project-management-service/pkg/token"
+	"project-management-service/util"
+)
+
+var errInvalidCredentials = errors.New("invalid email or password")
+
+type AuthHandler struct {
+	db                  *db.Queries
+	tokenMaker          token.Maker
+	accessTokenDuration time.Duration
+}
+
+func NewAuthHandler(conn *sql.DB, tokenMaker token.Maker, accessTokenDuration time.Duration) *AuthHandler {
+	return &AuthHandler{
+		db:                  db.New(conn),
+		tokenMaker:          tokenMaker,
+		accessTokenDuration: accessTokenDuration,
+	}
+}
+
+func (h *AuthHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/login", h.login)
+
+	return r
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	AccessToken          string       `json:"access_token"`
+	AccessTokenExpiresAt time.Time    `json:"access_token_expires_at"`
+	User                 userResponse `json:"user"`
+}
+
+// @Summary	Log in and receive an access token
+// @Tags		auth
+// @Accept		json
+// @Produce	json
+// @Param		request	body		loginRequest	true	"User credentials"
+// @Success	200		{object}	loginResponse
+// @Failure	400		{object}	response.Object
+// @Failure	401		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Router		/auth/login [post]
+func (h *AuthHandler) login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	user, err := h.db.GetUserByEmail(r.Context(), req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			response.Unauthorized(w, r, errInvalidCredentials)
+		} else {
+			response.InternalServerError(w, r, err)
+		}
+		return
+	}
+
+	if user.HashedPassword == "" || util.CheckPassword(req.Password, user.HashedPassword) != nil {
+		response.Unauthorized(w, r, errInvalidCredentials)
+		return
+	}
+
+	accessToken, payload, err := h.tokenMaker.CreateToken(user.ID, h.accessTokenDuration)
+	if err != nil {
+		response.InternalServerError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, loginResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: payload.ExpiresAt.Time,
+		User:                 newUserResponse(user),
+	})
+}
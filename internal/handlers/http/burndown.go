@@ -0,0 +1,125 @@
+package http
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/report"
+	"project-management-service/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// burndownResponse is the daily series of a burndown or burnup chart over
+// the days from and to
+type burndownResponse struct {
+	From   string         `json:"from" example:"2024-07-01"`
+	To     string         `json:"to" example:"2024-07-14"`
+	Points []report.Point `json:"points"`
+}
+
+// @Summary	Get the burndown of a project
+// @Description	Returns for every day the tasks of the project, how many of them were done at the end of the day and what remained, as counts and as sums of estimates. The series covers the project dates up to today unless from and to are given, at most 366 days.
+// @Tags		reports
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int		true	"Project ID"
+// @Param		from	query		string	false	"First day, e.g. 2024-07-01"
+// @Param		to		query		string	false	"Last day, e.g. 2024-07-31"
+// @Success	200		{object}	burndownResponse
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/burndown [get]
+func (h *ProjectHandler) projectBurndown(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	project, ok := h.authorizeRead(w, r, id)
+	if !ok {
+		return
+	}
+
+	h.burndown(w, r, project.ID, sql.NullInt64{}, project.StartDate, project.EndDate, time.Now())
+}
+
+// @Summary	Get the burndown of a sprint
+// @Description	Returns for every day the tasks now in the sprint, how many of them were done at the end of the day and what remained, as counts and as sums of estimates. The series covers the sprint dates up to today, or the day it was closed, unless from and to are given.
+// @Tags		reports
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int		true	"Project ID"
+// @Param		sprintID	path		int		true	"Sprint ID"
+// @Param		from		query		string	false	"First day, e.g. 2024-07-01"
+// @Param		to			query		string	false	"Last day, e.g. 2024-07-14"
+// @Success	200			{object}	burndownResponse
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints/{sprintID}/burndown [get]
+func (h *ProjectHandler) sprintBurndown(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
+		return
+	}
+
+	s, ok := h.sprint(w, r, id)
+	if !ok {
+		return
+	}
+
+	today := time.Now()
+	if s.ClosedAt.Valid {
+		today = s.ClosedAt.Time
+	}
+
+	h.burndown(w, r, id, sql.NullInt64{Int64: s.ID, Valid: true}, s.StartDate, s.EndDate, today)
+}
+
+// burndown answers with the series of the project, or of one of its sprints,
+// over the requested days or the default period between start and end
+func (h *ProjectHandler) burndown(w http.ResponseWriter, r *http.Request, projectID int64, sprintID sql.NullInt64, start, end, today time.Time) {
+	from, to, err := timeRange(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	first, last, err := report.Period(start, end, today, from, to)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	rows, err := h.db.GetBurndown(r.Context(), db.GetBurndownParams{
+		FromDate:  first,
+		ToDate:    last,
+		ProjectID: projectID,
+		SprintID:  sprintID,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, burndownResponse{
+		From:   first.Format(dateLayout),
+		To:     last.Format(dateLayout),
+		Points: report.Burndown(rows),
+	})
+}
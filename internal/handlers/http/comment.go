@@ -0,0 +1,379 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/policy"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// commentBodyMaxLength bounds the text of a single comment
+const commentBodyMaxLength = 10000
+
+// commentResponse is a comment with the replies it received, replies are
+// ordered from the oldest to the newest. UpdatedAt is null until the comment
+// is edited, EditedAt lists the time of every edit, oldest first.
+type commentResponse struct {
+	ID         int64              `json:"id"`
+	TaskID     int64              `json:"task_id"`
+	AuthorID   int64              `json:"author_id"`
+	AuthorName string             `json:"author_name,omitempty"`
+	ParentID   *int64             `json:"parent_id"`
+	Body       string             `json:"body"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  *time.Time         `json:"updated_at"`
+	EditedAt   []time.Time        `json:"edited_at"`
+	Replies    []*commentResponse `json:"replies,omitempty"`
+}
+
+func newCommentResponse(comment db.Comment, edits []time.Time) *commentResponse {
+	if edits == nil {
+		edits = []time.Time{}
+	}
+
+	rsp := &commentResponse{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		EditedAt:  edits,
+	}
+	if comment.ParentID.Valid {
+		rsp.ParentID = &comment.ParentID.Int64
+	}
+	if comment.UpdatedAt.Valid {
+		rsp.UpdatedAt = &comment.UpdatedAt.Time
+	}
+	return rsp
+}
+
+// threadComments nests the comments of a task under the comment they reply
+// to and returns the top level ones. Comments come oldest first, so a parent
+// is always seen before its replies.
+func threadComments(rows []db.ListTaskCommentsRow, edits []db.CommentEdit) []*commentResponse {
+	threads := []*commentResponse{}
+	byID := make(map[int64]*commentResponse, len(rows))
+
+	editedAt := make(map[int64][]time.Time)
+	for _, e := range edits {
+		editedAt[e.CommentID] = append(editedAt[e.CommentID], e.EditedAt)
+	}
+
+	for _, row := range rows {
+		c := newCommentResponse(db.Comment{
+			ID:        row.ID,
+			TaskID:    row.TaskID,
+			AuthorID:  row.AuthorID,
+			ParentID:  row.ParentID,
+			Body:      row.Body,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		}, editedAt[row.ID])
+		c.AuthorName = row.AuthorName
+		byID[c.ID] = c
+
+		if parent, ok := byID[row.ParentID.Int64]; row.ParentID.Valid && ok {
+			parent.Replies = append(parent.Replies, c)
+		} else {
+			threads = append(threads, c)
+		}
+	}
+
+	return threads
+}
+
+// commentRequest holds the text of a comment and, for a reply, the comment
+// of the same task it answers
+type commentRequest struct {
+	Body     string `json:"body"`
+	ParentID int64  `json:"parent_id"`
+}
+
+func (req commentRequest) validate() validation.Errors {
+	var v validation.Validator
+	v.Required("body", req.Body, commentBodyMaxLength)
+	v.Check(req.ParentID >= 0, "parent_id", "must be a positive id")
+	return v.Errors()
+}
+
+// @Summary List the comments of a task
+// @Description Comments are threaded: replies are nested under the comment they answer, oldest first.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {array} commentResponse
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/comments [get]
+func (h *TaskHandler) listComments(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	rows, err := h.db.ListTaskComments(r.Context(), task.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	edits, err := h.db.ListTaskCommentEdits(r.Context(), task.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, threadComments(rows, edits))
+}
+
+// @Summary Comment on a task or reply to a comment
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body commentRequest true "Comment"
+// @Success 200 {object} commentResponse
+// @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 422 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/comments [post]
+func (h *TaskHandler) addComment(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok {
+		return
+	}
+
+	var req commentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if errs := req.validate(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	if req.ParentID != 0 {
+		_, err := h.db.GetComment(r.Context(), db.GetCommentParams{TaskID: task.ID, ID: req.ParentID})
+		if errors.Is(err, sql.ErrNoRows) {
+			invalidRequest(w, r, validation.Errors{{Field: "parent_id", Message: "must be a comment of the same task"}})
+			return
+		}
+		if err != nil {
+			databaseError(w, r, err)
+			return
+		}
+	}
+
+	actor, _ := UserFromContext(r.Context())
+
+	comment, err := h.db.CreateComment(r.Context(), db.CreateCommentParams{
+		TaskID:   task.ID,
+		AuthorID: actor.ID,
+		ParentID: sql.NullInt64{Int64: req.ParentID, Valid: req.ParentID != 0},
+		Body:     req.Body,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newCommentResponse(comment, nil))
+}
+
+// @Summary Get a comment of a task
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param commentID path int true "Comment ID"
+// @Success 200 {object} commentResponse
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/comments/{commentID} [get]
+func (h *TaskHandler) getComment(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	comment, ok := h.comment(w, r, task.ID)
+	if !ok {
+		return
+	}
+
+	edits, err := h.db.ListCommentEdits(r.Context(), comment.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newCommentResponse(comment, edits))
+}
+
+// @Summary Edit a comment
+// @Description Only the author can edit a comment. The time of the latest edit is recorded in updated_at, edited_at lists the times of all edits, oldest first.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param commentID path int true "Comment ID"
+// @Param request body commentRequest true "New text, the parent_id is ignored"
+// @Success 200 {object} commentResponse
+// @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 422 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/comments/{commentID} [put]
+func (h *TaskHandler) updateComment(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok {
+		return
+	}
+
+	var req commentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	var v validation.Validator
+	v.Required("body", req.Body, commentBodyMaxLength)
+	if errs := v.Errors(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	current, ok := h.comment(w, r, task.ID)
+	if !ok {
+		return
+	}
+
+	actor, _ := UserFromContext(r.Context())
+	if err := policy.CanEditComment(actor, current); err != nil {
+		response.Forbidden(w, r, err)
+		return
+	}
+
+	updated, err := h.db.UpdateCommentTx(r.Context(), db.UpdateCommentParams{
+		TaskID: task.ID,
+		ID:     current.ID,
+		Body:   req.Body,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newCommentResponse(updated.Comment, updated.Edits))
+}
+
+// @Summary Delete a comment
+// @Description Deleting a comment also deletes the replies to it. The author, the project manager and admins can delete a comment.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param commentID path int true "Comment ID"
+// @Success 204 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/comments/{commentID} [delete]
+func (h *TaskHandler) deleteComment(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	comment, ok := h.comment(w, r, task.ID)
+	if !ok {
+		return
+	}
+
+	actor, _ := UserFromContext(r.Context())
+
+	member, err := projectMember(r.Context(), h.db, task.ProjectID, actor.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if err := policy.CanDeleteComment(actor, member, comment); err != nil {
+		response.Forbidden(w, r, err)
+		return
+	}
+
+	if err := h.db.DeleteComment(r.Context(), db.DeleteCommentParams{TaskID: task.ID, ID: comment.ID}); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// pathTask loads the task named in the path, writing the error
+// response and returning false when it cannot be read
+func (h *TaskHandler) pathTask(w http.ResponseWriter, r *http.Request) (db.Task, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return db.Task{}, false
+	}
+
+	task, err := h.db.GetTask(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return db.Task{}, false
+	}
+
+	return task, true
+}
+
+// comment loads the comment named in the path, which must belong to the
+// task, writing the error response and returning false otherwise
+func (h *TaskHandler) comment(w http.ResponseWriter, r *http.Request, taskID int64) (db.Comment, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "commentID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return db.Comment{}, false
+	}
+
+	comment, err := h.db.GetComment(r.Context(), db.GetCommentParams{TaskID: taskID, ID: id})
+	if err != nil {
+		databaseError(w, r, err)
+		return db.Comment{}, false
+	}
+
+	return comment, true
+}
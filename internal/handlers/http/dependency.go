@@ -0,0 +1,190 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var errDependencyNotFound = errors.New("the task does not depend on this blocker")
+
+// dependenciesResponse lists the tasks a task waits on and the tasks waiting
+// on it. Blocked is set while any of the blockers is not done.
+type dependenciesResponse struct {
+	Blocked   bool      `json:"blocked"`
+	BlockedBy []db.Task `json:"blocked_by"`
+	Blocks    []db.Task `json:"blocks"`
+}
+
+// dependencyRequest names a task that has to be done before the task
+type dependencyRequest struct {
+	BlockerID int64 `json:"blocker_id"`
+}
+
+// @Summary List the dependencies of a task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} dependenciesResponse
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/dependencies [get]
+func (h *TaskHandler) listDependencies(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	h.dependencies(w, r, task.ID)
+}
+
+// @Summary Make a task wait on another task
+// @Description The blocker must belong to the same project. A dependency that would make a task wait on itself, directly or through other tasks, is refused.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body dependencyRequest true "Blocking task"
+// @Success 200 {object} dependenciesResponse
+// @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 422 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/dependencies [post]
+func (h *TaskHandler) addDependency(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok {
+		return
+	}
+
+	var req dependencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	var v validation.Validator
+	v.ID("blocker_id", req.BlockerID)
+	v.Check(req.BlockerID != task.ID, "blocker_id", "a task cannot block itself")
+	if errs := v.Errors(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	blocker, err := h.db.GetTask(r.Context(), req.BlockerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		invalidRequest(w, r, validation.Errors{{Field: "blocker_id", Message: "the blocking task does not exist"}})
+		return
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if blocker.ProjectID != task.ProjectID {
+		invalidRequest(w, r, validation.Errors{{Field: "blocker_id", Message: "must be a task of the same project"}})
+		return
+	}
+
+	err = h.db.AddTaskDependencyTx(r.Context(), db.AddTaskDependencyTxParams{
+		ProjectID: task.ProjectID,
+		BlockerID: blocker.ID,
+		BlockedID: task.ID,
+	})
+	if errors.Is(err, db.ErrDependencyCycle) {
+		invalidRequest(w, r, validation.Errors{{Field: "blocker_id", Message: err.Error()}})
+		return
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	h.dependencies(w, r, task.ID)
+}
+
+// @Summary Stop a task from waiting on another task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param blockerID path int true "Blocking task ID"
+// @Success 204 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/dependencies/{blockerID} [delete]
+func (h *TaskHandler) removeDependency(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok {
+		return
+	}
+
+	blockerID, err := strconv.ParseInt(chi.URLParam(r, "blockerID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	removed, err := h.db.RemoveTaskDependency(r.Context(), db.RemoveTaskDependencyParams{BlockerID: blockerID, BlockedID: task.ID})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if removed == 0 {
+		response.NotFound(w, r, errDependencyNotFound)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// dependencies answers with the dependencies of the task as they are now
+func (h *TaskHandler) dependencies(w http.ResponseWriter, r *http.Request, taskID int64) {
+	task, err := h.db.GetTask(r.Context(), taskID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	blockers, err := h.db.ListTaskBlockers(r.Context(), taskID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	blocked, err := h.db.ListBlockedTasks(r.Context(), taskID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, dependenciesResponse{
+		Blocked:   task.Blocked,
+		BlockedBy: blockers,
+		Blocks:    blocked,
+	})
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"project-management-service/internal/dberr"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/log"
+	"project-management-service/pkg/server/response"
+)
+
+var errDatabase = errors.New("internal database error")
+
+// invalidRequest answers 422 with the field errors of a request body
+func invalidRequest(w http.ResponseWriter, r *http.Request, errs validation.Errors) {
+	response.UnprocessableEntity(w, r, errs, validation.Code, errs)
+}
+
+// databaseError answers with the status matching an error returned by a
+// query: 404 for a missing row, 409 or 422 for constraint violations and
+// 500 otherwise, without exposing the driver message to the client
+func databaseError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		response.NotFound(w, r, err)
+		return
+	}
+
+	if e := dberr.Translate(err); e != nil {
+		if e.Status == http.StatusConflict {
+			response.Conflict(w, r, e, e.Code)
+		} else {
+			response.UnprocessableEntity(w, r, e, e.Code, e.Fields())
+		}
+		return
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		log.LoggerFromContext(r.Context()).Error("database query failed", zap.Error(err))
+		response.InternalServerError(w, r, errDatabase)
+		return
+	}
+
+	response.InternalServerError(w, r, err)
+}
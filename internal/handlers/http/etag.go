@@ -0,0 +1,59 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"project-management-service/pkg/server/response"
+)
+
+var errStaleVersion = errors.New("the resource has been modified, fetch it again and retry with its current ETag")
+
+// etag formats the version of a record as a strong entity tag
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// setETag sends the version of the returned record as its ETag
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", etag(version))
+}
+
+// ifMatch checks the If-Match header of an update against the current
+// version of the record. A request without the header or with * matches any
+// version. Weak tags are compared by their value, proxies that compress the
+// response may have weakened the ETag the client got. It writes 412 and
+// returns false when none of the listed tags matches.
+func ifMatch(w http.ResponseWriter, r *http.Request, version int64) bool {
+	values := r.Header.Values("If-Match")
+	if len(values) == 0 {
+		return true
+	}
+
+	current := etag(version)
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == current {
+				return true
+			}
+		}
+	}
+
+	response.PreconditionFailed(w, r, errStaleVersion)
+	return false
+}
+
+// updateError answers an error of an update guarded by the record version.
+// The record existed when it was read, so a missing row means another
+// request changed it in between.
+func updateError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		response.PreconditionFailed(w, r, errStaleVersion)
+		return
+	}
+	databaseError(w, r, err)
+}
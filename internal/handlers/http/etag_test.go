@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatch(t *testing.T) {
+	for header, matches := range map[string]bool{
+		"":           true,
+		`"3"`:        true,
+		`W/"3"`:      true,
+		"*":          true,
+		`"1", W/"3"`: true,
+		`"2"`:        false,
+		`W/"2", "4"`: false,
+		`3`:          false,
+		`W/"3`:       false,
+	} {
+		r := httptest.NewRequest("PUT", "/", nil)
+		if header != "" {
+			r.Header.Set("If-Match", header)
+		}
+		w := httptest.NewRecorder()
+
+		assert.Equal(t, matches, ifMatch(w, r, 3), header)
+		if !matches {
+			assert.Equal(t, http.StatusPreconditionFailed, w.Code, header)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// defaultLabelColor is given to labels created without a color
+const defaultLabelColor = "#808080"
+
+var (
+	labelColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+	errLabelNotAttached = errors.New("the task does not carry this label")
+)
+
+// labelRequest holds the name of a label and its color as #rrggbb
+type labelRequest struct {
+	Name  string `json:"name"`
+	Color string `json:"color" example:"#d73a4a"`
+}
+
+func (req labelRequest) validate() validation.Errors {
+	var v validation.Validator
+	v.Required("name", req.Name, 50)
+	v.Check(req.Color == "" || labelColor.MatchString(req.Color), "color", "must be a hex color such as #d73a4a")
+	return v.Errors()
+}
+
+func (req labelRequest) color() string {
+	if req.Color == "" {
+		return defaultLabelColor
+	}
+	return req.Color
+}
+
+// attachLabelRequest names a label of the task's project
+type attachLabelRequest struct {
+	LabelID int64 `json:"label_id"`
+}
+
+// @Summary List the labels of a task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {array} db.Label
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/labels [get]
+func (h *TaskHandler) listTaskLabels(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	labels, err := h.db.ListTaskLabels(r.Context(), task.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, labels)
+}
+
+// @Summary Put a label on a task
+// @Description The label must belong to the project of the task. Attaching a label twice has no effect.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body attachLabelRequest true "Label"
+// @Success 200 {array} db.Label
+// @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 422 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/labels [post]
+func (h *TaskHandler) attachLabel(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok {
+		return
+	}
+
+	var req attachLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	var v validation.Validator
+	v.ID("label_id", req.LabelID)
+	if errs := v.Errors(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	_, err := h.db.GetLabel(r.Context(), db.GetLabelParams{ProjectID: task.ProjectID, ID: req.LabelID})
+	if errors.Is(err, sql.ErrNoRows) {
+		invalidRequest(w, r, validation.Errors{{Field: "label_id", Message: "must be a label of the task's project"}})
+		return
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if err := h.db.AttachLabel(r.Context(), db.AttachLabelParams{TaskID: task.ID, LabelID: req.LabelID, ProjectID: task.ProjectID}); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	h.listTaskLabels(w, r)
+}
+
+// @Summary Remove a label from a task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param labelID path int true "Label ID"
+// @Success 204 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/labels/{labelID} [delete]
+func (h *TaskHandler) detachLabel(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok {
+		return
+	}
+
+	labelID, err := strconv.ParseInt(chi.URLParam(r, "labelID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	detached, err := h.db.DetachLabel(r.Context(), db.DetachLabelParams{TaskID: task.ID, LabelID: labelID})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if detached == 0 {
+		response.NotFound(w, r, errLabelNotAttached)
+		return
+	}
+
+	response.NoContent(w, r)
+}
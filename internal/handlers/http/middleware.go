@@ -0,0 +1,78 @@
+package http
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/pkg/server/response"
+	"project-management-service/pkg/token"
+)
+
+const (
+	authorizationHeaderKey  = "Authorization"
+	authorizationTypeBearer = "bearer"
+)
+
+type authUser struct{}
+
+// ContextWithUser adds the authenticated user to context
+func ContextWithUser(ctx context.Context, u db.User) context.Context {
+	return context.WithValue(ctx, authUser{}, u)
+}
+
+// UserFromContext returns the authenticated user from context
+func UserFromContext(ctx context.Context) (db.User, bool) {
+	u, ok := ctx.Value(authUser{}).(db.User)
+	return u, ok
+}
+
+// Authenticate rejects requests without a valid bearer token and puts
+// the token owner into the request context
+func Authenticate(maker token.Maker, conn *sql.DB) func(http.Handler) http.Handler {
+	queries := db.New(conn)
+
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			authorizationHeader := r.Header.Get(authorizationHeaderKey)
+			if authorizationHeader == "" {
+				response.Unauthorized(w, r, errors.New("authorization header is not provided"))
+				return
+			}
+
+			fields := strings.Fields(authorizationHeader)
+			if len(fields) != 2 {
+				response.Unauthorized(w, r, errors.New("invalid authorization header format"))
+				return
+			}
+
+			if strings.ToLower(fields[0]) != authorizationTypeBearer {
+				response.Unauthorized(w, r, errors.New("unsupported authorization type "+fields[0]))
+				return
+			}
+
+			payload, err := maker.VerifyToken(fields[1])
+			if err != nil {
+				response.Unauthorized(w, r, err)
+				return
+			}
+
+			user, err := queries.GetUser(r.Context(), payload.UserID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					response.Unauthorized(w, r, token.ErrInvalidToken)
+				} else {
+					response.InternalServerError(w, r, err)
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithUser(r.Context(), user)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,344 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/report"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// milestoneRequest holds the details of a milestone, its due date must fall
+// within the project dates
+type milestoneRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	DueDate     string `json:"due_date" example:"2024-09-30"`
+}
+
+// validate checks the milestone against the dates of its project and returns
+// its due date
+func (req milestoneRequest) validate(project db.Project) (time.Time, validation.Errors) {
+	var v validation.Validator
+	v.Required("title", req.Title, 100)
+	dueDate := v.Date("due_date", req.DueDate)
+	if !dueDate.IsZero() {
+		due := report.Day(dueDate)
+		v.Check(!due.Before(report.Day(project.StartDate)) && !due.After(report.Day(project.EndDate)), "due_date",
+			"must be within the project dates, "+project.StartDate.Format(dateLayout)+" to "+project.EndDate.Format(dateLayout))
+	}
+	return dueDate, v.Errors()
+}
+
+// milestoneResponse is a milestone with the number of its tasks per status,
+// how many of them are done and the completion percent
+type milestoneResponse struct {
+	db.Milestone
+	Tasks     int64         `json:"tasks"`
+	Completed int64         `json:"completed"`
+	Percent   int32         `json:"percent"`
+	Statuses  []statusCount `json:"statuses"`
+}
+
+// milestoneStatuses groups the status counts of a project by milestone
+func milestoneStatuses(rows []db.CountMilestoneTasksByStatusRow) map[int64][]statusCount {
+	statuses := make(map[int64][]statusCount)
+	for _, row := range rows {
+		statuses[row.MilestoneID] = append(statuses[row.MilestoneID], statusCount{
+			Status:   row.Status,
+			Category: row.Category,
+			Tasks:    row.Tasks,
+		})
+	}
+	return statuses
+}
+
+func newMilestoneResponse(m db.Milestone, tasks, completed int64, percent int32, statuses []statusCount) milestoneResponse {
+	if statuses == nil {
+		statuses = []statusCount{}
+	}
+
+	return milestoneResponse{
+		Milestone: m,
+		Tasks:     tasks,
+		Completed: completed,
+		Percent:   percent,
+		Statuses:  statuses,
+	}
+}
+
+// @Summary	List the milestones of a project
+// @Description	Every milestone carries the number of its tasks per status and the percent of them that are done, ordered by due date.
+// @Tags		milestones
+// @Accept		json
+// @Produce	json
+// @Param		id	path		int	true	"Project ID"
+// @Success	200	{array}		milestoneResponse
+// @Failure	400	{object}	response.Object
+// @Failure	403	{object}	response.Object
+// @Failure	404	{object}	response.Object
+// @Failure	500	{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/milestones [get]
+func (h *ProjectHandler) listMilestones(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
+		return
+	}
+
+	rows, err := h.db.ListMilestones(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	counts, err := h.db.CountMilestoneTasksByStatus(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	statuses := milestoneStatuses(counts)
+	milestones := make([]milestoneResponse, len(rows))
+	for i, row := range rows {
+		milestones[i] = newMilestoneResponse(row.Milestone, row.Tasks, row.Completed, row.Percent, statuses[row.Milestone.ID])
+	}
+
+	response.OK(w, r, milestones)
+}
+
+// @Summary	Add a milestone to a project
+// @Tags		milestones
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int					true	"Project ID"
+// @Param		request	body		milestoneRequest	true	"Milestone details"
+// @Success	200		{object}	milestoneResponse
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	422		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/milestones [post]
+func (h *ProjectHandler) addMilestone(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	project, ok := h.authorizeManage(w, r, id)
+	if !ok {
+		return
+	}
+
+	var req milestoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	dueDate, errs := req.validate(project)
+	if len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	created, err := h.db.CreateMilestone(r.Context(), db.CreateMilestoneParams{
+		ProjectID:   id,
+		Title:       req.Title,
+		Description: req.Description,
+		DueDate:     dueDate,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newMilestoneResponse(created, 0, 0, 0, nil))
+}
+
+// @Summary	Get a milestone of a project
+// @Tags		milestones
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int	true	"Project ID"
+// @Param		milestoneID	path		int	true	"Milestone ID"
+// @Success	200			{object}	milestoneResponse
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/milestones/{milestoneID} [get]
+func (h *ProjectHandler) getMilestone(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	milestoneID, err := strconv.ParseInt(chi.URLParam(r, "milestoneID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
+		return
+	}
+
+	h.milestoneSummary(w, r, id, milestoneID)
+}
+
+// @Summary	Change the details of a milestone
+// @Tags		milestones
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int					true	"Project ID"
+// @Param		milestoneID	path		int					true	"Milestone ID"
+// @Param		request		body		milestoneRequest	true	"Milestone details"
+// @Success	200			{object}	milestoneResponse
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	422			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/milestones/{milestoneID} [put]
+func (h *ProjectHandler) updateMilestone(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	milestoneID, err := strconv.ParseInt(chi.URLParam(r, "milestoneID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	project, ok := h.authorizeManage(w, r, id)
+	if !ok {
+		return
+	}
+
+	var req milestoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	dueDate, errs := req.validate(project)
+	if len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if _, err := h.db.UpdateMilestone(r.Context(), db.UpdateMilestoneParams{
+		ProjectID:   id,
+		ID:          milestoneID,
+		Title:       req.Title,
+		Description: req.Description,
+		DueDate:     dueDate,
+	}); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	h.milestoneSummary(w, r, id, milestoneID)
+}
+
+// @Summary	Delete a milestone of a project
+// @Description	Its tasks are kept and no longer count towards a milestone.
+// @Tags		milestones
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int	true	"Project ID"
+// @Param		milestoneID	path		int	true	"Milestone ID"
+// @Success	204			{object}	response.Object
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/milestones/{milestoneID} [delete]
+func (h *ProjectHandler) deleteMilestone(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	milestoneID, err := strconv.ParseInt(chi.URLParam(r, "milestoneID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeManage(w, r, id); !ok {
+		return
+	}
+
+	if _, err := h.db.GetMilestone(r.Context(), db.GetMilestoneParams{ProjectID: id, ID: milestoneID}); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if err := h.db.DeleteMilestone(r.Context(), db.DeleteMilestoneParams{ProjectID: id, ID: milestoneID}); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// milestoneSummary answers with the milestone and the counts of its tasks as
+// they are now
+func (h *ProjectHandler) milestoneSummary(w http.ResponseWriter, r *http.Request, projectID, milestoneID int64) {
+	row, err := h.db.GetMilestoneSummary(r.Context(), db.GetMilestoneSummaryParams{ProjectID: projectID, ID: milestoneID})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	counts, err := h.db.CountMilestoneTasksByStatus(r.Context(), projectID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	statuses := milestoneStatuses(counts)
+	response.OK(w, r, newMilestoneResponse(row.Milestone, row.Tasks, row.Completed, row.Percent, statuses[milestoneID]))
+}
+
+// checkMilestone checks that the milestone belongs to the project of the
+// task, writing the error response and returning false otherwise
+func (h *TaskHandler) checkMilestone(w http.ResponseWriter, r *http.Request, projectID, milestoneID int64) bool {
+	_, err := h.db.GetMilestone(r.Context(), db.GetMilestoneParams{ProjectID: projectID, ID: milestoneID})
+	if errors.Is(err, sql.ErrNoRows) {
+		invalidRequest(w, r, validation.Errors{{Field: "milestone_id", Message: "must be a milestone of the project"}})
+		return false
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/server/response"
+)
+
+// contentTypeMergePatch is the media type of JSON Merge Patch documents
+const contentTypeMergePatch = "application/merge-patch+json"
+
+// optional is a member of a JSON Merge Patch (RFC 7396) document. Set reports
+// whether the member was present at all and Null whether it was given as
+// null, which removes the value. Absent members leave the field unchanged.
+type optional[T any] struct {
+	Value T
+	Set   bool
+	Null  bool
+}
+
+func (o *optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// present reports whether the patch gives the field a new value
+func (o optional[T]) present() bool {
+	return o.Set && !o.Null
+}
+
+// or returns the new value of the field, or current when it is not patched
+func (o optional[T]) or(current T) T {
+	if o.present() {
+		return o.Value
+	}
+	return current
+}
+
+// required rejects removing a field that cannot be empty
+func (o optional[T]) required(v *validation.Validator, field string) {
+	v.Check(!o.Null, field, "must not be null")
+}
+
+func nullString(o optional[string]) sql.NullString {
+	return sql.NullString{String: o.Value, Valid: o.present()}
+}
+
+func nullInt64(o optional[int64]) sql.NullInt64 {
+	return sql.NullInt64{Int64: o.Value, Valid: o.present()}
+}
+
+// decodePatch reads a merge patch document from the request body, which may
+// be sent as application/merge-patch+json or application/json. It writes the
+// error response and returns false when the body cannot be decoded.
+func decodePatch(w http.ResponseWriter, r *http.Request, dst any) bool {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != contentTypeMergePatch && mediaType != "application/json") {
+			response.UnsupportedMediaType(w, r, fmt.Errorf("unsupported content type %q, expected %s", ct, contentTypeMergePatch))
+			return false
+		}
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		response.BadRequest(w, r, err, nil)
+		return false
+	}
+
+	return true
+}
@@ -10,16 +10,28 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"project-management-service/db/sqlc"
+	"project-management-service/internal/policy"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/pagination"
 	"project-management-service/pkg/server/response"
 )
 
+// codeManagerMembership is the error code of removing the project manager
+// from the project
+const codeManagerMembership = "manager_membership"
+
+var (
+	errManagerMembership = errors.New("the project manager must stay a member with the manager role")
+	errNotMember         = errors.New("the user is not a member of the project")
+)
+
 type ProjectHandler struct {
-	db *db.Queries
+	db *db.Store
 }
 
 func NewProjectHandler(conn *sql.DB) *ProjectHandler {
 	return &ProjectHandler{
-		db: db.New(conn),
+		db: db.NewStore(conn),
 	}
 }
 
@@ -33,8 +45,54 @@ func (h *ProjectHandler) Routes() chi.Router {
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
+		r.Patch("/", h.patch)
 		r.Delete("/", h.delete)
 		r.Get("/tasks", h.getTasks)
+		r.Get("/time", h.projectTime)
+		r.Get("/summary", h.summary)
+		r.Get("/burndown", h.projectBurndown)
+
+		r.Route("/members", func(r chi.Router) {
+			r.Get("/", h.listMembers)
+			r.Post("/", h.addMember)
+			r.Delete("/{userID}", h.removeMember)
+		})
+
+		r.Route("/statuses", func(r chi.Router) {
+			r.Get("/", h.listStatuses)
+			r.Post("/", h.addStatus)
+			r.Put("/{statusID}", h.updateStatus)
+			r.Delete("/{statusID}", h.deleteStatus)
+		})
+
+		r.Route("/labels", func(r chi.Router) {
+			r.Get("/", h.listLabels)
+			r.Post("/", h.addLabel)
+			r.Put("/{labelID}", h.updateLabel)
+			r.Delete("/{labelID}", h.deleteLabel)
+		})
+
+		r.Route("/milestones", func(r chi.Router) {
+			r.Get("/", h.listMilestones)
+			r.Post("/", h.addMilestone)
+			r.Get("/{milestoneID}", h.getMilestone)
+			r.Put("/{milestoneID}", h.updateMilestone)
+			r.Delete("/{milestoneID}", h.deleteMilestone)
+		})
+
+		r.Route("/sprints", func(r chi.Router) {
+			r.Get("/", h.listSprints)
+			r.Post("/", h.addSprint)
+			r.Get("/{sprintID}", h.getSprint)
+			r.Put("/{sprintID}", h.updateSprint)
+			r.Delete("/{sprintID}", h.deleteSprint)
+			r.Post("/{sprintID}/start", h.startSprint)
+			r.Post("/{sprintID}/close", h.closeSprint)
+			r.Get("/{sprintID}/tasks", h.listSprintTasks)
+			r.Post("/{sprintID}/tasks", h.addSprintTask)
+			r.Delete("/{sprintID}/tasks/{taskID}", h.removeSprintTask)
+			r.Get("/{sprintID}/burndown", h.sprintBurndown)
+		})
 	})
 
 	return r
@@ -48,20 +106,62 @@ type createProjectRequest struct {
 	StartDate   string `json:"start_date"`
 }
 
+// params validates the request and converts it into the query parameters
+func (req createProjectRequest) params() (db.CreateProjectParams, validation.Errors) {
+	var v validation.Validator
+	startDate := v.Date("start_date", req.StartDate)
+	endDate := v.Date("end_date", req.EndDate)
+
+	params := db.CreateProjectParams{
+		Name:        req.Name,
+		Description: req.Description,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		ManagerID:   req.ManagerID,
+	}
+	validateProject(&v, params.Name, params.StartDate, params.EndDate, params.ManagerID)
+
+	return params, v.Errors()
+}
+
+// validateProject checks the fields shared by project creation and update
+func validateProject(v *validation.Validator, name string, startDate, endDate time.Time, managerID int64) {
+	v.Required("name", name, 255)
+	v.NotBefore("end_date", endDate, startDate, "start_date")
+	v.ID("manager_id", managerID)
+}
+
 // @Summary	List of projects from the repository
 // @Tags		projects
 // @Accept		json
 // @Produce	json
-// @Success	200	{array}		db.Project
-// @Failure	500	{object}	response.Object
+// @Param		sort	query		string	false	"Sort order, e.g. -end_date (name, start_date, end_date, manager_id, id)"
+// @Param		limit	query		int		false	"Page size (default 20, max 100)"
+// @Param		cursor	query		string	false	"Cursor returned as next_cursor by the previous page"
+// @Success	200		{array}		db.Project
+// @Failure	400		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
 // @Router		/projects [get]
 func (h *ProjectHandler) list(w http.ResponseWriter, r *http.Request) {
-	projects, err := h.db.ListProjects(r.Context())
+	sort, page, err := listPage(r, db.ProjectSortColumns, db.DefaultProjectSort)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	projects, err := h.db.SearchProjects(r.Context(), db.SearchProjectsParams{
+		Sort:      sort,
+		After:     page.After(),
+		PageLimit: page.FetchLimit(),
+	})
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		databaseError(w, r, err)
 		return
 	}
-	response.OK(w, r, projects)
+
+	projects, nextCursor := pagination.Trim(projects, page, db.ProjectSortColumns.Values(sort))
+	response.Paginated(w, r, projects, nextCursor)
 }
 
 // @Summary	Add a new project to the repository
@@ -71,41 +171,37 @@ func (h *ProjectHandler) list(w http.ResponseWriter, r *http.Request) {
 // @Param		request	body	createProjectRequest	true	"Project details"
 // @Success	200		{object}	db.Project
 // @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	422		{object}	response.Object
 // @Failure	500		{object}	response.Object
+// @Security	BearerAuth
 // @Router		/projects [post]
 func (h *ProjectHandler) add(w http.ResponseWriter, r *http.Request) {
-	var req createProjectRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, r, err, req)
+	actor, _ := UserFromContext(r.Context())
+	if err := policy.CanCreateProject(actor); err != nil {
+		response.Forbidden(w, r, err)
 		return
 	}
 
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
-	if err != nil {
+	var req createProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.BadRequest(w, r, err, req)
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", req.EndDate)
-	if err != nil {
-		response.BadRequest(w, r, err, req)
+	params, errs := req.params()
+	if len(errs) > 0 {
+		invalidRequest(w, r, errs)
 		return
 	}
 
-	params := db.CreateProjectParams{
-		Name:        req.Name,
-		Description: req.Description,
-		StartDate:   startDate,
-		EndDate:     endDate,
-		ManagerID:   req.ManagerID,
-	}
-
-	project, err := h.db.CreateProject(r.Context(), params)
+	project, err := h.db.CreateProjectTx(r.Context(), params)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		databaseError(w, r, err)
 		return
 	}
 
+	setETag(w, project.Version)
 	response.OK(w, r, project)
 }
 
@@ -115,8 +211,10 @@ func (h *ProjectHandler) add(w http.ResponseWriter, r *http.Request) {
 // @Produce	json
 // @Param		id	path		int	true	"Project ID"
 // @Success	200	{object}	db.Project
+// @Header		200	{string}	ETag	"Version of the project"
 // @Failure	404	{object}	response.Object
 // @Failure	500	{object}	response.Object
+// @Security	BearerAuth
 // @Router		/projects/{id} [get]
 func (h *ProjectHandler) get(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -127,27 +225,31 @@ func (h *ProjectHandler) get(w http.ResponseWriter, r *http.Request) {
 
 	project, err := h.db.GetProject(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		databaseError(w, r, err)
 		return
 	}
 
+	setETag(w, project.Version)
 	response.OK(w, r, project)
 }
 
 // @Summary	Update a project in the repository
+// @Description	The dates must still cover the due dates of the tasks and milestones of the project.
 // @Tags		projects
 // @Accept		json
 // @Produce	json
-// @Param		id		path		int						true	"Project ID"
-// @Param		request	body		db.UpdateProjectParams	true	"Project details"
-// @Success	200		{object}	db.Project
-// @Failure	400		{object}	response.Object
-// @Failure	404		{object}	response.Object
+// @Param		id			path		int						true	"Project ID"
+// @Param		If-Match	header		string					false	"ETag of the version being replaced"
+// @Param		request		body		db.UpdateProjectParams	true	"Project details"
+// @Success	200			{object}	db.Project
+// @Header		200			{string}	ETag	"Version of the project"
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	412			{object}	response.Object
+// @Failure	422		{object}	response.Object
 // @Failure	500		{object}	response.Object
+// @Security	BearerAuth
 // @Router		/projects/{id} [put]
 func (h *ProjectHandler) update(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -156,24 +258,145 @@ func (h *ProjectHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	current, ok := h.authorizeManage(w, r, id)
+	if !ok || !ifMatch(w, r, current.Version) {
+		return
+	}
+
 	var req db.UpdateProjectParams
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.BadRequest(w, r, err, req)
 		return
 	}
 
+	var v validation.Validator
+	v.Check(!req.StartDate.IsZero(), "start_date", "must not be empty")
+	v.Check(!req.EndDate.IsZero(), "end_date", "must not be empty")
+	validateProject(&v, req.Name, req.StartDate, req.EndDate, req.ManagerID)
+	if errs := v.Errors(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if !h.checkProjectDates(w, r, current, req.StartDate, req.EndDate) {
+		return
+	}
+
 	req.ID = id
+	req.Version = current.Version
 
-	project, err := h.db.UpdateProject(r.Context(), req)
+	project, err := h.db.UpdateProjectTx(r.Context(), req)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		updateError(w, r, err)
 		return
 	}
 
+	setETag(w, project.Version)
+	response.OK(w, r, project)
+}
+
+// patchProjectRequest is a merge patch of a project, absent fields are left
+// unchanged
+type patchProjectRequest struct {
+	Name        optional[string] `json:"name" swaggertype:"string"`
+	Description optional[string] `json:"description" swaggertype:"string"`
+	StartDate   optional[string] `json:"start_date" swaggertype:"string"`
+	EndDate     optional[string] `json:"end_date" swaggertype:"string"`
+	ManagerID   optional[int64]  `json:"manager_id" swaggertype:"integer"`
+}
+
+// params validates the project as it is after the patch and converts the
+// patch into the query parameters
+func (req patchProjectRequest) params(project db.Project) (db.PatchProjectParams, validation.Errors) {
+	var v validation.Validator
+	req.Name.required(&v, "name")
+	req.Description.required(&v, "description")
+	req.StartDate.required(&v, "start_date")
+	req.EndDate.required(&v, "end_date")
+	req.ManagerID.required(&v, "manager_id")
+
+	params := db.PatchProjectParams{
+		ID:          project.ID,
+		Version:     project.Version,
+		Name:        nullString(req.Name),
+		Description: nullString(req.Description),
+		ManagerID:   nullInt64(req.ManagerID),
+	}
+	if req.StartDate.present() {
+		params.StartDate = sql.NullTime{Time: v.Date("start_date", req.StartDate.Value), Valid: true}
+		project.StartDate = params.StartDate.Time
+	}
+	if req.EndDate.present() {
+		params.EndDate = sql.NullTime{Time: v.Date("end_date", req.EndDate.Value), Valid: true}
+		project.EndDate = params.EndDate.Time
+	}
+
+	validateProject(&v, req.Name.or(project.Name), project.StartDate, project.EndDate, req.ManagerID.or(project.ManagerID))
+
+	return params, v.Errors()
+}
+
+// @Summary	Partially update a project in the repository
+// @Description	Applies a JSON Merge Patch (RFC 7396): only the given fields change. The dates must still cover the due dates of the tasks and milestones of the project.
+// @Tags		projects
+// @Accept		application/merge-patch+json
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int					true	"Project ID"
+// @Param		If-Match	header		string				false	"ETag of the version being changed"
+// @Param		request		body		patchProjectRequest	true	"Fields to change"
+// @Success	200			{object}	db.Project
+// @Header		200			{string}	ETag	"Version of the project"
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	412			{object}	response.Object
+// @Failure	415			{object}	response.Object
+// @Failure	422			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id} [patch]
+func (h *ProjectHandler) patch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	current, ok := h.authorizeManage(w, r, id)
+	if !ok || !ifMatch(w, r, current.Version) {
+		return
+	}
+
+	var req patchProjectRequest
+	if !decodePatch(w, r, &req) {
+		return
+	}
+
+	params, errs := req.params(current)
+	if len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	startDate, endDate := current.StartDate, current.EndDate
+	if params.StartDate.Valid {
+		startDate = params.StartDate.Time
+	}
+	if params.EndDate.Valid {
+		endDate = params.EndDate.Time
+	}
+	if !h.checkProjectDates(w, r, current, startDate, endDate) {
+		return
+	}
+
+	project, err := h.db.PatchProjectTx(r.Context(), params)
+	if err != nil {
+		updateError(w, r, err)
+		return
+	}
+
+	setETag(w, project.Version)
 	response.OK(w, r, project)
 }
 
@@ -183,8 +406,11 @@ func (h *ProjectHandler) update(w http.ResponseWriter, r *http.Request) {
 // @Produce	json
 // @Param		id	path		int	true	"Project ID"
 // @Success	204	{object}	response.Object
+// @Failure	403	{object}	response.Object
 // @Failure	404	{object}	response.Object
+// @Failure	409	{object}	response.Object
 // @Failure	500	{object}	response.Object
+// @Security	BearerAuth
 // @Router		/projects/{id} [delete]
 func (h *ProjectHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -193,26 +419,75 @@ func (h *ProjectHandler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := h.authorizeManage(w, r, id); !ok {
+		return
+	}
+
 	if err := h.db.DeleteProject(r.Context(), id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		databaseError(w, r, err)
 		return
 	}
 
 	response.NoContent(w, r)
 }
 
+// authorizeManage loads the project and checks that the current user may modify it,
+// writing the error response and returning false otherwise
+func (h *ProjectHandler) authorizeManage(w http.ResponseWriter, r *http.Request, id int64) (db.Project, bool) {
+	project, err := h.db.GetProject(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return project, false
+	}
+
+	actor, _ := UserFromContext(r.Context())
+	if err := policy.CanManageProject(actor, project); err != nil {
+		response.Forbidden(w, r, err)
+		return project, false
+	}
+
+	return project, true
+}
+
+// authorizeRead loads the project and checks that the current user is allowed
+// to see its tasks and members, writing the error response and returning false otherwise
+func (h *ProjectHandler) authorizeRead(w http.ResponseWriter, r *http.Request, id int64) (db.Project, bool) {
+	project, err := h.db.GetProject(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return project, false
+	}
+
+	actor, _ := UserFromContext(r.Context())
+
+	member, err := projectMember(r.Context(), h.db, id, actor.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return project, false
+	}
+
+	if err := policy.CanReadProject(actor, member); err != nil {
+		response.Forbidden(w, r, err)
+		return project, false
+	}
+
+	return project, true
+}
+
 // @Summary	Get tasks for a project
 // @Tags		projects
 // @Accept		json
 // @Produce	json
-// @Param		id	path		int	true	"Project ID"
-// @Success	200	{array}		db.Task
-// @Failure	404	{object}	response.Object
-// @Failure	500	{object}	response.Object
+// @Param		id		path		int		true	"Project ID"
+// @Param		sort	query		string	false	"Sort order, e.g. -priority,creation_date (title, priority, status, assignee_id, project_id, creation_date, id)"
+// @Param		limit	query		int		false	"Page size (default 20, max 100)"
+// @Param		cursor	query		string	false	"Cursor returned as next_cursor by the previous page"
+// @Success	200		{array}		db.Task
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
 // @Router		/projects/{id}/tasks [get]
 func (h *ProjectHandler) getTasks(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -221,54 +496,692 @@ func (h *ProjectHandler) getTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tasks, err := h.db.GetProjectTasks(r.Context(), id)
+	sort, page, err := listPage(r, db.TaskSortColumns, db.DefaultTaskSort)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
 		return
 	}
 
-	response.OK(w, r, tasks)
+	// access to the project was checked above, so the viewer scope is not needed
+	tasks, err := h.db.SearchTasks(r.Context(), db.SearchTasksParams{
+		ProjectIDs:    []int64{id},
+		ViewerIsAdmin: true,
+		Sort:          sort,
+		After:         page.After(),
+		PageLimit:     page.FetchLimit(),
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	tasks, nextCursor := pagination.Trim(tasks, page, db.TaskSortColumns.Values(sort))
+	response.Paginated(w, r, tasks, nextCursor)
 }
 
-// @Summary	Search projects by title or manager ID
+// @Summary	Search projects by title and/or manager ID
 // @Tags		projects
 // @Accept		json
 // @Produce	json
 // @Param		title		query	string	false	"Project title"
 // @Param		manager		query	int		false	"Manager ID"
+// @Param		sort		query	string	false	"Sort order, e.g. -end_date (name, start_date, end_date, manager_id, id)"
+// @Param		limit		query	int		false	"Page size (default 20, max 100)"
+// @Param		cursor		query	string	false	"Cursor returned as next_cursor by the previous page"
 // @Success	200	{array}		db.Project
 // @Failure	400	{object}	response.Object
 // @Failure	500	{object}	response.Object
+// @Security	BearerAuth
 // @Router		/projects/search [get]
 func (h *ProjectHandler) search(w http.ResponseWriter, r *http.Request) {
 	title := r.URL.Query().Get("title")
 	managerIDStr := r.URL.Query().Get("manager")
-	
-	if title != "" {
-		projects, err := h.db.SearchProjectsByTitle(r.Context(), sql.NullString{String: title, Valid: true})
-		if err != nil {
-			response.InternalServerError(w, r, err)
-			return
-		}
-		response.OK(w, r, projects)
+
+	if title == "" && managerIDStr == "" {
+		response.BadRequest(w, r, errors.New("missing search criteria"), nil)
 		return
 	}
 
+	params := db.SearchProjectsParams{Name: title}
+
 	if managerIDStr != "" {
 		managerID, err := strconv.ParseInt(managerIDStr, 10, 64)
 		if err != nil {
 			response.BadRequest(w, r, err, nil)
 			return
 		}
+		params.ManagerID = managerID
+	}
 
-		projects, err := h.db.SearchProjectsByManager(r.Context(), managerID)
-		if err != nil {
-			response.InternalServerError(w, r, err)
-			return
-		}
-		response.OK(w, r, projects)
+	sort, page, err := listPage(r, db.ProjectSortColumns, db.DefaultProjectSort)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	params.Sort = sort
+	params.After = page.After()
+	params.PageLimit = page.FetchLimit()
+
+	projects, err := h.db.SearchProjects(r.Context(), params)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	projects, nextCursor := pagination.Trim(projects, page, db.ProjectSortColumns.Values(sort))
+	response.Paginated(w, r, projects, nextCursor)
+}
+
+type addMemberRequest struct {
+	UserID int64          `json:"user_id"`
+	Role   db.ProjectRole `json:"role"`
+}
+
+// @Summary	List members of a project
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id	path		int	true	"Project ID"
+// @Success	200	{array}		db.ListProjectMembersRow
+// @Failure	403	{object}	response.Object
+// @Failure	404	{object}	response.Object
+// @Failure	500	{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/members [get]
+func (h *ProjectHandler) listMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
+		return
+	}
+
+	members, err := h.db.ListProjectMembers(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, members)
+}
+
+// @Summary	Add a member to a project or change the member's role
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int					true	"Project ID"
+// @Param		request	body		addMemberRequest	true	"Member details"
+// @Success	200		{object}	db.ProjectMember
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	422		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/members [post]
+func (h *ProjectHandler) addMember(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req addMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if req.Role == "" {
+		req.Role = db.ProjectRoleMember
+	}
+
+	var v validation.Validator
+	v.ID("user_id", req.UserID)
+	validation.OneOf(&v, "role", req.Role, db.AllProjectRoleValues())
+	if errs := v.Errors(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	project, ok := h.authorizeManage(w, r, id)
+	if !ok {
+		return
+	}
+
+	if req.UserID == project.ManagerID && req.Role != db.ProjectRoleManager {
+		invalidRequest(w, r, validation.Errors{{Field: "role", Message: "must be manager for the project manager"}})
+		return
+	}
+
+	if _, err := h.db.GetUser(r.Context(), req.UserID); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	member, err := h.db.AddProjectMember(r.Context(), db.AddProjectMemberParams{
+		ProjectID: id,
+		UserID:    req.UserID,
+		Role:      req.Role,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, member)
+}
+
+// @Summary	Remove a member from a project
+// @Description	A member with assigned tasks cannot be removed until the tasks are reassigned. The project manager cannot be removed, the request fails with 409 and the code manager_membership.
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int	true	"Project ID"
+// @Param		userID	path		int	true	"User ID"
+// @Success	204		{object}	response.Object
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	409		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/members/{userID} [delete]
+func (h *ProjectHandler) removeMember(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	userID, err := strconv.ParseInt(chi.URLParam(r, "userID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	project, ok := h.authorizeManage(w, r, id)
+	if !ok {
+		return
+	}
+
+	if userID == project.ManagerID {
+		response.Conflict(w, r, errManagerMembership, codeManagerMembership)
+		return
+	}
+
+	removed, err := h.db.RemoveProjectMember(r.Context(), db.RemoveProjectMemberParams{
+		ProjectID: id,
+		UserID:    userID,
+	})
+	if err != nil {
+		databaseError(w, r, err)
 		return
 	}
 
-	response.BadRequest(w, r, errors.New("missing search criteria"), nil)
+	if removed == 0 {
+		response.NotFound(w, r, errNotMember)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+type workflowStatusRequest struct {
+	Name     string            `json:"name"`
+	Category db.StatusCategory `json:"category"`
+	Position int32             `json:"position"`
+}
+
+func (req workflowStatusRequest) validate() validation.Errors {
+	var v validation.Validator
+	v.Required("name", req.Name, 50)
+	validation.OneOf(&v, "category", req.Category, db.AllStatusCategoryValues())
+	return v.Errors()
+}
+
+// @Summary	List the workflow statuses of a project
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id	path		int	true	"Project ID"
+// @Success	200	{array}		db.WorkflowStatus
+// @Failure	403	{object}	response.Object
+// @Failure	404	{object}	response.Object
+// @Failure	500	{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/statuses [get]
+func (h *ProjectHandler) listStatuses(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
+		return
+	}
+
+	statuses, err := h.db.ListWorkflowStatuses(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, statuses)
+}
+
+// @Summary	Add a status to the workflow of a project
+// @Description	The category (new, active or done) decides which transitions lead to the status.
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int						true	"Project ID"
+// @Param		request	body		workflowStatusRequest	true	"Status details"
+// @Success	200		{object}	db.WorkflowStatus
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	409		{object}	response.Object
+// @Failure	422		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/statuses [post]
+func (h *ProjectHandler) addStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeManage(w, r, id); !ok {
+		return
+	}
+
+	var req workflowStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if errs := req.validate(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	status, err := h.db.CreateWorkflowStatus(r.Context(), db.CreateWorkflowStatusParams{
+		ProjectID: id,
+		Name:      req.Name,
+		Category:  req.Category,
+		Position:  req.Position,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, status)
+}
+
+// @Summary	Rename, recategorize or move a workflow status
+// @Description	Tasks in the status follow a rename.
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int						true	"Project ID"
+// @Param		statusID	path		int						true	"Status ID"
+// @Param		request		body		workflowStatusRequest	true	"Status details"
+// @Success	200			{object}	db.WorkflowStatus
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	409			{object}	response.Object
+// @Failure	422			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/statuses/{statusID} [put]
+func (h *ProjectHandler) updateStatus(w http.ResponseWriter, r *http.Request) {
+	current, ok := h.projectStatus(w, r)
+	if !ok {
+		return
+	}
+
+	var req workflowStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if errs := req.validate(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	status, err := h.db.UpdateWorkflowStatus(r.Context(), db.UpdateWorkflowStatusParams{
+		ID:       current.ID,
+		Name:     req.Name,
+		Category: req.Category,
+		Position: req.Position,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, status)
+}
+
+// @Summary	Remove a status from the workflow of a project
+// @Description	A status still used by tasks cannot be removed.
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int	true	"Project ID"
+// @Param		statusID	path		int	true	"Status ID"
+// @Success	204			{object}	response.Object
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	409			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/statuses/{statusID} [delete]
+func (h *ProjectHandler) deleteStatus(w http.ResponseWriter, r *http.Request) {
+	status, ok := h.projectStatus(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DeleteWorkflowStatus(r.Context(), status.ID); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// projectStatus loads the workflow status addressed by the request and
+// checks that the current user may manage its project, writing the error
+// response and returning false otherwise
+func (h *ProjectHandler) projectStatus(w http.ResponseWriter, r *http.Request) (db.WorkflowStatus, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return db.WorkflowStatus{}, false
+	}
+
+	statusID, err := strconv.ParseInt(chi.URLParam(r, "statusID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return db.WorkflowStatus{}, false
+	}
+
+	if _, ok := h.authorizeManage(w, r, id); !ok {
+		return db.WorkflowStatus{}, false
+	}
+
+	status, err := h.db.GetWorkflowStatusByID(r.Context(), statusID)
+	if err == nil && status.ProjectID != id {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return status, false
+	}
+
+	return status, true
+}
+
+// @Summary	List the labels of a project
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id	path		int	true	"Project ID"
+// @Success	200	{array}		db.Label
+// @Failure	400	{object}	response.Object
+// @Failure	403	{object}	response.Object
+// @Failure	404	{object}	response.Object
+// @Failure	500	{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/labels [get]
+func (h *ProjectHandler) listLabels(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
+		return
+	}
+
+	labels, err := h.db.ListLabels(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, labels)
+}
+
+// @Summary	Add a label to a project
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int				true	"Project ID"
+// @Param		request	body		labelRequest	true	"Label details"
+// @Success	200		{object}	db.Label
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	409		{object}	response.Object
+// @Failure	422		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/labels [post]
+func (h *ProjectHandler) addLabel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeManage(w, r, id); !ok {
+		return
+	}
+
+	var req labelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if errs := req.validate(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	label, err := h.db.CreateLabel(r.Context(), db.CreateLabelParams{
+		ProjectID: id,
+		Name:      req.Name,
+		Color:     req.color(),
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, label)
+}
+
+// @Summary	Rename or recolor a label
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int				true	"Project ID"
+// @Param		labelID	path		int				true	"Label ID"
+// @Param		request	body		labelRequest	true	"Label details"
+// @Success	200		{object}	db.Label
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	409		{object}	response.Object
+// @Failure	422		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/labels/{labelID} [put]
+func (h *ProjectHandler) updateLabel(w http.ResponseWriter, r *http.Request) {
+	current, ok := h.projectLabel(w, r)
+	if !ok {
+		return
+	}
+
+	var req labelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if errs := req.validate(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	label, err := h.db.UpdateLabel(r.Context(), db.UpdateLabelParams{
+		ProjectID: current.ProjectID,
+		ID:        current.ID,
+		Name:      req.Name,
+		Color:     req.color(),
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, label)
+}
+
+// @Summary	Delete a label of a project
+// @Description	The label is removed from every task carrying it.
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int	true	"Project ID"
+// @Param		labelID	path		int	true	"Label ID"
+// @Success	204		{object}	response.Object
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/labels/{labelID} [delete]
+func (h *ProjectHandler) deleteLabel(w http.ResponseWriter, r *http.Request) {
+	label, ok := h.projectLabel(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DeleteLabel(r.Context(), db.DeleteLabelParams{ProjectID: label.ProjectID, ID: label.ID}); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// projectLabel loads the label addressed by the request and checks that the
+// current user may manage its project, writing the error response and
+// returning false otherwise
+func (h *ProjectHandler) projectLabel(w http.ResponseWriter, r *http.Request) (db.Label, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return db.Label{}, false
+	}
+
+	labelID, err := strconv.ParseInt(chi.URLParam(r, "labelID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return db.Label{}, false
+	}
+
+	if _, ok := h.authorizeManage(w, r, id); !ok {
+		return db.Label{}, false
+	}
+
+	label, err := h.db.GetLabel(r.Context(), db.GetLabelParams{ProjectID: id, ID: labelID})
+	if err != nil {
+		databaseError(w, r, err)
+		return label, false
+	}
+
+	return label, true
+}
+
+// @Summary	Get the time logged on a project
+// @Description	Sums the stopped worklogs on the tasks of the project started within the range, per task and per user. Running timers are not counted.
+// @Tags		worklogs
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int		true	"Project ID"
+// @Param		from	query		string	false	"Started on or after (YYYY-MM-DD or RFC 3339)"
+// @Param		to		query		string	false	"Started on or before (YYYY-MM-DD or RFC 3339)"
+// @Success	200		{object}	projectTimeResponse
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/time [get]
+func (h *ProjectHandler) projectTime(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	from, to, err := timeRange(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
+		return
+	}
+
+	byTask, err := h.db.SumProjectWorklogsByTask(r.Context(), db.SumProjectWorklogsByTaskParams{
+		ProjectID:   id,
+		StartedFrom: from,
+		StartedTo:   to,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	byUser, err := h.db.SumProjectWorklogsByUser(r.Context(), db.SumProjectWorklogsByUserParams{
+		ProjectID:   id,
+		StartedFrom: from,
+		StartedTo:   to,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, projectTimeResponse{
+		ProjectID:    id,
+		TotalSeconds: sumSeconds(byTask, func(row db.SumProjectWorklogsByTaskRow) int64 { return row.Seconds }),
+		ByTask:       byTask,
+		ByUser:       byUser,
+	})
 }
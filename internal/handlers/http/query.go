@@ -0,0 +1,81 @@
+package http
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/pkg/pagination"
+)
+
+const dateLayout = "2006-01-02"
+
+// queryList returns the comma separated values of a query parameter, which
+// may also be repeated (status=new,in_progress or status=new&status=in_progress)
+func queryList(r *http.Request, name string) []string {
+	var values []string
+	for _, param := range r.URL.Query()[name] {
+		for _, v := range strings.Split(param, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// queryIDs parses the values of a query parameter as a list of ids
+func queryIDs(r *http.Request, name string) ([]int64, error) {
+	var ids []int64
+	for _, v := range queryList(r, name) {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", name, v)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// queryTime parses a query parameter given either as a date or as an
+// RFC 3339 timestamp. A date used as an upper bound covers the whole day.
+func queryTime(r *http.Request, name string, upperBound bool) (sql.NullTime, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return sql.NullTime{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return sql.NullTime{Time: t, Valid: true}, nil
+	}
+
+	t, err := time.Parse(dateLayout, v)
+	if err != nil {
+		return sql.NullTime{}, fmt.Errorf("invalid %s %q, expected YYYY-MM-DD or RFC 3339", name, v)
+	}
+	if upperBound {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return sql.NullTime{Time: t, Valid: true}, nil
+}
+
+// listPage reads the sort, limit and cursor query parameters of a listing.
+// The sort is checked against the whitelisted columns, def applies when the
+// request does not specify one.
+func listPage[T any](r *http.Request, columns db.SortColumns[T], def db.Sort) (db.Sort, pagination.Page, error) {
+	sort, err := columns.Parse(r.URL.Query().Get("sort"), def)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	page, err := pagination.FromRequest(r, sort.String())
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	return sort, page, nil
+}
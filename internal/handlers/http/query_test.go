@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"project-management-service/pkg/pagination"
+)
+
+func TestListRejectsForgedCursor(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer conn.Close()
+
+	routes := NewUserHandler(conn).Routes()
+
+	for name, cursor := range map[string]pagination.Cursor{
+		"missing value": {Sort: "full_name,id", Values: []interface{}{"Bob"}},
+		"extra value":   {Sort: "full_name,id", Values: []interface{}{"Bob", 3, 4}},
+		"object value":  {Sort: "full_name,id", Values: []interface{}{map[string]interface{}{"x": 1}, 3}},
+		"array value":   {Sort: "full_name,id", Values: []interface{}{"Bob", []interface{}{3}}},
+	} {
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, httptest.NewRequest("GET", "/?cursor="+cursor.Encode(), nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+		assert.Contains(t, w.Body.String(), pagination.ErrInvalidCursor.Error(), name)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchCombinesFilters(t *testing.T) {
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error initializing mock database: %v", err)
+	}
+	defer conn.Close()
+
+	mock.ExpectQuery(`FROM users WHERE full_name ILIKE '%' \|\| \$1 \|\| '%' ESCAPE '\\' AND email ILIKE '%' \|\| \$2 \|\| '%' ESCAPE '\\' ORDER BY full_name ASC, id ASC`).
+		WithArgs("Alice", "example.com", int32(21)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "full_name", "email", "registration_date", "role", "hashed_password", "version", "weekly_capacity"}))
+	mock.ExpectQuery(`FROM projects WHERE name ILIKE '%' \|\| \$1 \|\| '%' ESCAPE '\\' AND manager_id = \$2 ORDER BY start_date ASC, id ASC`).
+		WithArgs("Apollo", int64(3), int32(21)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "description", "start_date", "end_date", "manager_id", "version"}))
+
+	w := httptest.NewRecorder()
+	NewUserHandler(conn).Routes().ServeHTTP(w, httptest.NewRequest("GET", "/search?name=Alice&email=example.com", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	NewProjectHandler(conn).Routes().ServeHTTP(w, httptest.NewRequest("GET", "/search?title=Apollo&manager=3", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
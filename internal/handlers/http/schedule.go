@@ -0,0 +1,120 @@
+package http
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/report"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/server/response"
+)
+
+// validateSchedule checks the due date and estimate of a task, both may be
+// left empty
+func validateSchedule(v *validation.Validator, dueDate string, estimate float64) {
+	if dueDate != "" {
+		v.Date("due_date", dueDate)
+	}
+	v.Check(estimate >= 0, "estimate", "must not be negative")
+}
+
+// parseDueDate reads a validated YYYY-MM-DD due date, an empty one means the
+// task has no due date
+func parseDueDate(value string) sql.NullTime {
+	t, err := time.Parse(dateLayout, value)
+	return sql.NullTime{Time: t, Valid: err == nil}
+}
+
+// nullEstimate stores a zero estimate as no estimate
+func nullEstimate(estimate float64) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: estimate, Valid: estimate > 0}
+}
+
+// checkDueDate checks that the due date falls within the dates of the
+// project, writing the error response and returning false otherwise
+func (h *TaskHandler) checkDueDate(w http.ResponseWriter, r *http.Request, projectID int64, dueDate sql.NullTime) bool {
+	if !dueDate.Valid {
+		return true
+	}
+
+	project, err := h.db.GetProject(r.Context(), projectID)
+	if err != nil {
+		databaseError(w, r, err)
+		return false
+	}
+
+	due := report.Day(dueDate.Time)
+	if due.Before(report.Day(project.StartDate)) || due.After(report.Day(project.EndDate)) {
+		invalidRequest(w, r, validation.Errors{{
+			Field:   "due_date",
+			Message: "must be within the project dates, " + project.StartDate.Format(dateLayout) + " to " + project.EndDate.Format(dateLayout),
+		}})
+		return false
+	}
+
+	return true
+}
+
+// checkProjectDates checks that the new dates of a project still cover the
+// due dates of its tasks and milestones, writing the error response and
+// returning false otherwise
+func (h *ProjectHandler) checkProjectDates(w http.ResponseWriter, r *http.Request, project db.Project, startDate, endDate time.Time) bool {
+	if !report.Day(startDate).After(report.Day(project.StartDate)) && !report.Day(endDate).Before(report.Day(project.EndDate)) {
+		return true
+	}
+
+	due, err := h.db.CountDueOutsideDates(r.Context(), db.CountDueOutsideDatesParams{
+		ProjectID: project.ID,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return false
+	}
+
+	var v validation.Validator
+	v.Check(due.BeforeStart == 0, "start_date", fmt.Sprintf("must not be after the due dates of the project, %d tasks or milestones are due earlier", due.BeforeStart))
+	v.Check(due.AfterEnd == 0, "end_date", fmt.Sprintf("must not be before the due dates of the project, %d tasks or milestones are due later", due.AfterEnd))
+	if errs := v.Errors(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return false
+	}
+
+	return true
+}
+
+// @Summary List overdue tasks
+// @Description Unfinished tasks whose due date has passed, from the projects you are a member of. Accepts the filters of /tasks/search.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param status query string false "Task statuses, e.g. new,in_progress"
+// @Param priority query string false "Task priorities, e.g. high,medium"
+// @Param assignee query string false "Assignee IDs, e.g. 3,7"
+// @Param project query string false "Project IDs, e.g. 1,2"
+// @Param due_after query string false "Due on or after (YYYY-MM-DD)"
+// @Param due_before query string false "Due on or before (YYYY-MM-DD)"
+// @Param label query string false "Label names, e.g. bug,urgent"
+// @Param label_match query string false "Whether tasks need any (default) or all of the labels" Enums(any, all)
+// @Param sort query string false "Sort order, e.g. -priority,creation_date (title, priority, status, assignee_id, project_id, creation_date, id)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
+// @Success 200 {array} db.Task
+// @Failure 400 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/overdue [get]
+func (h *TaskHandler) overdue(w http.ResponseWriter, r *http.Request) {
+	params, err := searchTasksParams(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	params.Overdue = true
+	h.searchTasks(w, r, params)
+}
@@ -0,0 +1,692 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/dberr"
+	"project-management-service/internal/policy"
+	"project-management-service/internal/report"
+	"project-management-service/internal/sprint"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Error codes of sprint changes refused in the current state
+const (
+	codeSprintState  = "sprint_state"
+	codeSprintActive = "sprint_active"
+)
+
+// activeSprintIndex is the unique index that keeps a project to one active
+// sprint
+const activeSprintIndex = "sprints_active_key"
+
+var errTaskNotInSprint = errors.New("the task is not in this sprint")
+
+// sprintRequest holds the details of a sprint. The capacity is in the unit
+// of the task estimates, zero means the sprint has no capacity.
+type sprintRequest struct {
+	Name      string  `json:"name"`
+	Goal      string  `json:"goal"`
+	StartDate string  `json:"start_date" example:"2024-07-01"`
+	EndDate   string  `json:"end_date" example:"2024-07-14"`
+	Capacity  float64 `json:"capacity"`
+}
+
+// validate checks the sprint against the dates of its project and returns
+// its start and end dates
+func (req sprintRequest) validate(project db.Project) (time.Time, time.Time, validation.Errors) {
+	var v validation.Validator
+	v.Required("name", req.Name, 100)
+	startDate := v.Date("start_date", req.StartDate)
+	endDate := v.Date("end_date", req.EndDate)
+	v.NotBefore("end_date", endDate, startDate, "start_date")
+	v.Check(startDate.IsZero() || !report.Day(startDate).Before(report.Day(project.StartDate)), "start_date", "must not be before the project start_date "+project.StartDate.Format(dateLayout))
+	v.Check(endDate.IsZero() || !report.Day(endDate).After(report.Day(project.EndDate)), "end_date", "must not be after the project end_date "+project.EndDate.Format(dateLayout))
+	v.Check(req.Capacity >= 0, "capacity", "must not be negative")
+	return startDate, endDate, v.Errors()
+}
+
+// closeSprintRequest names the planned sprint the unfinished tasks move to,
+// they go back to the backlog without one
+type closeSprintRequest struct {
+	NextSprintID int64 `json:"next_sprint_id"`
+}
+
+// sprintTaskRequest names a task of the project
+type sprintTaskRequest struct {
+	TaskID int64 `json:"task_id"`
+}
+
+// sprintResponse is a sprint with the number of its tasks, how many of them
+// are done and the sum of their estimates
+type sprintResponse struct {
+	db.Sprint
+	Tasks        int64   `json:"tasks"`
+	Completed    int64   `json:"completed"`
+	Committed    float64 `json:"committed"`
+	OverCapacity bool    `json:"over_capacity"`
+}
+
+func newSprintResponse(s db.Sprint, tasks, completed int64, committed float64) sprintResponse {
+	return sprintResponse{
+		Sprint:       s,
+		Tasks:        tasks,
+		Completed:    completed,
+		Committed:    committed,
+		OverCapacity: sprint.OverCapacity(s.Capacity, committed),
+	}
+}
+
+// closeSprintResponse is the closed sprint with the number of unfinished
+// tasks moved to the next sprint or the backlog
+type closeSprintResponse struct {
+	Sprint       db.Sprint `json:"sprint"`
+	CarriedOver  int64     `json:"carried_over"`
+	NextSprintID *int64    `json:"next_sprint_id"`
+}
+
+// @Summary	List the sprints of a project
+// @Tags		sprints
+// @Accept		json
+// @Produce	json
+// @Param		id	path		int	true	"Project ID"
+// @Success	200	{array}		sprintResponse
+// @Failure	400	{object}	response.Object
+// @Failure	403	{object}	response.Object
+// @Failure	404	{object}	response.Object
+// @Failure	500	{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints [get]
+func (h *ProjectHandler) listSprints(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
+		return
+	}
+
+	rows, err := h.db.ListSprints(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	sprints := make([]sprintResponse, len(rows))
+	for i, row := range rows {
+		sprints[i] = newSprintResponse(row.Sprint, row.Tasks, row.Completed, row.Committed)
+	}
+
+	response.OK(w, r, sprints)
+}
+
+// @Summary	Plan a sprint of a project
+// @Description	The sprint starts out planned and must fall within the project dates.
+// @Tags		sprints
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int				true	"Project ID"
+// @Param		request	body		sprintRequest	true	"Sprint details"
+// @Success	200		{object}	sprintResponse
+// @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	422		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints [post]
+func (h *ProjectHandler) addSprint(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	project, ok := h.authorizeManage(w, r, id)
+	if !ok {
+		return
+	}
+
+	var req sprintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	startDate, endDate, errs := req.validate(project)
+	if len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	created, err := h.db.CreateSprint(r.Context(), db.CreateSprintParams{
+		ProjectID: id,
+		Name:      req.Name,
+		Goal:      req.Goal,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Capacity:  nullEstimate(req.Capacity),
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newSprintResponse(created, 0, 0, 0))
+}
+
+// @Summary	Get a sprint of a project
+// @Tags		sprints
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int	true	"Project ID"
+// @Param		sprintID	path		int	true	"Sprint ID"
+// @Success	200			{object}	sprintResponse
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints/{sprintID} [get]
+func (h *ProjectHandler) getSprint(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
+		return
+	}
+
+	s, ok := h.sprint(w, r, id)
+	if !ok {
+		return
+	}
+
+	h.sprintSummary(w, r, s)
+}
+
+// @Summary	Change the details of a sprint
+// @Description	Closed sprints cannot be changed.
+// @Tags		sprints
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int				true	"Project ID"
+// @Param		sprintID	path		int				true	"Sprint ID"
+// @Param		request		body		sprintRequest	true	"Sprint details"
+// @Success	200			{object}	sprintResponse
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	409			{object}	response.Object
+// @Failure	422			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints/{sprintID} [put]
+func (h *ProjectHandler) updateSprint(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	project, ok := h.authorizeManage(w, r, id)
+	if !ok {
+		return
+	}
+
+	current, ok := h.sprint(w, r, id)
+	if !ok {
+		return
+	}
+
+	var req sprintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	startDate, endDate, errs := req.validate(project)
+	if len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if err := sprint.CanChange(current); err != nil {
+		response.Conflict(w, r, err, codeSprintState)
+		return
+	}
+
+	updated, err := h.db.UpdateSprint(r.Context(), db.UpdateSprintParams{
+		ProjectID: id,
+		ID:        current.ID,
+		Name:      req.Name,
+		Goal:      req.Goal,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Capacity:  nullEstimate(req.Capacity),
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	h.sprintSummary(w, r, updated)
+}
+
+// @Summary	Delete a planned sprint
+// @Description	Its tasks go back to the backlog. Sprints that have started cannot be deleted.
+// @Tags		sprints
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int	true	"Project ID"
+// @Param		sprintID	path		int	true	"Sprint ID"
+// @Success	204			{object}	response.Object
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	409			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints/{sprintID} [delete]
+func (h *ProjectHandler) deleteSprint(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeManage(w, r, id); !ok {
+		return
+	}
+
+	s, ok := h.sprint(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := sprint.CanDelete(s); err != nil {
+		response.Conflict(w, r, err, codeSprintState)
+		return
+	}
+
+	if err := h.db.DeleteSprint(r.Context(), db.DeleteSprintParams{ProjectID: id, ID: s.ID}); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// @Summary	Start a planned sprint
+// @Description	A project has at most one active sprint, starting a second one fails with 409 and the code sprint_active.
+// @Tags		sprints
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int	true	"Project ID"
+// @Param		sprintID	path		int	true	"Sprint ID"
+// @Success	200			{object}	sprintResponse
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	409			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints/{sprintID}/start [post]
+func (h *ProjectHandler) startSprint(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeManage(w, r, id); !ok {
+		return
+	}
+
+	s, ok := h.sprint(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := sprint.CanStart(s); err != nil {
+		response.Conflict(w, r, err, codeSprintState)
+		return
+	}
+
+	// The active sprint index allows one active sprint per project,
+	// concurrent starts cannot both succeed
+	started, err := h.db.SetSprintState(r.Context(), db.SetSprintStateParams{
+		State:     db.SprintStateActive,
+		ProjectID: id,
+		ID:        s.ID,
+		Expected:  db.SprintStatePlanned,
+	})
+	if dberr.IsUniqueViolation(err, activeSprintIndex) {
+		response.Conflict(w, r, sprint.ErrAlreadyOpen, codeSprintActive)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		response.Conflict(w, r, sprint.ErrNotPlanned, codeSprintState)
+		return
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	h.sprintSummary(w, r, started)
+}
+
+// @Summary	Close the active sprint
+// @Description	Tasks of the sprint that are not done move to the planned sprint named by next_sprint_id, or back to the backlog without one.
+// @Tags		sprints
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int					true	"Project ID"
+// @Param		sprintID	path		int					true	"Sprint ID"
+// @Param		request		body		closeSprintRequest	false	"Where unfinished tasks go"
+// @Success	200			{object}	closeSprintResponse
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	409			{object}	response.Object
+// @Failure	422			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints/{sprintID}/close [post]
+func (h *ProjectHandler) closeSprint(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeManage(w, r, id); !ok {
+		return
+	}
+
+	s, ok := h.sprint(w, r, id)
+	if !ok {
+		return
+	}
+
+	var req closeSprintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if err := sprint.CanClose(s); err != nil {
+		response.Conflict(w, r, err, codeSprintState)
+		return
+	}
+
+	if req.NextSprintID != 0 {
+		next, err := h.db.GetSprint(r.Context(), db.GetSprintParams{ProjectID: id, ID: req.NextSprintID})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			databaseError(w, r, err)
+			return
+		}
+		if err != nil || sprint.CanCarryOver(s, next) != nil {
+			invalidRequest(w, r, validation.Errors{{Field: "next_sprint_id", Message: sprint.ErrCarryOver.Error()}})
+			return
+		}
+	}
+
+	result, err := h.db.CloseSprintTx(r.Context(), db.CloseSprintTxParams{
+		ProjectID:    id,
+		SprintID:     s.ID,
+		NextSprintID: sql.NullInt64{Int64: req.NextSprintID, Valid: req.NextSprintID != 0},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		response.Conflict(w, r, sprint.ErrNotActive, codeSprintState)
+		return
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	rsp := closeSprintResponse{Sprint: result.Sprint, CarriedOver: result.CarriedOver}
+	if req.NextSprintID != 0 {
+		rsp.NextSprintID = &req.NextSprintID
+	}
+
+	response.OK(w, r, rsp)
+}
+
+// @Summary	List the tasks of a sprint
+// @Tags		sprints
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int	true	"Project ID"
+// @Param		sprintID	path		int	true	"Sprint ID"
+// @Success	200			{array}		db.Task
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints/{sprintID}/tasks [get]
+func (h *ProjectHandler) listSprintTasks(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, ok := h.authorizeRead(w, r, id); !ok {
+		return
+	}
+
+	s, ok := h.sprint(w, r, id)
+	if !ok {
+		return
+	}
+
+	tasks, err := h.db.ListSprintTasks(r.Context(), s.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, tasks)
+}
+
+// @Summary	Add a task to a sprint
+// @Description	The task leaves the sprint or backlog it was in. Tasks cannot be added to a closed sprint.
+// @Tags		sprints
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int					true	"Project ID"
+// @Param		sprintID	path		int					true	"Sprint ID"
+// @Param		request		body		sprintTaskRequest	true	"Task"
+// @Success	200			{object}	db.Task
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	409			{object}	response.Object
+// @Failure	422			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints/{sprintID}/tasks [post]
+func (h *ProjectHandler) addSprintTask(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if !h.authorizeTasks(w, r, id) {
+		return
+	}
+
+	s, ok := h.sprint(w, r, id)
+	if !ok {
+		return
+	}
+
+	var req sprintTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	var v validation.Validator
+	v.ID("task_id", req.TaskID)
+	if errs := v.Errors(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if err := sprint.CanChange(s); err != nil {
+		response.Conflict(w, r, err, codeSprintState)
+		return
+	}
+
+	task, err := h.db.SetTaskSprint(r.Context(), db.SetTaskSprintParams{
+		SprintID:  sql.NullInt64{Int64: s.ID, Valid: true},
+		ProjectID: id,
+		ID:        req.TaskID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		invalidRequest(w, r, validation.Errors{{Field: "task_id", Message: "must be a task of the project"}})
+		return
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	setETag(w, task.Version)
+	response.OK(w, r, task)
+}
+
+// @Summary	Move a task of a sprint back to the backlog
+// @Tags		sprints
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int	true	"Project ID"
+// @Param		sprintID	path		int	true	"Sprint ID"
+// @Param		taskID		path		int	true	"Task ID"
+// @Success	204			{object}	response.Object
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	409			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/sprints/{sprintID}/tasks/{taskID} [delete]
+func (h *ProjectHandler) removeSprintTask(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	taskID, err := strconv.ParseInt(chi.URLParam(r, "taskID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if !h.authorizeTasks(w, r, id) {
+		return
+	}
+
+	s, ok := h.sprint(w, r, id)
+	if !ok {
+		return
+	}
+
+	task, err := h.db.GetTask(r.Context(), taskID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if task.SprintID.Int64 != s.ID || !task.SprintID.Valid {
+		response.NotFound(w, r, errTaskNotInSprint)
+		return
+	}
+
+	if err := sprint.CanChange(s); err != nil {
+		response.Conflict(w, r, err, codeSprintState)
+		return
+	}
+
+	if _, err := h.db.SetTaskSprint(r.Context(), db.SetTaskSprintParams{ProjectID: id, ID: task.ID}); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// sprint loads the sprint of the project named in the path, writing the
+// error response and returning false when it cannot be read
+func (h *ProjectHandler) sprint(w http.ResponseWriter, r *http.Request, projectID int64) (db.Sprint, bool) {
+	sprintID, err := strconv.ParseInt(chi.URLParam(r, "sprintID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return db.Sprint{}, false
+	}
+
+	s, err := h.db.GetSprint(r.Context(), db.GetSprintParams{ProjectID: projectID, ID: sprintID})
+	if err != nil {
+		databaseError(w, r, err)
+		return db.Sprint{}, false
+	}
+
+	return s, true
+}
+
+// sprintSummary answers with the sprint and the counts of its tasks as they
+// are now
+func (h *ProjectHandler) sprintSummary(w http.ResponseWriter, r *http.Request, s db.Sprint) {
+	row, err := h.db.GetSprintSummary(r.Context(), db.GetSprintSummaryParams{ProjectID: s.ProjectID, ID: s.ID})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newSprintResponse(row.Sprint, row.Tasks, row.Completed, row.Committed))
+}
+
+// authorizeTasks checks that the current user may change the tasks of the
+// project, writing the error response and returning false otherwise
+func (h *ProjectHandler) authorizeTasks(w http.ResponseWriter, r *http.Request, id int64) bool {
+	if _, err := h.db.GetProject(r.Context(), id); err != nil {
+		databaseError(w, r, err)
+		return false
+	}
+
+	actor, _ := UserFromContext(r.Context())
+
+	member, err := projectMember(r.Context(), h.db, id, actor.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return false
+	}
+
+	if err := policy.CanWriteProjectTasks(actor, member); err != nil {
+		response.Forbidden(w, r, err)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/hierarchy"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/server/response"
+)
+
+// @Summary List the direct subtasks of a task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {array} db.Task
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/subtasks [get]
+func (h *TaskHandler) listSubtasks(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	subtasks, err := h.db.ListSubtasks(r.Context(), task.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, subtasks)
+}
+
+// @Summary Get a task with all its subtasks
+// @Description Returns the task with its subtasks nested at any depth. Every node carries the completion rollup of the tasks below it.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} hierarchy.Node
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/subtree [get]
+func (h *TaskHandler) subtree(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	status, err := h.db.GetWorkflowStatus(r.Context(), db.GetWorkflowStatusParams{ProjectID: task.ProjectID, Name: task.Status})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	rows, err := h.db.ListTaskSubtree(r.Context(), task.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, hierarchy.Build(task, status.Category, rows))
+}
+
+// progress computes the completion rollup of a task from its subtasks,
+// writing the error response and returning false when it cannot be read
+func (h *TaskHandler) progress(w http.ResponseWriter, r *http.Request, task db.Task) (hierarchy.Progress, bool) {
+	status, err := h.db.GetWorkflowStatus(r.Context(), db.GetWorkflowStatusParams{ProjectID: task.ProjectID, Name: task.Status})
+	if err != nil {
+		databaseError(w, r, err)
+		return hierarchy.Progress{}, false
+	}
+
+	rollup, err := h.db.GetTaskRollup(r.Context(), task.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return hierarchy.Progress{}, false
+	}
+
+	return hierarchy.NewProgress(rollup.Total, rollup.Done, status.Category), true
+}
+
+// checkParent checks that the task may become a subtask of the parent: the
+// parent must exist in the same project and may not be the task itself or
+// one of its subtasks. taskID is 0 for a task being created. It writes the
+// error response and returns false otherwise.
+func (h *TaskHandler) checkParent(w http.ResponseWriter, r *http.Request, taskID, projectID, parentID int64) bool {
+	parent, err := h.db.GetTask(r.Context(), parentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		invalidRequest(w, r, validation.Errors{{Field: "parent_task_id", Message: "the parent task does not exist"}})
+		return false
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return false
+	}
+
+	if parent.ProjectID != projectID {
+		invalidRequest(w, r, validation.Errors{{Field: "parent_task_id", Message: "must be a task of the same project"}})
+		return false
+	}
+
+	if taskID == 0 {
+		return true
+	}
+
+	cycle, err := h.db.IsTaskAncestor(r.Context(), db.IsTaskAncestorParams{TaskID: parentID, AncestorID: taskID})
+	if err != nil {
+		databaseError(w, r, err)
+		return false
+	}
+
+	if cycle {
+		invalidRequest(w, r, validation.Errors{{Field: "parent_task_id", Message: "must not be the task itself or one of its subtasks"}})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/report"
+	"project-management-service/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// topAssignees is the number of assignees listed in a project summary
+const topAssignees = 5
+
+// statusCount is the number of tasks in one status of the workflow
+type statusCount struct {
+	Status   string            `json:"status"`
+	Category db.StatusCategory `json:"category"`
+	Tasks    int64             `json:"tasks"`
+}
+
+// assigneeWork is the open work of an assignee
+type assigneeWork struct {
+	AssigneeID   int64   `json:"assignee_id"`
+	FullName     string  `json:"full_name"`
+	OpenTasks    int64   `json:"open_tasks"`
+	OpenEstimate float64 `json:"open_estimate"`
+}
+
+// projectSummary is the state of a project at a glance
+type projectSummary struct {
+	Project      db.Project             `json:"project"`
+	Tasks        int64                  `json:"tasks"`
+	Completed    int64                  `json:"completed"`
+	Overdue      int64                  `json:"overdue"`
+	Progress     report.Progress        `json:"progress"`
+	ByStatus     []statusCount          `json:"by_status"`
+	ByPriority   []report.PriorityCount `json:"by_priority"`
+	TopAssignees []assigneeWork         `json:"top_assignees"`
+}
+
+// @Summary	Get the summary of a project
+// @Description	Returns the task counts by status and priority, the overdue tasks, the percent complete against the share of the project time elapsed, the days until end_date and the five assignees with the most open tasks. The health is late once end_date has passed with open tasks or when completion trails the elapsed time by more than 25 points, and at risk from 10 points behind.
+// @Tags		projects
+// @Accept		json
+// @Produce	json
+// @Param		id	path		int	true	"Project ID"
+// @Success	200	{object}	projectSummary
+// @Failure	400	{object}	response.Object
+// @Failure	403	{object}	response.Object
+// @Failure	404	{object}	response.Object
+// @Failure	500	{object}	response.Object
+// @Security	BearerAuth
+// @Router		/projects/{id}/summary [get]
+func (h *ProjectHandler) summary(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	project, ok := h.authorizeRead(w, r, id)
+	if !ok {
+		return
+	}
+
+	totals, err := h.db.GetProjectTaskTotals(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	statusRows, err := h.db.CountProjectTasksByStatus(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	priorityRows, err := h.db.CountProjectTasksByPriority(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	assigneeRows, err := h.db.ListTopAssignees(r.Context(), db.ListTopAssigneesParams{ProjectID: id, Top: topAssignees})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	statuses := make([]statusCount, len(statusRows))
+	for i, row := range statusRows {
+		statuses[i] = statusCount{Status: row.Status, Category: row.Category, Tasks: row.Tasks}
+	}
+
+	assignees := make([]assigneeWork, len(assigneeRows))
+	for i, row := range assigneeRows {
+		assignees[i] = assigneeWork{
+			AssigneeID:   row.AssigneeID,
+			FullName:     row.FullName,
+			OpenTasks:    row.OpenTasks,
+			OpenEstimate: row.OpenEstimate,
+		}
+	}
+
+	response.OK(w, r, projectSummary{
+		Project:      project,
+		Tasks:        totals.Tasks,
+		Completed:    totals.Completed,
+		Overdue:      totals.Overdue,
+		Progress:     report.NewProgress(totals.Tasks, totals.Completed, project.StartDate, project.EndDate, time.Now()),
+		ByStatus:     statuses,
+		ByPriority:   report.PriorityCounts(priorityRows),
+		TopAssignees: assignees,
+	})
+}
@@ -4,22 +4,39 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"project-management-service/db/sqlc"
+	"project-management-service/internal/hierarchy"
+	"project-management-service/internal/policy"
+	"project-management-service/internal/validation"
+	"project-management-service/internal/workflow"
+	"project-management-service/pkg/pagination"
 	"project-management-service/pkg/server/response"
+	"project-management-service/pkg/storage"
 
 	"github.com/go-chi/chi/v5"
 )
 
+// Error codes of status changes the workflow forbids
+const (
+	codeTransitionRefused = "transition_not_allowed"
+	codeTaskBlocked       = "task_blocked"
+)
+
 type TaskHandler struct {
-	db *db.Queries
+	db    *db.Store
+	blobs storage.BlobStore
 }
 
-func NewTaskHandler(conn *sql.DB) *TaskHandler {
+func NewTaskHandler(conn *sql.DB, blobs storage.BlobStore) *TaskHandler {
 	return &TaskHandler{
-		db: db.New(conn),
+		db:    db.NewStore(conn),
+		blobs: blobs,
 	}
 }
 
@@ -32,95 +49,294 @@ func (h *TaskHandler) Routes() chi.Router {
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
+		r.Patch("/", h.patch)
 		r.Delete("/", h.delete)
+		r.Get("/transitions", h.listTransitions)
+		r.Post("/transitions", h.transition)
+		r.Get("/subtasks", h.listSubtasks)
+		r.Get("/subtree", h.subtree)
+		r.Get("/dependencies", h.listDependencies)
+		r.Post("/dependencies", h.addDependency)
+		r.Delete("/dependencies/{blockerID}", h.removeDependency)
+		r.Get("/labels", h.listTaskLabels)
+		r.Post("/labels", h.attachLabel)
+		r.Delete("/labels/{labelID}", h.detachLabel)
+		r.Get("/time", h.taskTime)
+		r.Post("/timer/start", h.startTimer)
+		r.Post("/timer/stop", h.stopTimer)
+
+		r.Route("/comments", func(r chi.Router) {
+			r.Get("/", h.listComments)
+			r.Post("/", h.addComment)
+			r.Get("/{commentID}", h.getComment)
+			r.Put("/{commentID}", h.updateComment)
+			r.Delete("/{commentID}", h.deleteComment)
+		})
+
+		r.Route("/worklogs", func(r chi.Router) {
+			r.Get("/", h.listWorklogs)
+			r.Post("/", h.addWorklog)
+			r.Delete("/{worklogID}", h.deleteWorklog)
+		})
+
+		r.Route("/attachments", func(r chi.Router) {
+			r.Get("/", h.listAttachments)
+			r.Post("/", h.addAttachment)
+			r.Get("/{attachmentID}", h.getAttachment)
+			r.Get("/{attachmentID}/content", h.downloadAttachment)
+			r.Delete("/{attachmentID}", h.deleteAttachment)
+		})
 	})
 
 	r.Get("/search", h.search)
+	r.Get("/overdue", h.overdue)
 
 	return r
 }
 
 // @Summary Search tasks
+// @Description Every given filter must match. Filters accepting several values take them comma separated and match any of them.
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param title query string false "Task title"
-// @Param status query string false "Task status"
-// @Param priority query string false "Task priority"
-// @Param assignee query int false "Assignee ID"
-// @Param project query int false "Project ID"
+// @Param title query string false "Part of the task title"
+// @Param status query string false "Task statuses, e.g. new,in_progress"
+// @Param category query string false "Status categories, e.g. new,active (new, active, done)"
+// @Param priority query string false "Task priorities, e.g. high,medium"
+// @Param assignee query string false "Assignee IDs, e.g. 3,7"
+// @Param project query string false "Project IDs, e.g. 1,2"
+// @Param created_from query string false "Created on or after (YYYY-MM-DD or RFC 3339)"
+// @Param created_to query string false "Created on or before (YYYY-MM-DD or RFC 3339)"
+// @Param completed_from query string false "Completed on or after (YYYY-MM-DD or RFC 3339)"
+// @Param completed_to query string false "Completed on or before (YYYY-MM-DD or RFC 3339)"
+// @Param due_after query string false "Due on or after (YYYY-MM-DD)"
+// @Param due_before query string false "Due on or before (YYYY-MM-DD)"
+// @Param label query string false "Label names, e.g. bug,urgent"
+// @Param label_match query string false "Whether tasks need any (default) or all of the labels" Enums(any, all)
+// @Param sort query string false "Sort order, e.g. -priority,creation_date (title, priority, status, assignee_id, project_id, creation_date, id)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
 // @Success 200 {array} db.Task
 // @Failure 400 {object} response.Object
 // @Failure 500 {object} response.Object
+// @Security BearerAuth
 // @Router /tasks/search [get]
 func (h *TaskHandler) search(w http.ResponseWriter, r *http.Request) {
-	title := r.URL.Query().Get("title")
-	status := r.URL.Query().Get("status")
-	priority := r.URL.Query().Get("priority")
-	assigneeIDStr := r.URL.Query().Get("assignee")
-	projectIDStr := r.URL.Query().Get("project")
+	params, err := searchTasksParams(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
 
-	var tasks []db.Task
-	var err error
+	h.searchTasks(w, r, params)
+}
 
-	switch {
-	case title != "":
-		tasks, err = h.db.SearchTasksByTitle(r.Context(), sql.NullString{String: title, Valid: true})
-	case status != "":
-		tasks, err = h.db.SearchTasksByStatus(r.Context(), db.TaskStatus(status))
-	case priority != "":
-		tasks, err = h.db.SearchTasksByPriority(r.Context(), db.TaskPriority(priority))
-	case assigneeIDStr != "":
-		assigneeID, parseErr := strconv.ParseInt(assigneeIDStr, 10, 64)
-		if parseErr != nil {
-			response.BadRequest(w, r, parseErr, nil)
-			return
-		}
-		tasks, err = h.db.SearchTasksByAssignee(r.Context(), assigneeID)
-	case projectIDStr != "":
-		projectID, parseErr := strconv.ParseInt(projectIDStr, 10, 64)
-		if parseErr != nil {
-			response.BadRequest(w, r, parseErr, nil)
-			return
-		}
-		tasks, err = h.db.SearchTasksByProject(r.Context(), projectID)
-	default:
-		response.BadRequest(w, r, errors.New("missing or invalid search criteria"), nil)
+// searchTasks answers with the page of tasks matching the filters
+func (h *TaskHandler) searchTasks(w http.ResponseWriter, r *http.Request, params db.SearchTasksParams) {
+	sort, page, err := listPage(r, db.TaskSortColumns, db.DefaultTaskSort)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
 		return
 	}
 
+	params.ViewerID, params.ViewerIsAdmin = viewerScope(r)
+	params.Sort = sort
+	params.After = page.After()
+	params.PageLimit = page.FetchLimit()
+
+	tasks, err := h.db.SearchTasks(r.Context(), params)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		databaseError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, tasks)
+	tasks, nextCursor := pagination.Trim(tasks, page, db.TaskSortColumns.Values(sort))
+	response.Paginated(w, r, tasks, nextCursor)
 }
 
+// searchTasksParams reads the search filters from the query string
+func searchTasksParams(r *http.Request) (db.SearchTasksParams, error) {
+	params := db.SearchTasksParams{
+		Title: strings.TrimSpace(r.URL.Query().Get("title")),
+	}
+
+	params.Statuses = queryList(r, "status")
+
+	for _, v := range queryList(r, "category") {
+		category := db.StatusCategory(v)
+		if !category.Valid() {
+			return params, fmt.Errorf("invalid category %q", v)
+		}
+		params.Categories = append(params.Categories, category)
+	}
+
+	for _, v := range queryList(r, "priority") {
+		priority := db.TaskPriority(v)
+		if !priority.Valid() {
+			return params, fmt.Errorf("invalid priority %q", v)
+		}
+		params.Priorities = append(params.Priorities, priority)
+	}
+
+	var err error
+	if params.AssigneeIDs, err = queryIDs(r, "assignee"); err != nil {
+		return params, err
+	}
+	if params.ProjectIDs, err = queryIDs(r, "project"); err != nil {
+		return params, err
+	}
+	if params.CreatedFrom, err = queryTime(r, "created_from", false); err != nil {
+		return params, err
+	}
+	if params.CreatedTo, err = queryTime(r, "created_to", true); err != nil {
+		return params, err
+	}
+	if params.CompletedFrom, err = queryTime(r, "completed_from", false); err != nil {
+		return params, err
+	}
+	if params.CompletedTo, err = queryTime(r, "completed_to", true); err != nil {
+		return params, err
+	}
+	if params.DueAfter, err = queryTime(r, "due_after", false); err != nil {
+		return params, err
+	}
+	if params.DueBefore, err = queryTime(r, "due_before", false); err != nil {
+		return params, err
+	}
+	if params.Labels, params.AllLabels, err = labelFilter(r); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
+// labelFilter reads the label names to filter on and whether tasks need all
+// of them rather than any
+func labelFilter(r *http.Request) ([]string, bool, error) {
+	labels := queryList(r, "label")
+
+	switch match := r.URL.Query().Get("label_match"); match {
+	case "", "any":
+		return labels, false, nil
+	case "all":
+		return labels, true, nil
+	default:
+		return nil, false, fmt.Errorf("invalid label_match %q, expected any or all", match)
+	}
+}
+
+// createTaskRequest holds the fields of a new task. The status defaults to
+// the initial status of the project workflow and the completion date is set
+// by the workflow. A subtask names its parent task. The due date must fall
+// within the project dates, a zero estimate means the task is not estimated.
+// The milestone must be one of the project.
 type createTaskRequest struct {
-	Title          string       `json:"title"`
-	Description    string       `json:"description"`
-	Priority       string       `json:"priority"`
-	Status         string       `json:"status"`
-	AssigneeID     int64        `json:"assignee_id"`
-	ProjectID      int64        `json:"project_id"`
-	CompletionDate sql.NullTime `json:"completion_date"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	Priority     string  `json:"priority"`
+	Status       string  `json:"status"`
+	AssigneeID   int64   `json:"assignee_id"`
+	ProjectID    int64   `json:"project_id"`
+	ParentTaskID int64   `json:"parent_task_id"`
+	DueDate      string  `json:"due_date" example:"2024-07-19"`
+	Estimate     float64 `json:"estimate"`
+	MilestoneID  int64   `json:"milestone_id"`
+}
+
+func (req createTaskRequest) validate() validation.Errors {
+	errs := validateTask(req.Title, db.TaskPriority(req.Priority), req.Status, req.AssigneeID, req.ProjectID, req.ParentTaskID)
+	var v validation.Validator
+	validateSchedule(&v, req.DueDate, req.Estimate)
+	v.Check(req.MilestoneID >= 0, "milestone_id", "must be a positive id")
+	return append(errs, v.Errors()...)
+}
+
+// updateTaskRequest replaces the fields of a task, a task without
+// parent_task_id becomes a top level task and one without due_date,
+// estimate or milestone_id loses them
+type updateTaskRequest struct {
+	Title        string          `json:"title"`
+	Description  string          `json:"description"`
+	Priority     db.TaskPriority `json:"priority"`
+	Status       string          `json:"status"`
+	AssigneeID   int64           `json:"assignee_id"`
+	ProjectID    int64           `json:"project_id"`
+	ParentTaskID int64           `json:"parent_task_id"`
+	DueDate      string          `json:"due_date" example:"2024-07-19"`
+	Estimate     float64         `json:"estimate"`
+	MilestoneID  int64           `json:"milestone_id"`
+}
+
+func (req updateTaskRequest) validate() validation.Errors {
+	errs := validateTask(req.Title, req.Priority, req.Status, req.AssigneeID, req.ProjectID, req.ParentTaskID)
+	if req.Status == "" {
+		errs = append(errs, validation.FieldError{Field: "status", Message: "must not be empty"})
+	}
+	var v validation.Validator
+	validateSchedule(&v, req.DueDate, req.Estimate)
+	v.Check(req.MilestoneID >= 0, "milestone_id", "must be a positive id")
+	return append(errs, v.Errors()...)
+}
+
+// validateTask checks the fields shared by task creation and update, the
+// status is checked against the project workflow and the parent against the
+// task hierarchy separately
+func validateTask(title string, priority db.TaskPriority, status string, assigneeID, projectID, parentTaskID int64) validation.Errors {
+	var v validation.Validator
+	v.Required("title", title, 255)
+	validation.OneOf(&v, "priority", priority, db.AllTaskPriorityValues())
+	v.MaxLength("status", status, 50)
+	v.ID("assignee_id", assigneeID)
+	v.ID("project_id", projectID)
+	v.Check(parentTaskID >= 0, "parent_task_id", "must be a positive id")
+	return v.Errors()
 }
 
 // @Summary List of tasks from the repository
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param label query string false "Label names, e.g. bug,urgent"
+// @Param label_match query string false "Whether tasks need any (default) or all of the labels" Enums(any, all)
+// @Param sort query string false "Sort order, e.g. -priority,creation_date (title, priority, status, assignee_id, project_id, creation_date, id)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor returned as next_cursor by the previous page"
 // @Success 200 {array} db.Task
+// @Failure 400 {object} response.Object
 // @Failure 500 {object} response.Object
+// @Security BearerAuth
 // @Router /tasks [get]
 func (h *TaskHandler) list(w http.ResponseWriter, r *http.Request) {
-	tasks, err := h.db.ListTasks(r.Context())
+	sort, page, err := listPage(r, db.TaskSortColumns, db.DefaultTaskSort)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	labels, allLabels, err := labelFilter(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	viewerID, viewerIsAdmin := viewerScope(r)
+
+	tasks, err := h.db.SearchTasks(r.Context(), db.SearchTasksParams{
+		Labels:        labels,
+		AllLabels:     allLabels,
+		ViewerIsAdmin: viewerIsAdmin,
+		ViewerID:      viewerID,
+		Sort:          sort,
+		After:         page.After(),
+		PageLimit:     page.FetchLimit(),
+	})
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		databaseError(w, r, err)
 		return
 	}
-	response.OK(w, r, tasks)
+
+	tasks, nextCursor := pagination.Trim(tasks, page, db.TaskSortColumns.Values(sort))
+	response.Paginated(w, r, tasks, nextCursor)
 }
 
 // @Summary Add a new task to the repository
@@ -130,7 +346,10 @@ func (h *TaskHandler) list(w http.ResponseWriter, r *http.Request) {
 // @Param request body createTaskRequest true "Task details"
 // @Success 200 {object} db.Task
 // @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 422 {object} response.Object
 // @Failure 500 {object} response.Object
+// @Security BearerAuth
 // @Router /tasks [post]
 func (h *TaskHandler) add(w http.ResponseWriter, r *http.Request) {
 	var req createTaskRequest
@@ -139,33 +358,85 @@ func (h *TaskHandler) add(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if errs := req.validate(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, req.ProjectID, req.AssigneeID) {
+		return
+	}
+
+	wf, ok := h.workflow(w, r, req.ProjectID)
+	if !ok {
+		return
+	}
+
+	status, err := wf.Initial()
+	if req.Status != "" {
+		status, err = wf.Status(req.Status)
+	}
+	if err != nil {
+		workflowError(w, r, err)
+		return
+	}
+
+	if req.ParentTaskID != 0 && !h.checkParent(w, r, 0, req.ProjectID, req.ParentTaskID) {
+		return
+	}
+
+	dueDate := parseDueDate(req.DueDate)
+	if !h.checkDueDate(w, r, req.ProjectID, dueDate) {
+		return
+	}
+
+	if req.MilestoneID != 0 && !h.checkMilestone(w, r, req.ProjectID, req.MilestoneID) {
+		return
+	}
+
 	params := db.CreateTaskParams{
 		Title:          req.Title,
 		Description:    req.Description,
 		Priority:       db.TaskPriority(req.Priority),
-		Status:         db.TaskStatus(req.Status),
+		Status:         status.Name,
 		AssigneeID:     req.AssigneeID,
 		ProjectID:      req.ProjectID,
-		CompletionDate: req.CompletionDate,
+		CompletionDate: workflow.CompletionDate(sql.NullTime{}, "", status.Category, time.Now()),
+		ParentTaskID:   sql.NullInt64{Int64: req.ParentTaskID, Valid: req.ParentTaskID != 0},
+		DueDate:        dueDate,
+		Estimate:       nullEstimate(req.Estimate),
+		MilestoneID:    sql.NullInt64{Int64: req.MilestoneID, Valid: req.MilestoneID != 0},
 	}
 
 	task, err := h.db.CreateTask(r.Context(), params)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		databaseError(w, r, err)
 		return
 	}
 
+	setETag(w, task.Version)
 	response.OK(w, r, task)
 }
 
+// taskResponse is a task with the number of comments on it and the
+// completion rollup of its subtasks
+type taskResponse struct {
+	db.Task
+	CommentCount int64              `json:"comment_count"`
+	Progress     hierarchy.Progress `json:"progress"`
+}
+
 // @Summary Get a task from the repository
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path int true "Task ID"
-// @Success 200 {object} db.Task
+// @Success 200 {object} taskResponse
+// @Header 200 {string} ETag "Version of the task"
+// @Failure 403 {object} response.Object
 // @Failure 404 {object} response.Object
 // @Failure 500 {object} response.Object
+// @Security BearerAuth
 // @Router /tasks/{id} [get]
 func (h *TaskHandler) get(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -176,27 +447,48 @@ func (h *TaskHandler) get(w http.ResponseWriter, r *http.Request) {
 
 	task, err := h.db.GetTask(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		databaseError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, task)
+	if !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	comments, err := h.db.CountTaskComments(r.Context(), task.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	progress, ok := h.progress(w, r, task)
+	if !ok {
+		return
+	}
+
+	setETag(w, task.Version)
+	response.OK(w, r, taskResponse{Task: task, CommentCount: comments, Progress: progress})
 }
 
 // @Summary Update a task in the repository
+// @Description The status may only change along the workflow, see /tasks/{id}/transitions. The completion date is set by the workflow. The parent task must belong to the same project and may not be the task itself or one of its subtasks.
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path int true "Task ID"
-// @Param request body db.UpdateTaskParams true "Task details"
+// @Param If-Match header string false "ETag of the version being replaced"
+// @Param force query bool false "Start the task even though it is blocked"
+// @Param request body updateTaskRequest true "Task details"
 // @Success 200 {object} db.Task
+// @Header 200 {string} ETag "Version of the task"
 // @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
 // @Failure 404 {object} response.Object
+// @Failure 409 {object} response.Object
+// @Failure 412 {object} response.Object
+// @Failure 422 {object} response.Object
 // @Failure 500 {object} response.Object
+// @Security BearerAuth
 // @Router /tasks/{id} [put]
 func (h *TaskHandler) update(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -205,24 +497,240 @@ func (h *TaskHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req db.UpdateTaskParams
+	var req updateTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.BadRequest(w, r, err, req)
 		return
 	}
 
-	req.ID = id
+	if errs := req.validate(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
 
-	task, err := h.db.UpdateTask(r.Context(), req)
+	current, err := h.db.GetTask(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
+		databaseError(w, r, err)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, current.ProjectID, 0) || !h.authorizeWrite(w, r, req.ProjectID, req.AssigneeID) {
+		return
+	}
+
+	if !ifMatch(w, r, current.Version) {
+		return
+	}
+
+	completionDate, ok := h.moveStatus(w, r, current, req.ProjectID, req.Status)
+	if !ok {
+		return
+	}
+
+	if req.ParentTaskID != 0 && !h.checkParent(w, r, id, req.ProjectID, req.ParentTaskID) {
+		return
+	}
+
+	dueDate := parseDueDate(req.DueDate)
+	if !h.checkDueDate(w, r, req.ProjectID, dueDate) {
+		return
+	}
+
+	if req.MilestoneID != 0 && !h.checkMilestone(w, r, req.ProjectID, req.MilestoneID) {
+		return
+	}
+
+	task, err := h.db.UpdateTaskTx(r.Context(), db.UpdateTaskParams{
+		ID:             id,
+		Title:          req.Title,
+		Description:    req.Description,
+		Priority:       req.Priority,
+		Status:         req.Status,
+		AssigneeID:     req.AssigneeID,
+		ProjectID:      req.ProjectID,
+		CompletionDate: completionDate,
+		Version:        current.Version,
+		ParentTaskID:   sql.NullInt64{Int64: req.ParentTaskID, Valid: req.ParentTaskID != 0},
+		DueDate:        dueDate,
+		Estimate:       nullEstimate(req.Estimate),
+		MilestoneID:    sql.NullInt64{Int64: req.MilestoneID, Valid: req.MilestoneID != 0},
+	})
+	if err != nil {
+		updateError(w, r, err)
+		return
+	}
+
+	setETag(w, task.Version)
+	response.OK(w, r, task)
+}
+
+// patchTaskRequest is a merge patch of a task, absent fields are left
+// unchanged. A null parent_task_id makes the task a top level task, a null
+// due_date, estimate or milestone_id removes it. A task moved to another
+// project without a milestone_id leaves its milestone.
+type patchTaskRequest struct {
+	Title        optional[string]          `json:"title" swaggertype:"string"`
+	Description  optional[string]          `json:"description" swaggertype:"string"`
+	Priority     optional[db.TaskPriority] `json:"priority" swaggertype:"string"`
+	Status       optional[string]          `json:"status" swaggertype:"string"`
+	AssigneeID   optional[int64]           `json:"assignee_id" swaggertype:"integer"`
+	ProjectID    optional[int64]           `json:"project_id" swaggertype:"integer"`
+	ParentTaskID optional[int64]           `json:"parent_task_id" swaggertype:"integer"`
+	DueDate      optional[string]          `json:"due_date" swaggertype:"string"`
+	Estimate     optional[float64]         `json:"estimate" swaggertype:"number"`
+	MilestoneID  optional[int64]           `json:"milestone_id" swaggertype:"integer"`
+}
+
+// apply returns the task as it is after the patch and validates it
+func (req patchTaskRequest) apply(task db.Task) (db.Task, validation.Errors) {
+	var v validation.Validator
+	req.Title.required(&v, "title")
+	req.Description.required(&v, "description")
+	req.Priority.required(&v, "priority")
+	req.Status.required(&v, "status")
+	req.AssigneeID.required(&v, "assignee_id")
+	req.ProjectID.required(&v, "project_id")
+	if errs := v.Errors(); len(errs) > 0 {
+		return task, errs
+	}
+
+	task.Title = req.Title.or(task.Title)
+	task.Description = req.Description.or(task.Description)
+	task.Priority = req.Priority.or(task.Priority)
+	task.Status = req.Status.or(task.Status)
+	task.AssigneeID = req.AssigneeID.or(task.AssigneeID)
+	task.ProjectID = req.ProjectID.or(task.ProjectID)
+	if req.ParentTaskID.Set {
+		task.ParentTaskID = nullInt64(req.ParentTaskID)
+	}
+	if req.DueDate.Set {
+		task.DueDate = parseDueDate(req.DueDate.Value)
+	}
+	if req.Estimate.Set {
+		task.Estimate = nullEstimate(req.Estimate.Value)
+	}
+	if req.MilestoneID.Set {
+		task.MilestoneID = nullInt64(req.MilestoneID)
+	}
+
+	errs := validateTask(task.Title, task.Priority, task.Status, task.AssigneeID, task.ProjectID, task.ParentTaskID.Int64)
+	validateSchedule(&v, req.DueDate.Value, req.Estimate.Value)
+	v.Check(task.MilestoneID.Int64 >= 0, "milestone_id", "must be a positive id")
+	return task, append(errs, v.Errors()...)
+}
+
+// params converts the patch into the query parameters, leaving out the
+// fields that are not patched. The completion date follows a status or
+// project change.
+func (req patchTaskRequest) params(task db.Task, completionDate sql.NullTime) db.PatchTaskParams {
+	return db.PatchTaskParams{
+		ID:                task.ID,
+		Version:           task.Version,
+		Title:             nullString(req.Title),
+		Description:       nullString(req.Description),
+		Priority:          db.NullTaskPriority{TaskPriority: req.Priority.Value, Valid: req.Priority.present()},
+		Status:            nullString(req.Status),
+		AssigneeID:        nullInt64(req.AssigneeID),
+		ProjectID:         nullInt64(req.ProjectID),
+		SetParentTaskID:   req.ParentTaskID.Set,
+		ParentTaskID:      nullInt64(req.ParentTaskID),
+		SetCompletionDate: req.Status.present() || req.ProjectID.present(),
+		CompletionDate:    completionDate,
+		SetDueDate:        req.DueDate.Set,
+		DueDate:           parseDueDate(req.DueDate.Value),
+		SetEstimate:       req.Estimate.Set,
+		Estimate:          nullEstimate(req.Estimate.Value),
+		SetMilestoneID:    req.MilestoneID.Set,
+		MilestoneID:       nullInt64(req.MilestoneID),
+	}
+}
+
+// @Summary Partially update a task in the repository
+// @Description Applies a JSON Merge Patch (RFC 7396): only the given fields change. The status may only change along the workflow.
+// @Tags tasks
+// @Accept application/merge-patch+json
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Param force query bool false "Start the task even though it is blocked"
+// @Param request body patchTaskRequest true "Fields to change"
+// @Success 200 {object} db.Task
+// @Header 200 {string} ETag "Version of the task"
+// @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 409 {object} response.Object
+// @Failure 412 {object} response.Object
+// @Failure 415 {object} response.Object
+// @Failure 422 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id} [patch]
+func (h *TaskHandler) patch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req patchTaskRequest
+	if !decodePatch(w, r, &req) {
+		return
+	}
+
+	current, err := h.db.GetTask(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, current.ProjectID, 0) {
+		return
+	}
+
+	if !ifMatch(w, r, current.Version) {
+		return
+	}
+
+	patched, errs := req.apply(current)
+	if len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if req.ProjectID.present() || req.AssigneeID.present() {
+		if !h.authorizeWrite(w, r, patched.ProjectID, patched.AssigneeID) {
+			return
 		}
+	}
+
+	completionDate, ok := h.moveStatus(w, r, current, patched.ProjectID, patched.Status)
+	if !ok {
 		return
 	}
 
+	if (req.ParentTaskID.present() || req.ProjectID.present()) && patched.ParentTaskID.Valid {
+		if !h.checkParent(w, r, id, patched.ProjectID, patched.ParentTaskID.Int64) {
+			return
+		}
+	}
+
+	if (req.DueDate.present() || req.ProjectID.present()) && !h.checkDueDate(w, r, patched.ProjectID, patched.DueDate) {
+		return
+	}
+
+	if req.MilestoneID.present() && !h.checkMilestone(w, r, patched.ProjectID, patched.MilestoneID.Int64) {
+		return
+	}
+
+	task, err := h.db.PatchTaskTx(r.Context(), req.params(current, completionDate))
+	if err != nil {
+		updateError(w, r, err)
+		return
+	}
+
+	setETag(w, task.Version)
 	response.OK(w, r, task)
 }
 
@@ -232,8 +740,10 @@ func (h *TaskHandler) update(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Task ID"
 // @Success 204 {object} response.Object
+// @Failure 403 {object} response.Object
 // @Failure 404 {object} response.Object
 // @Failure 500 {object} response.Object
+// @Security BearerAuth
 // @Router /tasks/{id} [delete]
 func (h *TaskHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -242,14 +752,273 @@ func (h *TaskHandler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	task, err := h.db.GetTask(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	// The attachment rows go with the task, their content has to be removed
+	// from the blob store afterwards
+	attachments, err := h.db.ListTaskAttachments(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
 	if err := h.db.DeleteTask(r.Context(), id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		databaseError(w, r, err)
 		return
 	}
 
+	for _, attachment := range attachments {
+		h.removeBlob(r, attachment.StorageKey)
+	}
+
 	response.NoContent(w, r)
 }
+
+// @Summary List the transitions a task can take from its current status
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {array} workflow.Transition
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/transitions [get]
+func (h *TaskHandler) listTransitions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	task, err := h.db.GetTask(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	status, err := h.db.GetWorkflowStatus(r.Context(), db.GetWorkflowStatusParams{ProjectID: task.ProjectID, Name: task.Status})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, workflow.Available(status.Category))
+}
+
+// transitionRequest names the action to apply and optionally the status to
+// move to, which must belong to the category the action leads to. Force
+// starts a task even though it is blocked.
+type transitionRequest struct {
+	Action workflow.Action `json:"action"`
+	Status string          `json:"status"`
+	Force  bool            `json:"force"`
+}
+
+// @Summary Move a task along its workflow
+// @Description Applies a transition by action name: start, stop, complete or reopen. The task moves to the given status, or to the first status of the category the action leads to. Completing a task stamps its completion date, reopening clears it. A blocked task only becomes active when forced.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Param request body transitionRequest true "Transition"
+// @Success 200 {object} db.Task
+// @Header 200 {string} ETag "Version of the task"
+// @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 409 {object} response.Object
+// @Failure 412 {object} response.Object
+// @Failure 422 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/transitions [post]
+func (h *TaskHandler) transition(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req transitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	current, err := h.db.GetTask(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, current.ProjectID, 0) || !ifMatch(w, r, current.Version) {
+		return
+	}
+
+	wf, ok := h.workflow(w, r, current.ProjectID)
+	if !ok {
+		return
+	}
+
+	from, err := wf.Status(current.Status)
+	if err != nil {
+		workflowError(w, r, err)
+		return
+	}
+
+	to, err := wf.Apply(from, req.Action, req.Status)
+	if err == nil {
+		err = workflow.CanStart(current.Blocked, from.Category, to.Category, req.Force)
+	}
+	if err != nil {
+		workflowError(w, r, err)
+		return
+	}
+
+	task, err := h.db.PatchTask(r.Context(), db.PatchTaskParams{
+		ID:                id,
+		Version:           current.Version,
+		Status:            sql.NullString{String: to.Name, Valid: true},
+		SetCompletionDate: true,
+		CompletionDate:    workflow.CompletionDate(current.CompletionDate, from.Category, to.Category, time.Now()),
+	})
+	if err != nil {
+		updateError(w, r, err)
+		return
+	}
+
+	setETag(w, task.Version)
+	response.OK(w, r, task)
+}
+
+// workflow loads the statuses of a project, writing the error response and
+// returning false when they cannot be read
+func (h *TaskHandler) workflow(w http.ResponseWriter, r *http.Request, projectID int64) (workflow.Workflow, bool) {
+	statuses, err := h.db.ListWorkflowStatuses(r.Context(), projectID)
+	if err != nil {
+		databaseError(w, r, err)
+		return nil, false
+	}
+	return statuses, true
+}
+
+// moveStatus checks that the task may go to the named status of the project
+// workflow, starting a blocked task only when the request is forced, and
+// returns its completion date after the move, writing the error response and
+// returning false otherwise
+func (h *TaskHandler) moveStatus(w http.ResponseWriter, r *http.Request, task db.Task, projectID int64, name string) (sql.NullTime, bool) {
+	from, err := h.db.GetWorkflowStatus(r.Context(), db.GetWorkflowStatusParams{ProjectID: task.ProjectID, Name: task.Status})
+	if err != nil {
+		databaseError(w, r, err)
+		return sql.NullTime{}, false
+	}
+
+	wf, ok := h.workflow(w, r, projectID)
+	if !ok {
+		return sql.NullTime{}, false
+	}
+
+	to, err := wf.Status(name)
+	if err == nil {
+		err = workflow.CanMove(from, to)
+	}
+	if err == nil {
+		err = workflow.CanStart(task.Blocked, from.Category, to.Category, forced(r))
+	}
+	if err != nil {
+		workflowError(w, r, err)
+		return sql.NullTime{}, false
+	}
+
+	return workflow.CompletionDate(task.CompletionDate, from.Category, to.Category, time.Now()), true
+}
+
+// forced reports whether the request asks to start a task even though it
+// is blocked
+func forced(r *http.Request) bool {
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+	return force
+}
+
+// workflowError answers an error of the workflow: 422 for an unknown action
+// or status and 409 for a refused transition or a blocked task
+func workflowError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, workflow.ErrTaskBlocked):
+		response.Conflict(w, r, err, codeTaskBlocked)
+	case errors.Is(err, workflow.ErrUnknownAction):
+		invalidRequest(w, r, validation.Errors{{Field: "action", Message: err.Error()}})
+	case errors.Is(err, workflow.ErrUnknownStatus):
+		invalidRequest(w, r, validation.Errors{{Field: "status", Message: err.Error()}})
+	default:
+		response.Conflict(w, r, err, codeTransitionRefused)
+	}
+}
+
+// authorizeRead checks that the current user may see tasks of the project,
+// writing the error response and returning false otherwise
+func (h *TaskHandler) authorizeRead(w http.ResponseWriter, r *http.Request, projectID int64) bool {
+	actor, _ := UserFromContext(r.Context())
+
+	member, err := projectMember(r.Context(), h.db, projectID, actor.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return false
+	}
+
+	if err := policy.CanReadProject(actor, member); err != nil {
+		response.Forbidden(w, r, err)
+		return false
+	}
+
+	return true
+}
+
+// authorizeWrite checks that the current user may change tasks of the project
+// and, when assigneeID is set, that the assignee is a member of that project
+func (h *TaskHandler) authorizeWrite(w http.ResponseWriter, r *http.Request, projectID, assigneeID int64) bool {
+	actor, _ := UserFromContext(r.Context())
+
+	member, err := projectMember(r.Context(), h.db, projectID, actor.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return false
+	}
+
+	if err := policy.CanWriteProjectTasks(actor, member); err != nil {
+		response.Forbidden(w, r, err)
+		return false
+	}
+
+	if assigneeID == 0 {
+		return true
+	}
+
+	assignee, err := projectMember(r.Context(), h.db, projectID, assigneeID)
+	if err != nil {
+		databaseError(w, r, err)
+		return false
+	}
+
+	if assignee == nil {
+		invalidRequest(w, r, validation.Errors{{Field: "assignee_id", Message: "must be a member of the project"}})
+		return false
+	}
+
+	return true
+}
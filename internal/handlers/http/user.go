@@ -6,10 +6,15 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"project-management-service/db/sqlc"
+	"project-management-service/internal/policy"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/pagination"
 	"project-management-service/pkg/server/response"
+	"project-management-service/util"
 )
 
 type UserHandler struct {
@@ -32,52 +37,159 @@ func (h *UserHandler) Routes() chi.Router {
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
+		r.Patch("/", h.patch)
 		r.Delete("/", h.delete)
 		r.Get("/tasks", h.getTasks)
+		r.Get("/time", h.userTime)
+		r.Get("/workload", h.workload)
 	})
 
 	return r
 }
 
+// minPasswordLength is the shortest password accepted for new users
+const minPasswordLength = 8
+
+type createUserRequest struct {
+	FullName string      `json:"full_name"`
+	Email    string      `json:"email"`
+	Role     db.UserRole `json:"role"`
+	Password string      `json:"password"`
+}
+
+func (req createUserRequest) validate() validation.Errors {
+	var v validation.Validator
+	validateUser(&v, req.FullName, req.Email, req.Role)
+	v.Check(len(req.Password) >= minPasswordLength, "password", "must be at least 8 characters long")
+	return v.Errors()
+}
+
+// validateUser checks the fields shared by user creation and update
+func validateUser(v *validation.Validator, fullName, email string, role db.UserRole) {
+	v.Required("full_name", fullName, 255)
+	v.Email("email", email)
+	v.MaxLength("email", email, 320)
+	validation.OneOf(v, "role", role, db.AllUserRoleValues())
+}
+
+type userResponse struct {
+	ID               int64       `json:"id"`
+	FullName         string      `json:"full_name"`
+	Email            string      `json:"email"`
+	RegistrationDate time.Time   `json:"registration_date"`
+	Role             db.UserRole `json:"role"`
+	WeeklyCapacity   *float64    `json:"weekly_capacity"`
+	Version          int64       `json:"version"`
+}
+
+func newUserResponse(user db.User) userResponse {
+	rsp := userResponse{
+		ID:               user.ID,
+		FullName:         user.FullName,
+		Email:            user.Email,
+		RegistrationDate: user.RegistrationDate,
+		Role:             user.Role,
+		Version:          user.Version,
+	}
+	if user.WeeklyCapacity.Valid {
+		rsp.WeeklyCapacity = &user.WeeklyCapacity.Float64
+	}
+	return rsp
+}
+
+func newUserResponses(users []db.User) []userResponse {
+	rsp := make([]userResponse, 0, len(users))
+	for _, user := range users {
+		rsp = append(rsp, newUserResponse(user))
+	}
+	return rsp
+}
+
 // @Summary	List of users from the repository
 // @Tags		users
 // @Accept		json
 // @Produce	json
-// @Success	200	{array}		db.User
-// @Failure	500	{object}	response.Object
+// @Param		sort	query		string	false	"Sort order, e.g. -registration_date (full_name, email, registration_date, role, id)"
+// @Param		limit	query		int		false	"Page size (default 20, max 100)"
+// @Param		cursor	query		string	false	"Cursor returned as next_cursor by the previous page"
+// @Success	200		{array}		userResponse
+// @Failure	400		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
 // @Router		/users [get]
 func (h *UserHandler) list(w http.ResponseWriter, r *http.Request) {
-	users, err := h.db.ListUsers(r.Context())
+	sort, page, err := listPage(r, db.UserSortColumns, db.DefaultUserSort)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	users, err := h.db.SearchUsers(r.Context(), db.SearchUsersParams{
+		Sort:      sort,
+		After:     page.After(),
+		PageLimit: page.FetchLimit(),
+	})
+	if err != nil {
+		databaseError(w, r, err)
 		return
 	}
-	response.OK(w, r, users)
+
+	users, nextCursor := pagination.Trim(users, page, db.UserSortColumns.Values(sort))
+	response.Paginated(w, r, newUserResponses(users), nextCursor)
 }
 
 // @Summary	Add a new user to the repository
 // @Tags		users
 // @Accept		json
 // @Produce	json
-// @Param		request	body		db.CreateUserParams	true	"User details"
-// @Success	200		{object}	db.User
+// @Param		request	body		createUserRequest	true	"User details"
+// @Success	200		{object}	userResponse
 // @Failure	400		{object}	response.Object
+// @Failure	403		{object}	response.Object
+// @Failure	409		{object}	response.Object
+// @Failure	422		{object}	response.Object
 // @Failure	500		{object}	response.Object
+// @Security	BearerAuth
 // @Router		/users [post]
 func (h *UserHandler) add(w http.ResponseWriter, r *http.Request) {
-	var req db.CreateUserParams
+	actor, _ := UserFromContext(r.Context())
+	if err := policy.CanCreateUser(actor); err != nil {
+		response.Forbidden(w, r, err)
+		return
+	}
+
+	var req createUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		response.BadRequest(w, r, err, req)
+		response.BadRequest(w, r, err, nil)
 		return
 	}
 
-	user, err := h.db.CreateUser(r.Context(), req)
+	if errs := req.validate(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.Password)
 	if err != nil {
 		response.InternalServerError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, user)
+	params := db.CreateUserParams{
+		FullName:       req.FullName,
+		Email:          req.Email,
+		Role:           req.Role,
+		HashedPassword: hashedPassword,
+	}
+
+	user, err := h.db.CreateUser(r.Context(), params)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	setETag(w, user.Version)
+	response.OK(w, r, newUserResponse(user))
 }
 
 // @Summary	Get a user from the repository
@@ -85,9 +197,11 @@ func (h *UserHandler) add(w http.ResponseWriter, r *http.Request) {
 // @Accept		json
 // @Produce	json
 // @Param		id	path		int	true	"User ID"
-// @Success	200	{object}	db.User
+// @Success	200	{object}	userResponse
+// @Header		200	{string}	ETag	"Version of the user"
 // @Failure	404	{object}	response.Object
 // @Failure	500	{object}	response.Object
+// @Security	BearerAuth
 // @Router		/users/{id} [get]
 func (h *UserHandler) get(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -98,27 +212,31 @@ func (h *UserHandler) get(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.db.GetUser(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		databaseError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, user)
+	setETag(w, user.Version)
+	response.OK(w, r, newUserResponse(user))
 }
 
 // @Summary	Update a user in the repository
 // @Tags		users
 // @Accept		json
 // @Produce	json
-// @Param		id		path		int					true	"User ID"
-// @Param		request	body		db.UpdateUserParams	true	"User details"
-// @Success	200		{object}	db.User
-// @Failure	400		{object}	response.Object
-// @Failure	404		{object}	response.Object
-// @Failure	500	{object}	response.Object
+// @Param		id			path		int					true	"User ID"
+// @Param		If-Match	header		string				false	"ETag of the version being replaced"
+// @Param		request		body		db.UpdateUserParams	true	"User details"
+// @Success	200			{object}	userResponse
+// @Header		200			{string}	ETag	"Version of the user"
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	409			{object}	response.Object
+// @Failure	412			{object}	response.Object
+// @Failure	422			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
 // @Router		/users/{id} [put]
 func (h *UserHandler) update(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -133,19 +251,136 @@ func (h *UserHandler) update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var v validation.Validator
+	validateUser(&v, req.FullName, req.Email, req.Role)
+	if errs := v.Errors(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	target, err := h.db.GetUser(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	actor, _ := UserFromContext(r.Context())
+	if err := policy.CanUpdateUser(actor, target, req.Role); err != nil {
+		response.Forbidden(w, r, err)
+		return
+	}
+
+	if !ifMatch(w, r, target.Version) {
+		return
+	}
+
 	req.ID = id
+	req.Version = target.Version
 
 	user, err := h.db.UpdateUser(r.Context(), req)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		updateError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, user)
+	setETag(w, user.Version)
+	response.OK(w, r, newUserResponse(user))
+}
+
+// patchUserRequest is a merge patch of a user, absent fields are left
+// unchanged. The weekly capacity is in the unit of the task estimates, null
+// removes it.
+type patchUserRequest struct {
+	FullName       optional[string]      `json:"full_name" swaggertype:"string"`
+	Email          optional[string]      `json:"email" swaggertype:"string"`
+	Role           optional[db.UserRole] `json:"role" swaggertype:"string"`
+	WeeklyCapacity optional[float64]     `json:"weekly_capacity" swaggertype:"number"`
+}
+
+// params validates the user as it is after the patch and converts the patch
+// into the query parameters
+func (req patchUserRequest) params(user db.User) (db.PatchUserParams, validation.Errors) {
+	var v validation.Validator
+	req.FullName.required(&v, "full_name")
+	req.Email.required(&v, "email")
+	req.Role.required(&v, "role")
+	validateUser(&v, req.FullName.or(user.FullName), req.Email.or(user.Email), req.Role.or(user.Role))
+	v.Check(!req.WeeklyCapacity.present() || req.WeeklyCapacity.Value > 0, "weekly_capacity", "must be positive")
+
+	return db.PatchUserParams{
+		ID:                user.ID,
+		Version:           user.Version,
+		FullName:          nullString(req.FullName),
+		Email:             nullString(req.Email),
+		Role:              db.NullUserRole{UserRole: req.Role.Value, Valid: req.Role.present()},
+		SetWeeklyCapacity: req.WeeklyCapacity.Set,
+		WeeklyCapacity:    sql.NullFloat64{Float64: req.WeeklyCapacity.Value, Valid: req.WeeklyCapacity.present()},
+	}, v.Errors()
+}
+
+// @Summary	Partially update a user in the repository
+// @Description	Applies a JSON Merge Patch (RFC 7396): only the given fields change. A null weekly_capacity removes it.
+// @Tags		users
+// @Accept		application/merge-patch+json
+// @Accept		json
+// @Produce	json
+// @Param		id			path		int					true	"User ID"
+// @Param		If-Match	header		string				false	"ETag of the version being changed"
+// @Param		request		body		patchUserRequest	true	"Fields to change"
+// @Success	200			{object}	userResponse
+// @Header		200			{string}	ETag	"Version of the user"
+// @Failure	400			{object}	response.Object
+// @Failure	403			{object}	response.Object
+// @Failure	404			{object}	response.Object
+// @Failure	409			{object}	response.Object
+// @Failure	412			{object}	response.Object
+// @Failure	415			{object}	response.Object
+// @Failure	422			{object}	response.Object
+// @Failure	500			{object}	response.Object
+// @Security	BearerAuth
+// @Router		/users/{id} [patch]
+func (h *UserHandler) patch(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	var req patchUserRequest
+	if !decodePatch(w, r, &req) {
+		return
+	}
+
+	target, err := h.db.GetUser(r.Context(), id)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	actor, _ := UserFromContext(r.Context())
+	if err := policy.CanUpdateUser(actor, target, req.Role.or(target.Role)); err != nil {
+		response.Forbidden(w, r, err)
+		return
+	}
+
+	if !ifMatch(w, r, target.Version) {
+		return
+	}
+
+	params, errs := req.params(target)
+	if len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	user, err := h.db.PatchUser(r.Context(), params)
+	if err != nil {
+		updateError(w, r, err)
+		return
+	}
+
+	setETag(w, user.Version)
+	response.OK(w, r, newUserResponse(user))
 }
 
 // @Summary	Delete a user from the repository
@@ -154,8 +389,11 @@ func (h *UserHandler) update(w http.ResponseWriter, r *http.Request) {
 // @Produce	json
 // @Param		id	path		int	true	"User ID"
 // @Success	204	{object}	response.Object
+// @Failure	403	{object}	response.Object
 // @Failure	404	{object}	response.Object
+// @Failure	409	{object}	response.Object
 // @Failure	500	{object}	response.Object
+// @Security	BearerAuth
 // @Router		/users/{id} [delete]
 func (h *UserHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -164,12 +402,14 @@ func (h *UserHandler) delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	actor, _ := UserFromContext(r.Context())
+	if err := policy.CanDeleteUser(actor); err != nil {
+		response.Forbidden(w, r, err)
+		return
+	}
+
 	if err := h.db.DeleteUser(r.Context(), id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			response.NotFound(w, r, err)
-		} else {
-			response.InternalServerError(w, r, err)
-		}
+		databaseError(w, r, err)
 		return
 	}
 
@@ -180,10 +420,15 @@ func (h *UserHandler) delete(w http.ResponseWriter, r *http.Request) {
 // @Tags		users
 // @Accept		json
 // @Produce	json
-// @Param		id	path		int	true	"User ID"
-// @Success	200	{array}		db.Task
-// @Failure	404	{object}	response.Object
-// @Failure	500	{object}	response.Object
+// @Param		id		path		int		true	"User ID"
+// @Param		sort	query		string	false	"Sort order, e.g. -priority,creation_date (title, priority, status, assignee_id, project_id, creation_date, id)"
+// @Param		limit	query		int		false	"Page size (default 20, max 100)"
+// @Param		cursor	query		string	false	"Cursor returned as next_cursor by the previous page"
+// @Success	200		{array}		db.Task
+// @Failure	400		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
 // @Router		/users/{id}/tasks [get]
 func (h *UserHandler) getTasks(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
@@ -192,45 +437,131 @@ func (h *UserHandler) getTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tasks, err := h.db.GetUserTasks(r.Context(), id)
+	sort, page, err := listPage(r, db.TaskSortColumns, db.DefaultTaskSort)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		response.BadRequest(w, r, err, nil)
 		return
 	}
 
-	response.OK(w, r, tasks)
+	viewerID, viewerIsAdmin := viewerScope(r)
+
+	tasks, err := h.db.SearchTasks(r.Context(), db.SearchTasksParams{
+		AssigneeIDs:   []int64{id},
+		ViewerIsAdmin: viewerIsAdmin,
+		ViewerID:      viewerID,
+		Sort:          sort,
+		After:         page.After(),
+		PageLimit:     page.FetchLimit(),
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	tasks, nextCursor := pagination.Trim(tasks, page, db.TaskSortColumns.Values(sort))
+	response.Paginated(w, r, tasks, nextCursor)
 }
 
-// @Summary	Search users by name or email
+// @Summary	Search users by name and/or email
 // @Tags		users
 // @Accept		json
 // @Produce	json
 // @Param		name	query		string	false	"User name"
 // @Param		email	query		string	false	"User email"
-// @Success	200	{array}		db.User
+// @Param		sort	query		string	false	"Sort order, e.g. -registration_date (full_name, email, registration_date, role, id)"
+// @Param		limit	query		int		false	"Page size (default 20, max 100)"
+// @Param		cursor	query		string	false	"Cursor returned as next_cursor by the previous page"
+// @Success	200	{array}		userResponse
+// @Failure	400	{object}	response.Object
 // @Failure	500	{object}	response.Object
+// @Security	BearerAuth
 // @Router		/users/search [get]
 func (h *UserHandler) search(w http.ResponseWriter, r *http.Request) {
 	name := r.URL.Query().Get("name")
 	email := r.URL.Query().Get("email")
 
-	var users []db.User
-	var err error
-
-	switch {
-	case name != "":
-		users, err = h.db.SearchUsersByName(r.Context(), sql.NullString{String: name, Valid: true})
-	case email != "":
-		users, err = h.db.SearchUsersByEmail(r.Context(), sql.NullString{String: email, Valid: true})
-	default:
+	if name == "" && email == "" {
 		response.BadRequest(w, r, errors.New("missing query parameter"), nil)
 		return
 	}
 
+	params := db.SearchUsersParams{FullName: name, Email: email}
+
+	// a search by email alone is listed by email unless asked otherwise
+	def := db.DefaultUserSort
+	if name == "" {
+		def = db.Sort{{Column: "email"}, {Column: "id"}}
+	}
+
+	sort, page, err := listPage(r, db.UserSortColumns, def)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	params.Sort = sort
+	params.After = page.After()
+	params.PageLimit = page.FetchLimit()
+
+	users, err := h.db.SearchUsers(r.Context(), params)
 	if err != nil {
-		response.InternalServerError(w, r, err)
+		databaseError(w, r, err)
 		return
 	}
 
-	response.OK(w, r, users)
+	users, nextCursor := pagination.Trim(users, page, db.UserSortColumns.Values(sort))
+	response.Paginated(w, r, newUserResponses(users), nextCursor)
+}
+
+// @Summary	Get the time logged by a user
+// @Description	Sums the stopped worklogs of the user started within the range, per project. Only projects you are a member of are counted.
+// @Tags		worklogs
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int		true	"User ID"
+// @Param		from	query		string	false	"Started on or after (YYYY-MM-DD or RFC 3339)"
+// @Param		to		query		string	false	"Started on or before (YYYY-MM-DD or RFC 3339)"
+// @Success	200		{object}	userTimeResponse
+// @Failure	400		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/users/{id}/time [get]
+func (h *UserHandler) userTime(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	from, to, err := timeRange(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if _, err := h.db.GetUser(r.Context(), id); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	viewerID, viewerIsAdmin := viewerScope(r)
+
+	byProject, err := h.db.SumUserWorklogsByProject(r.Context(), db.SumUserWorklogsByProjectParams{
+		UserID:        id,
+		StartedFrom:   from,
+		StartedTo:     to,
+		ViewerIsAdmin: viewerIsAdmin,
+		ViewerID:      viewerID,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, userTimeResponse{
+		UserID:       id,
+		TotalSeconds: sumSeconds(byProject, func(row db.SumUserWorklogsByProjectRow) int64 { return row.Seconds }),
+		ByProject:    byProject,
+	})
 }
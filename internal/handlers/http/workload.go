@@ -0,0 +1,171 @@
+package http
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/report"
+	"project-management-service/pkg/server/response"
+)
+
+type ReportHandler struct {
+	db *db.Queries
+}
+
+func NewReportHandler(conn *sql.DB) *ReportHandler {
+	return &ReportHandler{
+		db: db.New(conn),
+	}
+}
+
+func (h *ReportHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/workload", h.workload)
+
+	return r
+}
+
+// workloadResponse is the open work assigned to a user across projects and
+// how it compares with their capacity
+type workloadResponse struct {
+	UserID         int64                  `json:"user_id"`
+	FullName       string                 `json:"full_name"`
+	WeeklyCapacity *float64               `json:"weekly_capacity"`
+	Projects       int64                  `json:"projects"`
+	OpenTasks      int64                  `json:"open_tasks"`
+	ByPriority     []report.PriorityCount `json:"by_priority"`
+	OpenEstimate   float64                `json:"open_estimate"`
+	Unestimated    int64                  `json:"unestimated"`
+	report.Allocation
+}
+
+func newWorkloadResponse(row db.ListWorkloadRow, weeks int) workloadResponse {
+	rsp := workloadResponse{
+		UserID:    row.UserID,
+		FullName:  row.FullName,
+		Projects:  row.Projects,
+		OpenTasks: row.OpenTasks,
+		ByPriority: []report.PriorityCount{
+			{Priority: db.TaskPriorityHigh, Tasks: row.High},
+			{Priority: db.TaskPriorityMedium, Tasks: row.Medium},
+			{Priority: db.TaskPriorityLow, Tasks: row.Low},
+		},
+		OpenEstimate: row.OpenEstimate,
+		Unestimated:  row.Unestimated,
+		Allocation:   report.NewAllocation(row.WeeklyCapacity, row.OpenEstimate, weeks),
+	}
+	if row.WeeklyCapacity.Valid {
+		rsp.WeeklyCapacity = &row.WeeklyCapacity.Float64
+	}
+	return rsp
+}
+
+// workloadWeeks reads the number of weeks open work is compared with the
+// weekly capacity over, one by default
+func workloadWeeks(r *http.Request) (int, error) {
+	v := r.URL.Query().Get("weeks")
+	if v == "" {
+		return 1, nil
+	}
+
+	weeks, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid weeks %q", v)
+	}
+	return weeks, report.CheckWeeks(weeks)
+}
+
+// @Summary	Get the workload of a user
+// @Description	Sums the open tasks assigned to the user by priority and estimate across the projects you are a member of. The user is over-allocated when the open estimate exceeds the weekly capacity times weeks. Unless you are an admin, the user must be yourself or a member of one of your projects.
+// @Tags		reports
+// @Accept		json
+// @Produce	json
+// @Param		id		path		int	true	"User ID"
+// @Param		weeks	query		int	false	"Weeks of capacity to compare with (default 1, max 52)"
+// @Success	200		{object}	workloadResponse
+// @Failure	400		{object}	response.Object
+// @Failure	404		{object}	response.Object
+// @Failure	500		{object}	response.Object
+// @Security	BearerAuth
+// @Router		/users/{id}/workload [get]
+func (h *UserHandler) workload(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	weeks, err := workloadWeeks(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	viewerID, viewerIsAdmin := viewerScope(r)
+
+	rows, err := h.db.ListWorkload(r.Context(), db.ListWorkloadParams{
+		ViewerIsAdmin: viewerIsAdmin,
+		ViewerID:      viewerID,
+		UserID:        sql.NullInt64{Int64: id, Valid: true},
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if len(rows) == 0 {
+		databaseError(w, r, sql.ErrNoRows)
+		return
+	}
+
+	response.OK(w, r, newWorkloadResponse(rows[0], weeks))
+}
+
+// @Summary	Get the workload of the team
+// @Description	Sums the open tasks of the members of your projects, or of every user for admins, by priority and estimate across the projects you are a member of, busiest first. Users are over-allocated when their open estimate exceeds their weekly capacity times weeks; users without a capacity never are.
+// @Tags		reports
+// @Accept		json
+// @Produce	json
+// @Param		weeks			query		int		false	"Weeks of capacity to compare with (default 1, max 52)"
+// @Param		over_allocated	query		bool	false	"Only list over-allocated users"
+// @Success	200				{array}		workloadResponse
+// @Failure	400				{object}	response.Object
+// @Failure	500				{object}	response.Object
+// @Security	BearerAuth
+// @Router		/reports/workload [get]
+func (h *ReportHandler) workload(w http.ResponseWriter, r *http.Request) {
+	weeks, err := workloadWeeks(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	overAllocated, _ := strconv.ParseBool(r.URL.Query().Get("over_allocated"))
+
+	viewerID, viewerIsAdmin := viewerScope(r)
+
+	rows, err := h.db.ListWorkload(r.Context(), db.ListWorkloadParams{
+		ViewerIsAdmin: viewerIsAdmin,
+		ViewerID:      viewerID,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	workloads := make([]workloadResponse, 0, len(rows))
+	for _, row := range rows {
+		rsp := newWorkloadResponse(row, weeks)
+		if overAllocated && !rsp.OverAllocated {
+			continue
+		}
+		workloads = append(workloads, rsp)
+	}
+
+	response.OK(w, r, workloads)
+}
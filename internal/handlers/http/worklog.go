@@ -0,0 +1,405 @@
+package http
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project-management-service/db/sqlc"
+	"project-management-service/internal/dberr"
+	"project-management-service/internal/policy"
+	"project-management-service/internal/validation"
+	"project-management-service/pkg/server/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// codeTimerRunning is the error code of starting a second timer
+const codeTimerRunning = "timer_running"
+
+// runningTimerIndex is the unique index that keeps a user to one timer
+const runningTimerIndex = "worklogs_running_timer_key"
+
+// Bounds of logged time
+const (
+	worklogMaxDuration   = 24 * 60 * 60
+	worklogNoteMaxLength = 1000
+)
+
+var (
+	errNoRunningTimer = errors.New("you have no timer running on this task")
+	errTimerRunning   = errors.New("a timer is already running, stop it first")
+)
+
+// worklogResponse is time logged on a task. Duration is in seconds and is
+// null while the worklog is a running timer.
+type worklogResponse struct {
+	ID        int64     `json:"id"`
+	TaskID    int64     `json:"task_id"`
+	UserID    int64     `json:"user_id"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  *int64    `json:"duration"`
+	Running   bool      `json:"running"`
+	Note      string    `json:"note"`
+}
+
+func newWorklogResponse(worklog db.Worklog) worklogResponse {
+	rsp := worklogResponse{
+		ID:        worklog.ID,
+		TaskID:    worklog.TaskID,
+		UserID:    worklog.UserID,
+		StartedAt: worklog.StartedAt,
+		Running:   !worklog.Duration.Valid,
+		Note:      worklog.Note,
+	}
+	if worklog.Duration.Valid {
+		rsp.Duration = &worklog.Duration.Int64
+	}
+	return rsp
+}
+
+// worklogRequest logs time spent on a task, the duration is in seconds
+type worklogRequest struct {
+	StartedAt time.Time `json:"started_at"`
+	Duration  int64     `json:"duration"`
+	Note      string    `json:"note"`
+}
+
+func (req worklogRequest) validate(now time.Time) validation.Errors {
+	var v validation.Validator
+	v.Check(!req.StartedAt.IsZero(), "started_at", "must not be empty")
+	v.Check(!req.StartedAt.After(now), "started_at", "must not be in the future")
+	v.Check(req.Duration > 0, "duration", "must be a positive number of seconds")
+	v.Check(req.Duration <= worklogMaxDuration, "duration", fmt.Sprintf("must be at most %d seconds", worklogMaxDuration))
+	v.MaxLength("note", req.Note, worklogNoteMaxLength)
+	return v.Errors()
+}
+
+// timerRequest optionally describes the work a timer is started for
+type timerRequest struct {
+	Note string `json:"note"`
+}
+
+// taskTimeResponse is the time logged on a task, in seconds
+type taskTimeResponse struct {
+	TaskID       int64                         `json:"task_id"`
+	TotalSeconds int64                         `json:"total_seconds"`
+	ByUser       []db.SumTaskWorklogsByUserRow `json:"by_user"`
+}
+
+// projectTimeResponse is the time logged on the tasks of a project, in
+// seconds
+type projectTimeResponse struct {
+	ProjectID    int64                            `json:"project_id"`
+	TotalSeconds int64                            `json:"total_seconds"`
+	ByTask       []db.SumProjectWorklogsByTaskRow `json:"by_task"`
+	ByUser       []db.SumProjectWorklogsByUserRow `json:"by_user"`
+}
+
+// userTimeResponse is the time a user logged, in seconds
+type userTimeResponse struct {
+	UserID       int64                            `json:"user_id"`
+	TotalSeconds int64                            `json:"total_seconds"`
+	ByProject    []db.SumUserWorklogsByProjectRow `json:"by_project"`
+}
+
+// sumSeconds adds up the time of the rows of a breakdown
+func sumSeconds[T any](rows []T, seconds func(T) int64) int64 {
+	var total int64
+	for _, row := range rows {
+		total += seconds(row)
+	}
+	return total
+}
+
+// timeRange reads the from and to query parameters, which bound the start
+// of the counted worklogs. They are converted to UTC, the time base of all
+// worklogs.
+func timeRange(r *http.Request) (from, to sql.NullTime, err error) {
+	if from, err = queryTime(r, "from", false); err != nil {
+		return from, to, err
+	}
+	if to, err = queryTime(r, "to", true); err != nil {
+		return from, to, err
+	}
+	from.Time, to.Time = from.Time.UTC(), to.Time.UTC()
+	return from, to, nil
+}
+
+// @Summary List the time logged on a task
+// @Description Running timers are included with a null duration.
+// @Tags worklogs
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {array} worklogResponse
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/worklogs [get]
+func (h *TaskHandler) listWorklogs(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	worklogs, err := h.db.ListTaskWorklogs(r.Context(), task.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	rsp := make([]worklogResponse, len(worklogs))
+	for i, worklog := range worklogs {
+		rsp[i] = newWorklogResponse(worklog)
+	}
+
+	response.OK(w, r, rsp)
+}
+
+// @Summary Log time spent on a task
+// @Tags worklogs
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body worklogRequest true "Time spent, the duration in seconds"
+// @Success 200 {object} worklogResponse
+// @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 422 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/worklogs [post]
+func (h *TaskHandler) addWorklog(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok {
+		return
+	}
+
+	var req worklogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	if errs := req.validate(time.Now()); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	actor, _ := UserFromContext(r.Context())
+
+	worklog, err := h.db.CreateWorklog(r.Context(), db.CreateWorklogParams{
+		TaskID:    task.ID,
+		UserID:    actor.ID,
+		StartedAt: req.StartedAt.UTC(),
+		Duration:  sql.NullInt64{Int64: req.Duration, Valid: true},
+		Note:      req.Note,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newWorklogResponse(worklog))
+}
+
+// @Summary Delete time logged on a task
+// @Description The user who logged the time, the project manager and admins can delete it.
+// @Tags worklogs
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param worklogID path int true "Worklog ID"
+// @Success 204 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/worklogs/{worklogID} [delete]
+func (h *TaskHandler) deleteWorklog(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	worklogID, err := strconv.ParseInt(chi.URLParam(r, "worklogID"), 10, 64)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	worklog, err := h.db.GetWorklog(r.Context(), db.GetWorklogParams{TaskID: task.ID, ID: worklogID})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	actor, _ := UserFromContext(r.Context())
+
+	member, err := projectMember(r.Context(), h.db, task.ProjectID, actor.ID)
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	if err := policy.CanDeleteWorklog(actor, member, worklog); err != nil {
+		response.Forbidden(w, r, err)
+		return
+	}
+
+	if err := h.db.DeleteWorklog(r.Context(), db.DeleteWorklogParams{TaskID: task.ID, ID: worklog.ID}); err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.NoContent(w, r)
+}
+
+// @Summary Start a timer on a task
+// @Description Starts logging time on the task until the timer is stopped. A user can only have one timer running, starting a second one fails with 409 and the code timer_running.
+// @Tags worklogs
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body timerRequest false "What the time is spent on"
+// @Success 200 {object} worklogResponse
+// @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 409 {object} response.Object
+// @Failure 422 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/timer/start [post]
+func (h *TaskHandler) startTimer(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok {
+		return
+	}
+
+	var req timerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.BadRequest(w, r, err, req)
+		return
+	}
+
+	var v validation.Validator
+	v.MaxLength("note", req.Note, worklogNoteMaxLength)
+	if errs := v.Errors(); len(errs) > 0 {
+		invalidRequest(w, r, errs)
+		return
+	}
+
+	if !h.authorizeWrite(w, r, task.ProjectID, 0) {
+		return
+	}
+
+	actor, _ := UserFromContext(r.Context())
+
+	// The running timer index allows one timer per user, concurrent starts
+	// cannot both succeed
+	worklog, err := h.db.StartTimer(r.Context(), db.StartTimerParams{TaskID: task.ID, UserID: actor.ID, Note: req.Note})
+	if dberr.IsUniqueViolation(err, runningTimerIndex) {
+		response.Conflict(w, r, errTimerRunning, codeTimerRunning)
+		return
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newWorklogResponse(worklog))
+}
+
+// @Summary Stop the timer running on a task
+// @Description Stops your timer on the task and logs the elapsed time.
+// @Tags worklogs
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} worklogResponse
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/timer/stop [post]
+func (h *TaskHandler) stopTimer(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok || !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	actor, _ := UserFromContext(r.Context())
+
+	worklog, err := h.db.StopTimer(r.Context(), db.StopTimerParams{TaskID: task.ID, UserID: actor.ID})
+	if errors.Is(err, sql.ErrNoRows) {
+		response.NotFound(w, r, errNoRunningTimer)
+		return
+	}
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, newWorklogResponse(worklog))
+}
+
+// @Summary Get the total time logged on a task
+// @Description Sums the stopped worklogs started within the range, per user. Running timers are not counted.
+// @Tags worklogs
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param from query string false "Started on or after (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "Started on or before (YYYY-MM-DD or RFC 3339)"
+// @Success 200 {object} taskTimeResponse
+// @Failure 400 {object} response.Object
+// @Failure 403 {object} response.Object
+// @Failure 404 {object} response.Object
+// @Failure 500 {object} response.Object
+// @Security BearerAuth
+// @Router /tasks/{id}/time [get]
+func (h *TaskHandler) taskTime(w http.ResponseWriter, r *http.Request) {
+	task, ok := h.pathTask(w, r)
+	if !ok {
+		return
+	}
+
+	from, to, err := timeRange(r)
+	if err != nil {
+		response.BadRequest(w, r, err, nil)
+		return
+	}
+
+	if !h.authorizeRead(w, r, task.ProjectID) {
+		return
+	}
+
+	byUser, err := h.db.SumTaskWorklogsByUser(r.Context(), db.SumTaskWorklogsByUserParams{
+		TaskID:      task.ID,
+		StartedFrom: from,
+		StartedTo:   to,
+	})
+	if err != nil {
+		databaseError(w, r, err)
+		return
+	}
+
+	response.OK(w, r, taskTimeResponse{
+		TaskID:       task.ID,
+		TotalSeconds: sumSeconds(byUser, func(row db.SumTaskWorklogsByUserRow) int64 { return row.Seconds }),
+		ByUser:       byUser,
+	})
+}
@@ -0,0 +1,71 @@
+package hierarchy
+
+import (
+	"project-management-service/db/sqlc"
+)
+
+// Progress is the completion rollup of a task: how many tasks sit below it
+// and how many of them are done. A task without subtasks is either 0 or 100
+// percent complete depending on its own status.
+type Progress struct {
+	Subtasks  int64 `json:"subtasks"`
+	Completed int64 `json:"completed"`
+	Percent   int   `json:"percent"`
+}
+
+// NewProgress computes the rollup of a task from the counts of its subtasks
+// at any depth and the category of its own status
+func NewProgress(subtasks, completed int64, category db.StatusCategory) Progress {
+	p := Progress{Subtasks: subtasks, Completed: completed}
+	switch {
+	case subtasks > 0:
+		p.Percent = int(completed * 100 / subtasks)
+	case category == db.StatusCategoryDone:
+		p.Percent = 100
+	}
+	return p
+}
+
+// Node is a task with its subtasks
+type Node struct {
+	db.Task
+	Category db.StatusCategory `json:"category"`
+	Progress Progress          `json:"progress"`
+	Subtasks []*Node           `json:"subtasks"`
+}
+
+// Build nests the rows of a subtree under the root task and rolls the
+// progress up to every node. Rows must come ordered by depth so that a
+// parent is always seen before its subtasks.
+func Build(root db.Task, category db.StatusCategory, rows []db.ListTaskSubtreeRow) *Node {
+	tree := &Node{Task: root, Category: category, Subtasks: []*Node{}}
+	byID := map[int64]*Node{root.ID: tree}
+
+	for _, row := range rows {
+		parent, ok := byID[row.Task.ParentTaskID.Int64]
+		if !ok {
+			continue
+		}
+		node := &Node{Task: row.Task, Category: row.Category, Subtasks: []*Node{}}
+		parent.Subtasks = append(parent.Subtasks, node)
+		byID[node.ID] = node
+	}
+
+	tree.rollup()
+	return tree
+}
+
+// rollup computes the progress of the node and of its subtasks and returns
+// the number of tasks below the node and how many of them are done
+func (n *Node) rollup() (subtasks, completed int64) {
+	for _, child := range n.Subtasks {
+		s, c := child.rollup()
+		subtasks += s + 1
+		completed += c
+		if child.Category == db.StatusCategoryDone {
+			completed++
+		}
+	}
+	n.Progress = NewProgress(subtasks, completed, n.Category)
+	return subtasks, completed
+}
@@ -0,0 +1,54 @@
+package hierarchy
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"project-management-service/db/sqlc"
+)
+
+func subtask(id, parentID int64, category db.StatusCategory) db.ListTaskSubtreeRow {
+	return db.ListTaskSubtreeRow{
+		Task:     db.Task{ID: id, ParentTaskID: sql.NullInt64{Int64: parentID, Valid: true}},
+		Category: category,
+	}
+}
+
+func TestNewProgress(t *testing.T) {
+	assert.Equal(t, Progress{Subtasks: 4, Completed: 1, Percent: 25}, NewProgress(4, 1, db.StatusCategoryActive))
+	assert.Equal(t, Progress{Subtasks: 3, Completed: 2, Percent: 66}, NewProgress(3, 2, db.StatusCategoryDone))
+	assert.Equal(t, 0, NewProgress(0, 0, db.StatusCategoryNew).Percent)
+	assert.Equal(t, 100, NewProgress(0, 0, db.StatusCategoryDone).Percent)
+}
+
+func TestBuild(t *testing.T) {
+	// 1
+	// ├── 2 (done)
+	// │   ├── 4 (done)
+	// │   └── 5
+	// └── 3
+	rows := []db.ListTaskSubtreeRow{
+		subtask(2, 1, db.StatusCategoryDone),
+		subtask(3, 1, db.StatusCategoryNew),
+		subtask(4, 2, db.StatusCategoryDone),
+		subtask(5, 2, db.StatusCategoryActive),
+	}
+
+	tree := Build(db.Task{ID: 1}, db.StatusCategoryActive, rows)
+
+	require.Len(t, tree.Subtasks, 2)
+	assert.Equal(t, Progress{Subtasks: 4, Completed: 2, Percent: 50}, tree.Progress)
+
+	first := tree.Subtasks[0]
+	assert.Equal(t, int64(2), first.ID)
+	require.Len(t, first.Subtasks, 2)
+	assert.Equal(t, Progress{Subtasks: 2, Completed: 1, Percent: 50}, first.Progress)
+	assert.Equal(t, 100, first.Subtasks[0].Progress.Percent)
+
+	leaf := tree.Subtasks[1]
+	assert.Empty(t, leaf.Subtasks)
+	assert.Equal(t, Progress{}, leaf.Progress)
+}
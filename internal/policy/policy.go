@@ -0,0 +1,150 @@
+package policy
+
+import (
+	"errors"
+
+	"project-management-service/db/sqlc"
+)
+
+// Errors returned when the actor is not allowed to perform an action
+var (
+	ErrAdminOnly       = errors.New("only admins can perform this action")
+	ErrReadOnly        = errors.New("viewers have read-only access")
+	ErrProjectManager  = errors.New("only the project manager or an admin can modify this project")
+	ErrProjectCreation = errors.New("only admins and managers can create projects")
+	ErrRoleChange      = errors.New("only admins can change user roles")
+	ErrUserUpdate      = errors.New("users can only update their own profile")
+	ErrNotMember       = errors.New("only project members can access this project")
+	ErrCommentAuthor   = errors.New("only the author can edit a comment")
+	ErrCommentDelete   = errors.New("only the author, the project manager or an admin can delete a comment")
+	ErrWorklogDelete   = errors.New("only the user who logged the time, the project manager or an admin can delete a worklog")
+)
+
+// CanCreateUser checks if the actor is allowed to create users
+func CanCreateUser(actor db.User) error {
+	if actor.Role != db.UserRoleAdmin {
+		return ErrAdminOnly
+	}
+	return nil
+}
+
+// CanUpdateUser checks if the actor is allowed to update the target user,
+// admins may update anyone while others may only edit themselves without
+// touching their role
+func CanUpdateUser(actor, target db.User, role db.UserRole) error {
+	if actor.Role == db.UserRoleAdmin {
+		return nil
+	}
+	if actor.Role == db.UserRoleViewer {
+		return ErrReadOnly
+	}
+	if actor.ID != target.ID {
+		return ErrUserUpdate
+	}
+	if role != target.Role {
+		return ErrRoleChange
+	}
+	return nil
+}
+
+// CanDeleteUser checks if the actor is allowed to delete users
+func CanDeleteUser(actor db.User) error {
+	if actor.Role != db.UserRoleAdmin {
+		return ErrAdminOnly
+	}
+	return nil
+}
+
+// CanCreateProject checks if the actor is allowed to create projects
+func CanCreateProject(actor db.User) error {
+	switch actor.Role {
+	case db.UserRoleAdmin, db.UserRoleManager:
+		return nil
+	case db.UserRoleViewer:
+		return ErrReadOnly
+	default:
+		return ErrProjectCreation
+	}
+}
+
+// CanManageProject checks if the actor is allowed to update or delete the project
+func CanManageProject(actor db.User, project db.Project) error {
+	if actor.Role == db.UserRoleAdmin {
+		return nil
+	}
+	if actor.Role == db.UserRoleViewer {
+		return ErrReadOnly
+	}
+	if project.ManagerID != actor.ID {
+		return ErrProjectManager
+	}
+	return nil
+}
+
+// CanWriteTask checks if the actor is allowed to create, update or delete tasks
+func CanWriteTask(actor db.User) error {
+	if actor.Role == db.UserRoleViewer || !actor.Role.Valid() {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// CanReadProject checks if the actor can see the project's tasks and members,
+// member is nil when the actor does not belong to the project
+func CanReadProject(actor db.User, member *db.ProjectMember) error {
+	if actor.Role == db.UserRoleAdmin || member != nil {
+		return nil
+	}
+	return ErrNotMember
+}
+
+// CanWriteProjectTasks checks if the actor can create, update or delete tasks
+// of the project, member is nil when the actor does not belong to the project
+func CanWriteProjectTasks(actor db.User, member *db.ProjectMember) error {
+	if err := CanWriteTask(actor); err != nil {
+		return err
+	}
+	if actor.Role == db.UserRoleAdmin {
+		return nil
+	}
+	if member == nil {
+		return ErrNotMember
+	}
+	if member.Role == db.ProjectRoleViewer {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// CanEditComment checks if the actor can change the text of a comment, which
+// only its author can do
+func CanEditComment(actor db.User, comment db.Comment) error {
+	if comment.AuthorID != actor.ID {
+		return ErrCommentAuthor
+	}
+	return nil
+}
+
+// CanDeleteComment checks if the actor can delete a comment, member is nil
+// when the actor does not belong to the project of the task
+func CanDeleteComment(actor db.User, member *db.ProjectMember, comment db.Comment) error {
+	if actor.Role == db.UserRoleAdmin || comment.AuthorID == actor.ID {
+		return nil
+	}
+	if member != nil && member.Role == db.ProjectRoleManager {
+		return nil
+	}
+	return ErrCommentDelete
+}
+
+// CanDeleteWorklog checks if the actor can delete logged time, member is nil
+// when the actor does not belong to the project of the task
+func CanDeleteWorklog(actor db.User, member *db.ProjectMember, worklog db.Worklog) error {
+	if actor.Role == db.UserRoleAdmin || worklog.UserID == actor.ID {
+		return nil
+	}
+	if member != nil && member.Role == db.ProjectRoleManager {
+		return nil
+	}
+	return ErrWorklogDelete
+}
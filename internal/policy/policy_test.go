@@ -0,0 +1,95 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"project-management-service/db/sqlc"
+)
+
+var (
+	admin   = db.User{ID: 1, Role: db.UserRoleAdmin}
+	manager = db.User{ID: 2, Role: db.UserRoleManager}
+	member  = db.User{ID: 3, Role: db.UserRoleMember}
+	viewer  = db.User{ID: 4, Role: db.UserRoleViewer}
+)
+
+func TestUserManagement(t *testing.T) {
+	assert.NoError(t, CanCreateUser(admin))
+	assert.ErrorIs(t, CanCreateUser(manager), ErrAdminOnly)
+	assert.ErrorIs(t, CanCreateUser(viewer), ErrAdminOnly)
+
+	assert.NoError(t, CanDeleteUser(admin))
+	assert.ErrorIs(t, CanDeleteUser(member), ErrAdminOnly)
+
+	assert.NoError(t, CanUpdateUser(admin, member, db.UserRoleManager))
+	assert.NoError(t, CanUpdateUser(member, member, db.UserRoleMember))
+	assert.ErrorIs(t, CanUpdateUser(member, member, db.UserRoleAdmin), ErrRoleChange)
+	assert.ErrorIs(t, CanUpdateUser(member, manager, db.UserRoleManager), ErrUserUpdate)
+	assert.ErrorIs(t, CanUpdateUser(viewer, viewer, db.UserRoleViewer), ErrReadOnly)
+}
+
+func TestProjectManagement(t *testing.T) {
+	project := db.Project{ID: 1, ManagerID: manager.ID}
+
+	assert.NoError(t, CanCreateProject(admin))
+	assert.NoError(t, CanCreateProject(manager))
+	assert.ErrorIs(t, CanCreateProject(member), ErrProjectCreation)
+	assert.ErrorIs(t, CanCreateProject(viewer), ErrReadOnly)
+
+	assert.NoError(t, CanManageProject(admin, project))
+	assert.NoError(t, CanManageProject(manager, project))
+	assert.ErrorIs(t, CanManageProject(db.User{ID: 5, Role: db.UserRoleManager}, project), ErrProjectManager)
+	assert.ErrorIs(t, CanManageProject(viewer, project), ErrReadOnly)
+}
+
+func TestCanWriteTask(t *testing.T) {
+	assert.NoError(t, CanWriteTask(admin))
+	assert.NoError(t, CanWriteTask(manager))
+	assert.NoError(t, CanWriteTask(member))
+	assert.ErrorIs(t, CanWriteTask(viewer), ErrReadOnly)
+	assert.ErrorIs(t, CanWriteTask(db.User{}), ErrReadOnly)
+}
+
+func TestProjectAccess(t *testing.T) {
+	contributor := &db.ProjectMember{ProjectID: 1, UserID: member.ID, Role: db.ProjectRoleMember}
+	observer := &db.ProjectMember{ProjectID: 1, UserID: member.ID, Role: db.ProjectRoleViewer}
+
+	assert.NoError(t, CanReadProject(admin, nil))
+	assert.NoError(t, CanReadProject(member, contributor))
+	assert.NoError(t, CanReadProject(viewer, observer))
+	assert.ErrorIs(t, CanReadProject(member, nil), ErrNotMember)
+
+	assert.NoError(t, CanWriteProjectTasks(admin, nil))
+	assert.NoError(t, CanWriteProjectTasks(member, contributor))
+	assert.ErrorIs(t, CanWriteProjectTasks(member, nil), ErrNotMember)
+	assert.ErrorIs(t, CanWriteProjectTasks(member, observer), ErrReadOnly)
+	assert.ErrorIs(t, CanWriteProjectTasks(viewer, contributor), ErrReadOnly)
+}
+
+func TestCommentModeration(t *testing.T) {
+	comment := db.Comment{ID: 1, TaskID: 1, AuthorID: member.ID}
+	lead := &db.ProjectMember{ProjectID: 1, UserID: manager.ID, Role: db.ProjectRoleManager}
+	contributor := &db.ProjectMember{ProjectID: 1, UserID: 5, Role: db.ProjectRoleMember}
+
+	assert.NoError(t, CanEditComment(member, comment))
+	assert.ErrorIs(t, CanEditComment(admin, comment), ErrCommentAuthor)
+	assert.ErrorIs(t, CanEditComment(manager, comment), ErrCommentAuthor)
+
+	assert.NoError(t, CanDeleteComment(member, nil, comment))
+	assert.NoError(t, CanDeleteComment(admin, nil, comment))
+	assert.NoError(t, CanDeleteComment(manager, lead, comment))
+	assert.ErrorIs(t, CanDeleteComment(db.User{ID: 5, Role: db.UserRoleMember}, contributor, comment), ErrCommentDelete)
+}
+
+func TestWorklogDeletion(t *testing.T) {
+	worklog := db.Worklog{ID: 1, TaskID: 1, UserID: member.ID}
+	lead := &db.ProjectMember{ProjectID: 1, UserID: manager.ID, Role: db.ProjectRoleManager}
+	contributor := &db.ProjectMember{ProjectID: 1, UserID: 5, Role: db.ProjectRoleMember}
+
+	assert.NoError(t, CanDeleteWorklog(member, nil, worklog))
+	assert.NoError(t, CanDeleteWorklog(admin, nil, worklog))
+	assert.NoError(t, CanDeleteWorklog(manager, lead, worklog))
+	assert.ErrorIs(t, CanDeleteWorklog(db.User{ID: 5, Role: db.UserRoleMember}, contributor, worklog), ErrWorklogDelete)
+}
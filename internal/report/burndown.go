@@ -0,0 +1,83 @@
+package report
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"project-management-service/db/sqlc"
+)
+
+// MaxDays is the longest period a chart covers
+const MaxDays = 366
+
+// Errors returned for a period a chart cannot cover
+var (
+	ErrPeriodOrder  = errors.New("the period must not end before it starts")
+	ErrPeriodLength = errors.New("the period must not be longer than 366 days")
+)
+
+const dateLayout = "2006-01-02"
+
+// Point is the work of a day: the tasks that existed at its end, how many of
+// them were done and what remained, as counts and as sums of estimates
+type Point struct {
+	Date              string  `json:"date" example:"2024-07-01"`
+	Tasks             int64   `json:"tasks"`
+	Completed         int64   `json:"completed"`
+	Remaining         int64   `json:"remaining"`
+	Estimate          float64 `json:"estimate"`
+	CompletedEstimate float64 `json:"completed_estimate"`
+	RemainingEstimate float64 `json:"remaining_estimate"`
+}
+
+// Period returns the first and last day a chart covers. Without from and to
+// it runs from start to end, or to today when end is still ahead; a period
+// that would end before it starts covers only its first day.
+func Period(start, end, today time.Time, from, to sql.NullTime) (time.Time, time.Time, error) {
+	first, last := Day(start), Day(end)
+	if t := Day(today); t.Before(last) {
+		last = t
+	}
+	if last.Before(first) {
+		last = first
+	}
+
+	if from.Valid {
+		first = Day(from.Time)
+	}
+	if to.Valid {
+		last = Day(to.Time)
+	}
+
+	if last.Before(first) {
+		return first, last, ErrPeriodOrder
+	}
+	if last.Sub(first) >= MaxDays*24*time.Hour {
+		return first, last, ErrPeriodLength
+	}
+	return first, last, nil
+}
+
+// Burndown turns the daily counts into the points of a burndown or burnup
+// chart
+func Burndown(rows []db.GetBurndownRow) []Point {
+	points := make([]Point, len(rows))
+	for i, row := range rows {
+		points[i] = Point{
+			Date:              row.Day.Format(dateLayout),
+			Tasks:             row.Tasks,
+			Completed:         row.Completed,
+			Remaining:         row.Tasks - row.Completed,
+			Estimate:          row.Estimate,
+			CompletedEstimate: row.CompletedEstimate,
+			RemainingEstimate: row.Estimate - row.CompletedEstimate,
+		}
+	}
+	return points
+}
+
+// Day drops the time of day, leaving the date in UTC
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
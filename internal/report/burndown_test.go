@@ -0,0 +1,57 @@
+package report
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"project-management-service/db/sqlc"
+)
+
+func date(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPeriod(t *testing.T) {
+	start, end := date(2024, 7, 1), date(2024, 7, 14)
+
+	first, last, err := Period(start, end, date(2024, 8, 1), sql.NullTime{}, sql.NullTime{})
+	assert.NoError(t, err)
+	assert.Equal(t, start, first)
+	assert.Equal(t, end, last)
+
+	// A running period ends today
+	_, last, err = Period(start, end, time.Date(2024, 7, 5, 15, 30, 0, 0, time.UTC), sql.NullTime{}, sql.NullTime{})
+	assert.NoError(t, err)
+	assert.Equal(t, date(2024, 7, 5), last)
+
+	// One that has not started yet covers its first day
+	first, last, err = Period(start, end, date(2024, 6, 1), sql.NullTime{}, sql.NullTime{})
+	assert.NoError(t, err)
+	assert.Equal(t, first, last)
+
+	first, last, err = Period(start, end, date(2024, 8, 1), sql.NullTime{Time: date(2024, 7, 3), Valid: true}, sql.NullTime{Time: date(2024, 7, 20), Valid: true})
+	assert.NoError(t, err)
+	assert.Equal(t, date(2024, 7, 3), first)
+	assert.Equal(t, date(2024, 7, 20), last)
+
+	_, _, err = Period(start, end, date(2024, 8, 1), sql.NullTime{Time: date(2024, 7, 3), Valid: true}, sql.NullTime{Time: date(2024, 7, 2), Valid: true})
+	assert.ErrorIs(t, err, ErrPeriodOrder)
+
+	_, _, err = Period(start, end, date(2024, 8, 1), sql.NullTime{Time: date(2023, 1, 1), Valid: true}, sql.NullTime{})
+	assert.ErrorIs(t, err, ErrPeriodLength)
+}
+
+func TestBurndown(t *testing.T) {
+	points := Burndown([]db.GetBurndownRow{
+		{Day: date(2024, 7, 1), Tasks: 4, Completed: 0, Estimate: 10},
+		{Day: date(2024, 7, 2), Tasks: 5, Completed: 2, Estimate: 12, CompletedEstimate: 4.5},
+	})
+
+	assert.Equal(t, []Point{
+		{Date: "2024-07-01", Tasks: 4, Remaining: 4, Estimate: 10, RemainingEstimate: 10},
+		{Date: "2024-07-02", Tasks: 5, Completed: 2, Remaining: 3, Estimate: 12, CompletedEstimate: 4.5, RemainingEstimate: 7.5},
+	}, points)
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"time"
+
+	"project-management-service/db/sqlc"
+)
+
+// Health tells whether the work of a project keeps up with its schedule
+type Health string
+
+const (
+	HealthOnTrack Health = "on_track"
+	HealthAtRisk  Health = "at_risk"
+	HealthLate    Health = "late"
+)
+
+// How many percentage points the completion may trail the elapsed time
+// before a project is at risk or late
+const (
+	riskMargin = 10
+	lateMargin = 25
+)
+
+// Progress compares the share of the tasks of a project that are done with
+// the share of its time that has passed
+type Progress struct {
+	Percent        int    `json:"percent"`
+	ElapsedPercent int    `json:"elapsed_percent"`
+	DaysRemaining  int    `json:"days_remaining"`
+	Health         Health `json:"health"`
+}
+
+// NewProgress computes the progress of a project running from start to end
+// as of today. A project is late once its end has passed with open tasks,
+// or when its completion trails the elapsed time by more than 25 points; it
+// is at risk from 10 points behind.
+func NewProgress(tasks, completed int64, start, end, today time.Time) Progress {
+	start, end, today = Day(start), Day(end), Day(today)
+
+	p := Progress{Health: HealthOnTrack}
+	if tasks > 0 {
+		p.Percent = int(completed * 100 / tasks)
+	}
+
+	switch {
+	case !today.After(start):
+		p.ElapsedPercent = 0
+	case !today.Before(end):
+		p.ElapsedPercent = 100
+	default:
+		p.ElapsedPercent = int(today.Sub(start) * 100 / end.Sub(start))
+	}
+
+	if today.Before(end) {
+		p.DaysRemaining = int(end.Sub(today).Hours() / 24)
+	}
+
+	if completed == tasks {
+		return p
+	}
+
+	switch behind := p.ElapsedPercent - p.Percent; {
+	case today.After(end), behind > lateMargin:
+		p.Health = HealthLate
+	case behind > riskMargin:
+		p.Health = HealthAtRisk
+	}
+	return p
+}
+
+// PriorityCount is the number of tasks of one priority
+type PriorityCount struct {
+	Priority db.TaskPriority `json:"priority"`
+	Tasks    int64           `json:"tasks"`
+}
+
+// PriorityCounts lists the task counts of every priority, highest first,
+// including those without tasks
+func PriorityCounts(rows []db.CountProjectTasksByPriorityRow) []PriorityCount {
+	tasks := make(map[db.TaskPriority]int64, len(rows))
+	for _, row := range rows {
+		tasks[row.Priority] = row.Tasks
+	}
+
+	priorities := db.AllTaskPriorityValues()
+	counts := make([]PriorityCount, len(priorities))
+	for i, priority := range priorities {
+		counts[len(priorities)-1-i] = PriorityCount{Priority: priority, Tasks: tasks[priority]}
+	}
+	return counts
+}
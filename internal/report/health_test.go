@@ -0,0 +1,41 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"project-management-service/db/sqlc"
+)
+
+func TestNewProgress(t *testing.T) {
+	start, end := date(2024, 7, 1), date(2024, 7, 11)
+
+	// Halfway through with half of the work done
+	assert.Equal(t, Progress{Percent: 50, ElapsedPercent: 50, DaysRemaining: 5, Health: HealthOnTrack},
+		NewProgress(10, 5, start, end, date(2024, 7, 6)))
+
+	assert.Equal(t, HealthAtRisk, NewProgress(10, 3, start, end, date(2024, 7, 6)).Health)
+	assert.Equal(t, HealthLate, NewProgress(10, 2, start, end, date(2024, 7, 6)).Health)
+
+	// Not started yet
+	assert.Equal(t, Progress{DaysRemaining: 21, Health: HealthOnTrack}, NewProgress(4, 0, start, end, date(2024, 6, 20)))
+
+	// Past the end with open tasks, or with all of them done
+	assert.Equal(t, Progress{Percent: 90, ElapsedPercent: 100, Health: HealthLate}, NewProgress(10, 9, start, end, date(2024, 7, 12)))
+	assert.Equal(t, HealthOnTrack, NewProgress(10, 10, start, end, date(2024, 7, 12)).Health)
+	assert.Equal(t, HealthOnTrack, NewProgress(0, 0, start, end, date(2024, 7, 12)).Health)
+}
+
+func TestPriorityCounts(t *testing.T) {
+	counts := PriorityCounts([]db.CountProjectTasksByPriorityRow{
+		{Priority: db.TaskPriorityLow, Tasks: 4},
+		{Priority: db.TaskPriorityHigh, Tasks: 1},
+	})
+
+	assert.Equal(t, []PriorityCount{
+		{Priority: db.TaskPriorityHigh, Tasks: 1},
+		{Priority: db.TaskPriorityMedium, Tasks: 0},
+		{Priority: db.TaskPriorityLow, Tasks: 4},
+	}, counts)
+}
@@ -0,0 +1,43 @@
+package report
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// MaxWeeks is the longest horizon open work is compared with capacity over
+const MaxWeeks = 52
+
+// ErrWeeks is returned for a horizon outside 1 to MaxWeeks weeks
+var ErrWeeks = errors.New("weeks must be between 1 and 52")
+
+// Allocation compares the open work of a user with the capacity they have
+// over a number of weeks. Users without a weekly capacity have no load.
+type Allocation struct {
+	Capacity      float64 `json:"capacity"`
+	LoadPercent   int     `json:"load_percent"`
+	OverAllocated bool    `json:"over_allocated"`
+}
+
+// NewAllocation computes the allocation of open work, as a sum of task
+// estimates, against a weekly capacity over weeks weeks
+func NewAllocation(weeklyCapacity sql.NullFloat64, openEstimate float64, weeks int) Allocation {
+	if !weeklyCapacity.Valid || weeklyCapacity.Float64 <= 0 {
+		return Allocation{}
+	}
+
+	capacity := weeklyCapacity.Float64 * float64(weeks)
+	return Allocation{
+		Capacity:      capacity,
+		LoadPercent:   int(openEstimate * 100 / capacity),
+		OverAllocated: openEstimate > capacity,
+	}
+}
+
+// CheckWeeks checks that the horizon is within 1 to MaxWeeks weeks
+func CheckWeeks(weeks int) error {
+	if weeks < 1 || weeks > MaxWeeks {
+		return ErrWeeks
+	}
+	return nil
+}
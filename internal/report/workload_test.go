@@ -0,0 +1,29 @@
+package report
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAllocation(t *testing.T) {
+	capacity := sql.NullFloat64{Float64: 30, Valid: true}
+
+	assert.Equal(t, Allocation{Capacity: 30, LoadPercent: 50}, NewAllocation(capacity, 15, 1))
+	assert.Equal(t, Allocation{Capacity: 30, LoadPercent: 150, OverAllocated: true}, NewAllocation(capacity, 45, 1))
+	assert.Equal(t, Allocation{Capacity: 60, LoadPercent: 75}, NewAllocation(capacity, 45, 2))
+
+	// Exactly full is not over-allocated
+	assert.False(t, NewAllocation(capacity, 30, 1).OverAllocated)
+
+	// Without a capacity nobody is over-allocated
+	assert.Equal(t, Allocation{}, NewAllocation(sql.NullFloat64{}, 100, 1))
+}
+
+func TestCheckWeeks(t *testing.T) {
+	assert.NoError(t, CheckWeeks(1))
+	assert.NoError(t, CheckWeeks(MaxWeeks))
+	assert.ErrorIs(t, CheckWeeks(0), ErrWeeks)
+	assert.ErrorIs(t, CheckWeeks(53), ErrWeeks)
+}
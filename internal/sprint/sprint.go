@@ -0,0 +1,67 @@
+package sprint
+
+import (
+	"database/sql"
+	"errors"
+
+	"project-management-service/db/sqlc"
+)
+
+// Errors returned when a sprint cannot change in its current state
+var (
+	ErrNotPlanned  = errors.New("only a planned sprint can be started")
+	ErrNotActive   = errors.New("only the active sprint can be closed")
+	ErrClosed      = errors.New("a closed sprint cannot be changed")
+	ErrDelete      = errors.New("only a planned sprint can be deleted")
+	ErrCarryOver   = errors.New("unfinished tasks can only move to another planned sprint of the project")
+	ErrAlreadyOpen = errors.New("the project already has an active sprint")
+)
+
+// CanStart checks that the sprint is planned, the caller checks that no
+// other sprint of the project is active
+func CanStart(s db.Sprint) error {
+	if s.State != db.SprintStatePlanned {
+		return ErrNotPlanned
+	}
+	return nil
+}
+
+// CanClose checks that the sprint is the active one
+func CanClose(s db.Sprint) error {
+	if s.State != db.SprintStateActive {
+		return ErrNotActive
+	}
+	return nil
+}
+
+// CanChange checks that the details or the tasks of the sprint may still
+// change, which they may until it is closed
+func CanChange(s db.Sprint) error {
+	if s.State == db.SprintStateClosed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// CanDelete checks that the sprint has not started yet
+func CanDelete(s db.Sprint) error {
+	if s.State != db.SprintStatePlanned {
+		return ErrDelete
+	}
+	return nil
+}
+
+// CanCarryOver checks that the unfinished tasks of the closing sprint may
+// move to next
+func CanCarryOver(closing, next db.Sprint) error {
+	if next.ProjectID != closing.ProjectID || next.ID == closing.ID || next.State != db.SprintStatePlanned {
+		return ErrCarryOver
+	}
+	return nil
+}
+
+// OverCapacity reports whether the estimates committed to a sprint exceed
+// its capacity, a sprint without capacity never is
+func OverCapacity(capacity sql.NullFloat64, committed float64) bool {
+	return capacity.Valid && committed > capacity.Float64
+}
@@ -0,0 +1,49 @@
+package sprint
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"project-management-service/db/sqlc"
+)
+
+var (
+	planned = db.Sprint{ID: 1, ProjectID: 1, State: db.SprintStatePlanned}
+	active  = db.Sprint{ID: 2, ProjectID: 1, State: db.SprintStateActive}
+	closed  = db.Sprint{ID: 3, ProjectID: 1, State: db.SprintStateClosed}
+)
+
+func TestLifecycle(t *testing.T) {
+	assert.NoError(t, CanStart(planned))
+	assert.ErrorIs(t, CanStart(active), ErrNotPlanned)
+	assert.ErrorIs(t, CanStart(closed), ErrNotPlanned)
+
+	assert.NoError(t, CanClose(active))
+	assert.ErrorIs(t, CanClose(planned), ErrNotActive)
+	assert.ErrorIs(t, CanClose(closed), ErrNotActive)
+
+	assert.NoError(t, CanChange(planned))
+	assert.NoError(t, CanChange(active))
+	assert.ErrorIs(t, CanChange(closed), ErrClosed)
+
+	assert.NoError(t, CanDelete(planned))
+	assert.ErrorIs(t, CanDelete(active), ErrDelete)
+	assert.ErrorIs(t, CanDelete(closed), ErrDelete)
+}
+
+func TestCanCarryOver(t *testing.T) {
+	assert.NoError(t, CanCarryOver(active, planned))
+	assert.ErrorIs(t, CanCarryOver(active, active), ErrCarryOver)
+	assert.ErrorIs(t, CanCarryOver(active, closed), ErrCarryOver)
+
+	other := db.Sprint{ID: 4, ProjectID: 2, State: db.SprintStatePlanned}
+	assert.ErrorIs(t, CanCarryOver(active, other), ErrCarryOver)
+}
+
+func TestOverCapacity(t *testing.T) {
+	assert.False(t, OverCapacity(sql.NullFloat64{}, 40))
+	assert.False(t, OverCapacity(sql.NullFloat64{Float64: 40, Valid: true}, 40))
+	assert.True(t, OverCapacity(sql.NullFloat64{Float64: 40, Valid: true}, 42.5))
+}
@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Code is the machine-readable error code of a request failing validation
+const Code = "validation_failed"
+
+// FieldError describes why the value of a request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors is the list of field errors of a request, it is nil when the
+// request is valid
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + ": " + fe.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Validator collects field errors, only the first error of a field is kept
+type Validator struct {
+	errs Errors
+}
+
+// Errors returns the collected field errors
+func (v *Validator) Errors() Errors {
+	return v.errs
+}
+
+// Check adds the message for the field unless ok holds
+func (v *Validator) Check(ok bool, field, message string) {
+	if ok {
+		return
+	}
+	for _, fe := range v.errs {
+		if fe.Field == field {
+			return
+		}
+	}
+	v.errs = append(v.errs, FieldError{Field: field, Message: message})
+}
+
+// Required checks that a text field is not blank and fits in max characters
+func (v *Validator) Required(field, value string, max int) {
+	v.Check(strings.TrimSpace(value) != "", field, "must not be empty")
+	v.MaxLength(field, value, max)
+}
+
+// MaxLength checks that a text field fits in max characters
+func (v *Validator) MaxLength(field, value string, max int) {
+	v.Check(utf8.RuneCountInString(value) <= max, field, fmt.Sprintf("must be at most %d characters long", max))
+}
+
+// ID checks that a reference to another record is set
+func (v *Validator) ID(field string, id int64) {
+	v.Check(id > 0, field, "must be a positive id")
+}
+
+// OneOf checks that value is one of the allowed values, listing them in the
+// message otherwise
+func OneOf[T ~string](v *Validator, field string, value T, allowed []T) {
+	values := make([]string, len(allowed))
+	for i, a := range allowed {
+		values[i] = string(a)
+	}
+	v.Check(slices.Contains(allowed, value), field, "must be one of "+strings.Join(values, ", "))
+}
+
+// Email checks that the field holds a bare email address
+func (v *Validator) Email(field, value string) {
+	addr, err := mail.ParseAddress(value)
+	v.Check(err == nil && addr.Address == value, field, "must be a valid email address")
+}
+
+// Date parses a YYYY-MM-DD field, recording an error when it is malformed
+func (v *Validator) Date(field, value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	v.Check(err == nil, field, "must be a date in YYYY-MM-DD format")
+	return t
+}
+
+// NotBefore checks that the end of a period does not precede its start
+func (v *Validator) NotBefore(field string, end, start time.Time, startField string) {
+	if end.IsZero() || start.IsZero() {
+		return
+	}
+	v.Check(!end.Before(start), field, "must not be before "+startField)
+}
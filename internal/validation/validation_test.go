@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatorValid(t *testing.T) {
+	var v Validator
+	v.Required("title", "Write docs", 255)
+	v.ID("assignee_id", 3)
+	v.Email("email", "alice@example.com")
+	start := v.Date("start_date", "2024-07-01")
+	end := v.Date("end_date", "2024-07-19")
+	v.NotBefore("end_date", end, start, "start_date")
+
+	assert.Nil(t, v.Errors())
+}
+
+func TestValidatorErrors(t *testing.T) {
+	var v Validator
+	v.Required("title", "   ", 255)
+	v.MaxLength("title", "way too long", 3)
+	v.ID("assignee_id", 0)
+	v.Email("email", "Alice <alice@example.com>")
+	OneOf(&v, "priority", "urgent", []string{"low", "medium", "high"})
+	start := v.Date("start_date", "2024-07-19")
+	end := v.Date("end_date", "2024-07-01")
+	v.NotBefore("end_date", end, start, "start_date")
+	v.Date("due_date", "19.07.2024")
+
+	errs := v.Errors()
+	require.Len(t, errs, 6)
+	assert.Equal(t, FieldError{Field: "title", Message: "must not be empty"}, errs[0])
+	assert.Equal(t, "assignee_id", errs[1].Field)
+	assert.Equal(t, "email", errs[2].Field)
+	assert.Equal(t, FieldError{Field: "priority", Message: "must be one of low, medium, high"}, errs[3])
+	assert.Equal(t, FieldError{Field: "end_date", Message: "must not be before start_date"}, errs[4])
+	assert.Equal(t, "due_date", errs[5].Field)
+	assert.Contains(t, errs.Error(), "title: must not be empty")
+}
+
+func TestNotBeforeSkipsMissingDates(t *testing.T) {
+	var v Validator
+	v.NotBefore("end_date", time.Time{}, time.Now(), "start_date")
+	assert.Nil(t, v.Errors())
+}
@@ -0,0 +1,161 @@
+package workflow
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"project-management-service/db/sqlc"
+)
+
+// Errors returned when a task cannot move to another status
+var (
+	ErrUnknownAction     = errors.New("unknown transition action")
+	ErrUnknownStatus     = errors.New("not a status of the project workflow")
+	ErrTransitionRefused = errors.New("transition not allowed")
+	ErrTaskBlocked       = errors.New("task is blocked by unfinished tasks")
+)
+
+// Action names a transition clients can request
+type Action string
+
+// Actions moving a task through its workflow
+const (
+	ActionStart    Action = "start"
+	ActionStop     Action = "stop"
+	ActionComplete Action = "complete"
+	ActionReopen   Action = "reopen"
+)
+
+// Transition moves a task from a status in any of the From categories to a
+// status of the To category
+type Transition struct {
+	Action Action              `json:"action"`
+	From   []db.StatusCategory `json:"from"`
+	To     db.StatusCategory   `json:"to"`
+}
+
+// Transitions lists every allowed move between status categories. Moving
+// between statuses of the same category is always allowed.
+var Transitions = []Transition{
+	{Action: ActionStart, From: []db.StatusCategory{db.StatusCategoryNew}, To: db.StatusCategoryActive},
+	{Action: ActionStop, From: []db.StatusCategory{db.StatusCategoryActive}, To: db.StatusCategoryNew},
+	{Action: ActionComplete, From: []db.StatusCategory{db.StatusCategoryNew, db.StatusCategoryActive}, To: db.StatusCategoryDone},
+	{Action: ActionReopen, From: []db.StatusCategory{db.StatusCategoryDone}, To: db.StatusCategoryActive},
+}
+
+func (t Transition) allows(from db.StatusCategory) bool {
+	for _, category := range t.From {
+		if category == from {
+			return true
+		}
+	}
+	return false
+}
+
+// Workflow is the list of statuses of a project, ordered by position
+type Workflow []db.WorkflowStatus
+
+// Status looks up a status of the workflow by name
+func (wf Workflow) Status(name string) (db.WorkflowStatus, error) {
+	for _, s := range wf {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return db.WorkflowStatus{}, fmt.Errorf("%q is %w", name, ErrUnknownStatus)
+}
+
+// first returns the first status of a category
+func (wf Workflow) first(category db.StatusCategory) (db.WorkflowStatus, error) {
+	for _, s := range wf {
+		if s.Category == category {
+			return s, nil
+		}
+	}
+	return db.WorkflowStatus{}, fmt.Errorf("%w: the workflow has no %s status", ErrTransitionRefused, category)
+}
+
+// Initial returns the status new tasks start in, the first one of the new
+// category
+func (wf Workflow) Initial() (db.WorkflowStatus, error) {
+	return wf.first(db.StatusCategoryNew)
+}
+
+// Apply returns the status a task ends up in after the action. The target
+// status may be named, it must then belong to the category the action leads
+// to. Otherwise the first status of that category is taken.
+func (wf Workflow) Apply(from db.WorkflowStatus, action Action, target string) (db.WorkflowStatus, error) {
+	for _, t := range Transitions {
+		if t.Action != action {
+			continue
+		}
+		if !t.allows(from.Category) {
+			return from, fmt.Errorf("%w: cannot %s a task that is %s", ErrTransitionRefused, action, from.Name)
+		}
+		if target == "" {
+			return wf.first(t.To)
+		}
+
+		to, err := wf.Status(target)
+		if err != nil {
+			return from, err
+		}
+		if to.Category != t.To {
+			return from, fmt.Errorf("%w: %s does not lead to %s", ErrTransitionRefused, action, to.Name)
+		}
+		return to, nil
+	}
+	return from, fmt.Errorf("%w %q", ErrUnknownAction, action)
+}
+
+// CanMove checks that a task may go straight from one status to another,
+// which is the case within a category or when some action links the
+// categories
+func CanMove(from, to db.WorkflowStatus) error {
+	if from.Category == to.Category {
+		return nil
+	}
+	for _, t := range Transitions {
+		if t.To == to.Category && t.allows(from.Category) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: a task cannot go from %s to %s", ErrTransitionRefused, from.Name, to.Name)
+}
+
+// CanStart checks that a blocked task only becomes active when the move is
+// forced. Moves within the active category do not start the task again.
+func CanStart(blocked bool, from, to db.StatusCategory, force bool) error {
+	if blocked && !force && to == db.StatusCategoryActive && from != db.StatusCategoryActive {
+		return fmt.Errorf("%w, force the move to start it anyway", ErrTaskBlocked)
+	}
+	return nil
+}
+
+// Available returns the transitions a task in a status of the given
+// category can take
+func Available(from db.StatusCategory) []Transition {
+	available := []Transition{}
+	for _, t := range Transitions {
+		if t.allows(from) {
+			available = append(available, t)
+		}
+	}
+	return available
+}
+
+// CompletionDate returns the completion date of a task moving between
+// status categories: it is stamped when the task gets done, cleared when
+// the task is reopened and kept otherwise
+func CompletionDate(current sql.NullTime, from, to db.StatusCategory, now time.Time) sql.NullTime {
+	switch {
+	case to != db.StatusCategoryDone:
+		return sql.NullTime{}
+	case from != db.StatusCategoryDone || !current.Valid:
+		return sql.NullTime{Time: now, Valid: true}
+	default:
+		return current
+	}
+}
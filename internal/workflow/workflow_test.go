@@ -0,0 +1,93 @@
+package workflow
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"project-management-service/db/sqlc"
+)
+
+var (
+	todo   = db.WorkflowStatus{ID: 1, Name: "todo", Category: db.StatusCategoryNew}
+	doing  = db.WorkflowStatus{ID: 2, Name: "doing", Category: db.StatusCategoryActive}
+	review = db.WorkflowStatus{ID: 3, Name: "review", Category: db.StatusCategoryActive}
+	done   = db.WorkflowStatus{ID: 4, Name: "done", Category: db.StatusCategoryDone}
+
+	board = Workflow{todo, doing, review, done}
+)
+
+func TestApply(t *testing.T) {
+	status, err := board.Apply(todo, ActionStart, "")
+	assert.NoError(t, err)
+	assert.Equal(t, doing, status)
+
+	status, err = board.Apply(todo, ActionStart, "review")
+	assert.NoError(t, err)
+	assert.Equal(t, review, status)
+
+	status, err = board.Apply(review, ActionComplete, "")
+	assert.NoError(t, err)
+	assert.Equal(t, done, status)
+
+	status, err = board.Apply(done, ActionReopen, "")
+	assert.NoError(t, err)
+	assert.Equal(t, doing, status)
+
+	_, err = board.Apply(done, ActionStart, "")
+	assert.ErrorIs(t, err, ErrTransitionRefused)
+
+	_, err = board.Apply(todo, ActionStart, "done")
+	assert.ErrorIs(t, err, ErrTransitionRefused)
+
+	_, err = board.Apply(todo, ActionStart, "qa")
+	assert.ErrorIs(t, err, ErrUnknownStatus)
+
+	_, err = board.Apply(todo, Action("archive"), "")
+	assert.ErrorIs(t, err, ErrUnknownAction)
+}
+
+func TestInitial(t *testing.T) {
+	status, err := board.Initial()
+	assert.NoError(t, err)
+	assert.Equal(t, todo, status)
+
+	_, err = Workflow{doing, done}.Initial()
+	assert.ErrorIs(t, err, ErrTransitionRefused)
+}
+
+func TestCanMove(t *testing.T) {
+	assert.NoError(t, CanMove(doing, review))
+	assert.NoError(t, CanMove(todo, done))
+	assert.NoError(t, CanMove(review, todo))
+	assert.ErrorIs(t, CanMove(done, todo), ErrTransitionRefused)
+}
+
+func TestCanStart(t *testing.T) {
+	assert.ErrorIs(t, CanStart(true, db.StatusCategoryNew, db.StatusCategoryActive, false), ErrTaskBlocked)
+	assert.ErrorIs(t, CanStart(true, db.StatusCategoryDone, db.StatusCategoryActive, false), ErrTaskBlocked)
+	assert.NoError(t, CanStart(true, db.StatusCategoryNew, db.StatusCategoryActive, true))
+	assert.NoError(t, CanStart(true, db.StatusCategoryActive, db.StatusCategoryActive, false))
+	assert.NoError(t, CanStart(true, db.StatusCategoryNew, db.StatusCategoryDone, false))
+	assert.NoError(t, CanStart(false, db.StatusCategoryNew, db.StatusCategoryActive, false))
+}
+
+func TestAvailable(t *testing.T) {
+	var actions []Action
+	for _, tr := range Available(db.StatusCategoryActive) {
+		actions = append(actions, tr.Action)
+	}
+	assert.Equal(t, []Action{ActionStop, ActionComplete}, actions)
+}
+
+func TestCompletionDate(t *testing.T) {
+	now := time.Now()
+	completed := sql.NullTime{Time: now.Add(-time.Hour), Valid: true}
+
+	assert.Equal(t, sql.NullTime{Time: now, Valid: true}, CompletionDate(sql.NullTime{}, db.StatusCategoryActive, db.StatusCategoryDone, now))
+	assert.Equal(t, completed, CompletionDate(completed, db.StatusCategoryDone, db.StatusCategoryDone, now))
+	assert.Equal(t, sql.NullTime{}, CompletionDate(completed, db.StatusCategoryDone, db.StatusCategoryActive, now))
+	assert.Equal(t, sql.NullTime{}, CompletionDate(sql.NullTime{}, db.StatusCategoryNew, db.StatusCategoryActive, now))
+}
@@ -4,6 +4,10 @@ import (
 	"project-management-service/internal/app"
 )
 
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				Type "Bearer" followed by a space and the access token.
 func main() {
 	app.Run()
 }
\ No newline at end of file
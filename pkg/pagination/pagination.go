@@ -0,0 +1,128 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultLimit is used when the request does not specify a limit
+	DefaultLimit = 20
+	// MaxLimit is the largest page size a client can request
+	MaxLimit = 100
+)
+
+// Different types of error returned by the FromRequest function
+var (
+	ErrInvalidLimit  = errors.New("limit must be a number between 1 and 100")
+	ErrInvalidCursor = errors.New("cursor is invalid")
+)
+
+// Cursor points at the last row of a page by the values of the columns the
+// listing is sorted by. The sort is kept so that a cursor cannot be replayed
+// against a different order.
+type Cursor struct {
+	Sort   string        `json:"o"`
+	Values []interface{} `json:"v"`
+}
+
+// Encode returns the opaque representation of the cursor handed out to clients
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode parses a cursor previously produced by Encode. Numbers are kept as
+// json.Number so that large ids survive the round trip. Values other than
+// strings, numbers, booleans and null are rejected, a cursor is only ever
+// built from column values.
+func Decode(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil || c.Sort == "" || len(c.Values) == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	for _, v := range c.Values {
+		switch v.(type) {
+		case nil, string, json.Number, bool:
+		default:
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return &c, nil
+}
+
+// Page describes which slice of a result set the client asked for
+type Page struct {
+	Limit  int32
+	Sort   string
+	Cursor *Cursor
+}
+
+// FromRequest reads the limit and cursor query parameters for a listing
+// sorted by sort, rejecting cursors issued for another sort order or that do
+// not carry one value per sort column
+func FromRequest(r *http.Request, sort string) (Page, error) {
+	page := Page{Limit: DefaultLimit, Sort: sort}
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return page, ErrInvalidLimit
+		}
+		page.Limit = int32(limit)
+	}
+
+	if v := r.URL.Query().Get("cursor"); v != "" {
+		cursor, err := Decode(v)
+		if err != nil {
+			return page, err
+		}
+		if cursor.Sort != sort || len(cursor.Values) != len(strings.Split(sort, ",")) {
+			return page, ErrInvalidCursor
+		}
+		page.Cursor = cursor
+	}
+
+	return page, nil
+}
+
+// FetchLimit is the number of rows to query, one more than the page size
+// so that the presence of a next page can be detected
+func (p Page) FetchLimit() int32 {
+	return p.Limit + 1
+}
+
+// After returns the sort values of the row the page starts after, or nil
+// for the first page
+func (p Page) After() []interface{} {
+	if p.Cursor == nil {
+		return nil
+	}
+	return p.Cursor.Values
+}
+
+// Trim drops the extra row fetched by FetchLimit and returns the encoded
+// cursor of the next page, or an empty string on the last page
+func Trim[T any](items []T, p Page, values func(T) []interface{}) ([]T, string) {
+	if len(items) <= int(p.Limit) {
+		return items, ""
+	}
+
+	items = items[:p.Limit]
+	cursor := Cursor{Sort: p.Sort, Values: values(items[len(items)-1])}
+	return items, cursor.Encode()
+}
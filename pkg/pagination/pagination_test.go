@@ -0,0 +1,81 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	now := time.Now().UTC()
+
+	cursor, err := Decode(Cursor{Sort: "-priority,creation_date,id", Values: []interface{}{"high", now, int64(1) << 60}}.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, "-priority,creation_date,id", cursor.Sort)
+	require.Len(t, cursor.Values, 3)
+	assert.Equal(t, "high", cursor.Values[0])
+	assert.Equal(t, now.Format(time.RFC3339Nano), cursor.Values[1])
+	assert.Equal(t, json.Number("1152921504606846976"), cursor.Values[2])
+}
+
+func TestDecodeInvalidCursor(t *testing.T) {
+	for _, s := range []string{
+		"%%%",
+		"bm90IGpzb24",
+		"e30",
+		Cursor{Sort: "id"}.Encode(),
+		Cursor{Sort: "id", Values: []interface{}{map[string]interface{}{"id": 1}}}.Encode(),
+		Cursor{Sort: "id", Values: []interface{}{[]interface{}{1, 2}}}.Encode(),
+	} {
+		_, err := Decode(s)
+		assert.ErrorIs(t, err, ErrInvalidCursor, s)
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	page, err := FromRequest(httptest.NewRequest("GET", "/tasks", nil), "creation_date,id")
+	require.NoError(t, err)
+	assert.Equal(t, int32(DefaultLimit), page.Limit)
+	assert.Nil(t, page.Cursor)
+	assert.Nil(t, page.After())
+
+	cursor := Cursor{Sort: "full_name,id", Values: []interface{}{"Bob", 3}}.Encode()
+	page, err = FromRequest(httptest.NewRequest("GET", "/users?limit=5&cursor="+cursor, nil), "full_name,id")
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), page.Limit)
+	assert.Equal(t, int32(6), page.FetchLimit())
+	assert.Equal(t, []interface{}{"Bob", json.Number("3")}, page.After())
+
+	_, err = FromRequest(httptest.NewRequest("GET", "/users?cursor="+cursor, nil), "-full_name,id")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	short := Cursor{Sort: "full_name,id", Values: []interface{}{"Bob"}}.Encode()
+	_, err = FromRequest(httptest.NewRequest("GET", "/users?cursor="+short, nil), "full_name,id")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	for _, query := range []string{"limit=0", "limit=101", "limit=abc", "cursor=abc"} {
+		_, err = FromRequest(httptest.NewRequest("GET", "/tasks?"+query, nil), "id")
+		assert.Error(t, err, query)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	page := Page{Limit: 2, Sort: "id"}
+	values := func(id int64) []interface{} { return []interface{}{id} }
+
+	items, next := Trim([]int64{1, 2}, page, values)
+	assert.Equal(t, []int64{1, 2}, items)
+	assert.Empty(t, next)
+
+	items, next = Trim([]int64{1, 2, 3}, page, values)
+	assert.Equal(t, []int64{1, 2}, items)
+
+	decoded, err := Decode(next)
+	require.NoError(t, err)
+	assert.Equal(t, "id", decoded.Sort)
+	assert.Equal(t, []interface{}{json.Number("2")}, decoded.Values)
+}
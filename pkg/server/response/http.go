@@ -2,14 +2,19 @@ package response
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/render"
 )
 
 type Object struct {
-	Success bool   `json:"success"`
-	Message string `json:"message,omitempty"`
-	Data    any    `json:"data,omitempty"`
+	Success    bool   `json:"success"`
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Data       any    `json:"data,omitempty"`
+	Errors     any    `json:"errors,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 func OK(w http.ResponseWriter, r *http.Request, data any) {
@@ -22,35 +27,86 @@ func OK(w http.ResponseWriter, r *http.Request, data any) {
 	render.JSON(w, r, v)
 }
 
-func BadRequest(w http.ResponseWriter, r *http.Request, err error, data any) {
-	render.Status(r, http.StatusBadRequest)
+func Paginated(w http.ResponseWriter, r *http.Request, data any, nextCursor string) {
+	render.Status(r, http.StatusOK)
 
 	v := Object{
-		Success: false,
-		Data:    data,
-		Message: err.Error(),
+		Success:    true,
+		Data:       data,
+		NextCursor: nextCursor,
 	}
 	render.JSON(w, r, v)
 }
 
-func NotFound(w http.ResponseWriter, r *http.Request, err error) {
-	render.Status(r, http.StatusNotFound)
+// Error writes an error response with the given status. It is an Object by
+// default and RFC 7807 problem details when the request asked for them, see
+// ProblemDetails. The code, data and fields are optional.
+func Error(w http.ResponseWriter, r *http.Request, status int, code string, err error, data, fields any) {
+	if wantsProblem(r) {
+		writeProblem(w, newProblem(r, status, code, err, fields))
+		return
+	}
+
+	render.Status(r, status)
 
 	v := Object{
 		Success: false,
+		Code:    code,
 		Message: err.Error(),
+		Data:    data,
+		Errors:  fields,
 	}
 	render.JSON(w, r, v)
 }
 
-func InternalServerError(w http.ResponseWriter, r *http.Request, err error) {
-	render.Status(r, http.StatusInternalServerError)
+func BadRequest(w http.ResponseWriter, r *http.Request, err error, data any) {
+	Error(w, r, http.StatusBadRequest, "", err, data, nil)
+}
 
-	v := Object{
-		Success: false,
-		Message: err.Error(),
+func Unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	Error(w, r, http.StatusUnauthorized, "", err, nil, nil)
+}
+
+func Forbidden(w http.ResponseWriter, r *http.Request, err error) {
+	Error(w, r, http.StatusForbidden, "", err, nil, nil)
+}
+
+func NotFound(w http.ResponseWriter, r *http.Request, err error) {
+	Error(w, r, http.StatusNotFound, "", err, nil, nil)
+}
+
+func Conflict(w http.ResponseWriter, r *http.Request, err error, code string) {
+	Error(w, r, http.StatusConflict, code, err, nil, nil)
+}
+
+func PreconditionFailed(w http.ResponseWriter, r *http.Request, err error) {
+	Error(w, r, http.StatusPreconditionFailed, "", err, nil, nil)
+}
+
+func RequestEntityTooLarge(w http.ResponseWriter, r *http.Request, err error) {
+	Error(w, r, http.StatusRequestEntityTooLarge, "", err, nil, nil)
+}
+
+func UnsupportedMediaType(w http.ResponseWriter, r *http.Request, err error) {
+	Error(w, r, http.StatusUnsupportedMediaType, "", err, nil, nil)
+}
+
+func UnprocessableEntity(w http.ResponseWriter, r *http.Request, err error, code string, fields any) {
+	Error(w, r, http.StatusUnprocessableEntity, code, err, nil, fields)
+}
+
+// TooManyRequests rejects a request over a rate limit, telling the client
+// when it may retry
+func TooManyRequests(w http.ResponseWriter, r *http.Request, err error, retryAfter time.Duration) {
+	if retryAfter > 0 {
+		seconds := int((retryAfter + time.Second - 1) / time.Second)
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
 	}
-	render.JSON(w, r, v)
+	Error(w, r, http.StatusTooManyRequests, "", err, nil, nil)
+}
+
+func InternalServerError(w http.ResponseWriter, r *http.Request, err error) {
+	Error(w, r, http.StatusInternalServerError, "", err, nil, nil)
 }
 
 func NoContent(w http.ResponseWriter, r *http.Request) {
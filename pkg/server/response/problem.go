@@ -0,0 +1,89 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ContentTypeProblem is the media type of RFC 7807 problem details
+const ContentTypeProblem = "application/problem+json"
+
+// problemTypePrefix turns a machine-readable error code into a problem type URI
+const problemTypePrefix = "urn:project-management-service:problem:"
+
+// Problem is an RFC 7807 problem details object. Code and Errors are
+// extension members carrying the same information as in Object.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Code      string `json:"code,omitempty"`
+	Errors    any    `json:"errors,omitempty"`
+}
+
+type problemDetails struct{}
+
+// ProblemDetails is a middleware selecting the error format. When always is
+// set every error is written as problem+json, otherwise only for requests
+// that list application/problem+json in their Accept header.
+func ProblemDetails(always bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if always {
+				r = r.WithContext(context.WithValue(r.Context(), problemDetails{}, true))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// wantsProblem reports whether the error response should be problem+json
+func wantsProblem(r *http.Request) bool {
+	if always, _ := r.Context().Value(problemDetails{}).(bool); always {
+		return true
+	}
+
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err == nil && mediaType == ContentTypeProblem {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newProblem describes an error of the request as problem details
+func newProblem(r *http.Request, status int, code string, err error, fields any) Problem {
+	p := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Instance:  r.URL.RequestURI(),
+		RequestID: middleware.GetReqID(r.Context()),
+		Code:      code,
+		Errors:    fields,
+	}
+	if code != "" {
+		p.Type = problemTypePrefix + code
+	}
+	if err != nil {
+		p.Detail = err.Error()
+	}
+	return p
+}
+
+func writeProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
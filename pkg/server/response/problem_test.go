@@ -0,0 +1,73 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorDefaultsToObject(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/tasks/1", nil)
+
+	Conflict(w, r, errors.New("a record with this email already exists"), "already_exists")
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	var v Object
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&v))
+	assert.False(t, v.Success)
+	assert.Equal(t, "already_exists", v.Code)
+	assert.Equal(t, "a record with this email already exists", v.Message)
+}
+
+func TestErrorAsProblemWhenAccepted(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/tasks?x=1", nil)
+	r.Header.Set("Accept", "application/json, application/problem+json;q=0.9")
+
+	handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		UnprocessableEntity(w, r, errors.New("validation failed"), "validation_failed", []map[string]string{{"field": "title"}})
+	}))
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, ContentTypeProblem, w.Header().Get("Content-Type"))
+
+	var p Problem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+	assert.Equal(t, problemTypePrefix+"validation_failed", p.Type)
+	assert.Equal(t, "Unprocessable Entity", p.Title)
+	assert.Equal(t, http.StatusUnprocessableEntity, p.Status)
+	assert.Equal(t, "validation failed", p.Detail)
+	assert.Equal(t, "/tasks?x=1", p.Instance)
+	assert.NotEmpty(t, p.RequestID)
+	assert.NotNil(t, p.Errors)
+}
+
+func TestProblemDetailsAlways(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	handler := ProblemDetails(true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		TooManyRequests(w, r, errors.New("slow down"), 1500*time.Millisecond)
+	}))
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, ContentTypeProblem, w.Header().Get("Content-Type"))
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+
+	var p Problem
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+	assert.Equal(t, "about:blank", p.Type)
+	assert.Equal(t, "Too Many Requests", p.Title)
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Drivers of the blob stores that can be selected in the configuration
+const (
+	DriverLocal = "local"
+)
+
+// Errors returned by blob stores
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+	ErrInvalidKey   = errors.New("invalid blob key")
+)
+
+// BlobStore is an interface for storing file contents outside the database
+type BlobStore interface {
+	// Put stores the content read from r under key and returns its size
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+
+	// Open returns the content stored under key, the reader is seekable so
+	// that parts of the content can be served
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+
+	// Delete removes the content stored under key, a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStore creates the blob store of the given driver, location is the
+// driver specific place the blobs are kept, a directory for local storage
+func NewBlobStore(driver, location string) (BlobStore, error) {
+	switch driver {
+	case DriverLocal, "":
+		return NewLocalStore(location)
+	default:
+		return nil, fmt.Errorf("unknown blob store driver %q", driver)
+	}
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore keeps blobs as files below a directory of the local filesystem
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore creates a LocalStore, creating its directory when missing
+func NewLocalStore(dir string) (BlobStore, error) {
+	if dir == "" {
+		return nil, errors.New("the blob directory is not configured")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("could not create blob directory: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+// Put writes the content to a temporary file first and renames it once
+// complete, so readers never see a partial blob
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, contextReader{ctx, r})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return size, os.Rename(tmp.Name(), path)
+}
+
+// Open opens the file of the blob
+func (s *LocalStore) Open(_ context.Context, key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+	return f, err
+}
+
+// Delete removes the file of the blob
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps a key to a file below the store directory, keys are slash
+// separated and may not leave the directory
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || !fs.ValidPath(key) || strings.Contains(key, `\`) {
+		return "", fmt.Errorf("%w %q", ErrInvalidKey, key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// contextReader stops reading once the context is done, so that an
+// abandoned upload does not keep writing
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewBlobStore(DriverLocal, t.TempDir())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	size, err := store.Put(ctx, "tasks/1/report", strings.NewReader("quarterly report"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(16), size)
+
+	blob, err := store.Open(ctx, "tasks/1/report")
+	require.NoError(t, err)
+
+	_, err = blob.Seek(10, io.SeekStart)
+	require.NoError(t, err)
+	rest, err := io.ReadAll(blob)
+	require.NoError(t, err)
+	assert.Equal(t, "report", string(rest))
+	require.NoError(t, blob.Close())
+
+	require.NoError(t, store.Delete(ctx, "tasks/1/report"))
+	require.NoError(t, store.Delete(ctx, "tasks/1/report"))
+
+	_, err = store.Open(ctx, "tasks/1/report")
+	assert.ErrorIs(t, err, ErrBlobNotFound)
+}
+
+func TestLocalStoreRejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../secret", "/etc/passwd", "tasks/../../secret"} {
+		_, err := store.Put(context.Background(), key, strings.NewReader("x"))
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+func TestUnknownDriver(t *testing.T) {
+	_, err := NewBlobStore("ftp", t.TempDir())
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"project-management-service/util"
+)
+
+func TestJWTMaker(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	userID := int64(42)
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(userID, duration)
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotNil(t, payload)
+
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, payload.UserID)
+	assert.WithinDuration(t, issuedAt, payload.IssuedAt.Time, time.Second)
+	assert.WithinDuration(t, expiredAt, payload.ExpiresAt.Time, time.Second)
+}
+
+func TestExpiredJWTToken(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(42, -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	assert.ErrorIs(t, err, ErrExpiredToken)
+	assert.Nil(t, payload)
+}
+
+func TestInvalidJWTTokenAlgNone(t *testing.T) {
+	payload := NewPayload(42, time.Minute)
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
+	token, err := jwtToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Nil(t, payload)
+}
+
+func TestJWTTokenWrongKey(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(42, time.Minute)
+	require.NoError(t, err)
+
+	otherMaker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	payload, err := otherMaker.VerifyToken(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Nil(t, payload)
+}
+
+func TestNewJWTMakerShortKey(t *testing.T) {
+	_, err := NewJWTMaker(util.RandomString(16))
+	assert.Error(t, err)
+}
@@ -0,0 +1,35 @@
+package token
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Different types of error returned by the VerifyToken function
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// Payload contains the payload data of the token
+type Payload struct {
+	UserID int64 `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// NewPayload creates a new token payload with a specific user and duration
+func NewPayload(userID int64, duration time.Duration) *Payload {
+	now := time.Now()
+
+	return &Payload{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatInt(userID, 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+		},
+	}
+}